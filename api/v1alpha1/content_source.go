@@ -0,0 +1,46 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+// ContentFrom references content that is held outside of the resource.
+// Exactly one of the references should be set.
+type ContentFrom struct {
+	ConfigMapKeyRef *ContentKeyRef     `json:"config_map_key_ref,omitempty"`
+	SecretKeyRef    *ContentKeyRef     `json:"secret_key_ref,omitempty"`
+	HTTP            *ContentHTTPSource `json:"http,omitempty"`
+}
+
+// ContentKeyRef refers to a key in a k8s config map or secret
+type ContentKeyRef struct {
+	Name string `json:"name,omitempty"`
+	Key  string `json:"key,omitempty"`
+}
+
+// ContentHTTPSource refers to content downloaded from an HTTP(S) URL.
+// When SHA256 is set the downloaded content is verified against it.
+type ContentHTTPSource struct {
+	URL    string `json:"url,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+}
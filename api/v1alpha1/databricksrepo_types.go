@@ -0,0 +1,177 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabricksRepoUpdateAnnotation requests a pull of the latest commit whenever its value changes
+const DatabricksRepoUpdateAnnotation = "databricks.microsoft.com/repo-update"
+
+// DatabricksRepoSpec defines the desired state of DatabricksRepo
+type DatabricksRepoSpec struct {
+	// URL of the git repository
+	URL string `json:"url,omitempty"`
+	// Provider is the git provider, such as gitHub, gitLab, bitbucketCloud or azureDevOpsServices
+	Provider string `json:"provider,omitempty"`
+	// Path is the workspace path of the repo, such as /Repos/<folder>/<name>
+	Path string `json:"path,omitempty"`
+	// Branch to check out. Only one of Branch and Tag can be set.
+	Branch string `json:"branch,omitempty"`
+	// Tag to check out. Only one of Branch and Tag can be set.
+	Tag string `json:"tag,omitempty"`
+	// UpdateIntervalSeconds is the time between pulls of the latest commit of the branch.
+	// Periodic pulls are disabled when it is 0.
+	UpdateIntervalSeconds int32 `json:"update_interval_seconds,omitempty"`
+}
+
+// DatabricksRepoStatus defines the observed state of DatabricksRepo
+type DatabricksRepoStatus struct {
+	ID           int64  `json:"id,omitempty"`
+	URL          string `json:"url,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+	HeadCommitID string `json:"head_commit_id,omitempty"`
+	// ObservedUpdateToken is the value of the update annotation when the repo was last updated
+	ObservedUpdateToken string       `json:"observed_update_token,omitempty"`
+	LastUpdatedTime     *metav1.Time `json:"last_updated_time,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=databricksrepos
+
+// DatabricksRepo is the Schema for the databricksrepos API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Path",type="string",JSONPath=".status.path"
+// +kubebuilder:printcolumn:name="Branch",type="string",JSONPath=".status.branch"
+// +kubebuilder:printcolumn:name="Tag",type="string",JSONPath=".status.tag"
+// +kubebuilder:printcolumn:name="Commit",type="string",JSONPath=".status.head_commit_id"
+type DatabricksRepo struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   *DatabricksRepoSpec   `json:"spec,omitempty"`
+	Status *DatabricksRepoStatus `json:"status,omitempty"`
+}
+
+// IsBeingDeleted returns true if a deletion timestamp is set
+func (repo *DatabricksRepo) IsBeingDeleted() bool {
+	return !repo.ObjectMeta.DeletionTimestamp.IsZero()
+}
+
+// IsSubmitted returns true if the repo has been created in DataBricks
+func (repo *DatabricksRepo) IsSubmitted() bool {
+	return repo.Status != nil && repo.Status.ID != 0
+}
+
+// IsRecreateRequired returns true if the repo was created with a URL or path that has since
+// changed, which the Repos API cannot update in place
+func (repo *DatabricksRepo) IsRecreateRequired() bool {
+	if !repo.IsSubmitted() {
+		return false
+	}
+	if repo.Spec.URL != repo.Status.URL {
+		return true
+	}
+	return repo.Spec.Path != "" && repo.Spec.Path != repo.Status.Path
+}
+
+// IsCheckedOut returns true if the branch or tag in the spec is the one checked out
+func (repo *DatabricksRepo) IsCheckedOut() bool {
+	if !repo.IsSubmitted() {
+		return false
+	}
+	if repo.Spec.Tag != "" {
+		return repo.Spec.Tag == repo.Status.Tag
+	}
+	if repo.Status.Tag != "" {
+		return false
+	}
+	return repo.Spec.Branch == "" || repo.Spec.Branch == repo.Status.Branch
+}
+
+// GetUpdateToken returns the value of the update annotation
+func (repo *DatabricksRepo) GetUpdateToken() string {
+	return repo.GetAnnotations()[DatabricksRepoUpdateAnnotation]
+}
+
+// IsUpdateRequested returns true if the update annotation has changed since the repo was last updated
+func (repo *DatabricksRepo) IsUpdateRequested() bool {
+	if !repo.IsSubmitted() {
+		return false
+	}
+	return repo.GetUpdateToken() != repo.Status.ObservedUpdateToken
+}
+
+// NextUpdate returns how long to wait from now until the next periodic pull is due, or 0 if it
+// is due already. It returns false if periodic pulls are disabled, which is always the case for tags.
+func (repo *DatabricksRepo) NextUpdate(now time.Time) (time.Duration, bool) {
+	if repo.Spec.UpdateIntervalSeconds <= 0 || repo.Spec.Tag != "" {
+		return 0, false
+	}
+	if !repo.IsSubmitted() || repo.Status.LastUpdatedTime == nil {
+		return 0, true
+	}
+	next := repo.Status.LastUpdatedTime.Add(time.Duration(repo.Spec.UpdateIntervalSeconds) * time.Second)
+	if !next.After(now) {
+		return 0, true
+	}
+	return next.Sub(now), true
+}
+
+// DatabricksRepoFinalizerName is the name of the databricks repo finalizer
+const DatabricksRepoFinalizerName = "databricksrepo.finalizers.databricks.microsoft.com"
+
+// HasFinalizer returns true if the item has the specified finalizer
+func (repo *DatabricksRepo) HasFinalizer(finalizerName string) bool {
+	return containsString(repo.ObjectMeta.Finalizers, finalizerName)
+}
+
+// AddFinalizer adds the specified finalizer
+func (repo *DatabricksRepo) AddFinalizer(finalizerName string) {
+	repo.ObjectMeta.Finalizers = append(repo.ObjectMeta.Finalizers, finalizerName)
+}
+
+// RemoveFinalizer removes the specified finalizer
+func (repo *DatabricksRepo) RemoveFinalizer(finalizerName string) {
+	repo.ObjectMeta.Finalizers = removeString(repo.ObjectMeta.Finalizers, finalizerName)
+}
+
+// +kubebuilder:object:root=true
+
+// DatabricksRepoList contains a list of DatabricksRepo
+type DatabricksRepoList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabricksRepo `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabricksRepo{}, &DatabricksRepoList{})
+}
@@ -0,0 +1,178 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// These tests are written in BDD-style using Ginkgo framework. Refer to
+// http://onsi.github.io/ginkgo to learn more.
+
+var _ = Describe("DatabricksRepo", func() {
+	var (
+		key              types.NamespacedName
+		created, fetched *DatabricksRepo
+	)
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	// Add Tests for OpenAPI validation (or additional CRD features) specified in
+	// your API definition.
+	// Avoid adding tests for vanilla CRUD operations because they would
+	// test Kubernetes API server, which isn't the goal here.
+	Context("Create API", func() {
+
+		It("should create an object successfully", func() {
+
+			key = types.NamespacedName{
+				Name:      "foo" + RandomString(5),
+				Namespace: "default",
+			}
+			created = &DatabricksRepo{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				}}
+
+			By("creating an API obj")
+			Expect(k8sClient.Create(context.TODO(), created)).To(Succeed())
+
+			fetched = &DatabricksRepo{}
+			Expect(k8sClient.Get(context.TODO(), key, fetched)).To(Succeed())
+			Expect(fetched).To(Equal(created))
+
+			By("deleting the created object")
+			Expect(k8sClient.Delete(context.TODO(), created)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, created)).ToNot(Succeed())
+		})
+
+		It("should correctly handle finalizers", func() {
+			repo := &DatabricksRepo{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: &metav1.Time{
+						Time: time.Now(),
+					},
+				},
+			}
+			Expect(repo.IsBeingDeleted()).To(BeTrue())
+
+			repo.AddFinalizer(DatabricksRepoFinalizerName)
+			Expect(len(repo.GetFinalizers())).To(Equal(1))
+			Expect(repo.HasFinalizer(DatabricksRepoFinalizerName)).To(BeTrue())
+
+			repo.RemoveFinalizer(DatabricksRepoFinalizerName)
+			Expect(len(repo.GetFinalizers())).To(Equal(0))
+			Expect(repo.HasFinalizer(DatabricksRepoFinalizerName)).To(BeFalse())
+		})
+
+		It("should correctly handle checkouts", func() {
+			repo := &DatabricksRepo{
+				Spec: &DatabricksRepoSpec{URL: "https://github.com/org/repo", Path: "/Repos/ci/repo"},
+			}
+			Expect(repo.IsSubmitted()).To(BeFalse())
+			Expect(repo.IsCheckedOut()).To(BeFalse())
+
+			repo.Status = &DatabricksRepoStatus{ID: 1, URL: "https://github.com/org/repo", Path: "/Repos/ci/repo", Branch: "main"}
+			Expect(repo.IsSubmitted()).To(BeTrue())
+			Expect(repo.IsCheckedOut()).To(BeTrue())
+
+			repo.Spec.Branch = "release"
+			Expect(repo.IsCheckedOut()).To(BeFalse())
+
+			repo.Spec.Branch = ""
+			repo.Spec.Tag = "v1.0.0"
+			Expect(repo.IsCheckedOut()).To(BeFalse())
+
+			repo.Status.Tag = "v1.0.0"
+			Expect(repo.IsCheckedOut()).To(BeTrue())
+
+			repo.Spec.Tag = ""
+			Expect(repo.IsCheckedOut()).To(BeFalse())
+		})
+
+		It("should correctly handle recreation", func() {
+			repo := &DatabricksRepo{
+				Spec:   &DatabricksRepoSpec{URL: "https://github.com/org/repo"},
+				Status: &DatabricksRepoStatus{ID: 1, URL: "https://github.com/org/repo", Path: "/Repos/ci/repo"},
+			}
+			Expect(repo.IsRecreateRequired()).To(BeFalse())
+
+			repo.Spec.Path = "/Repos/ci/repo"
+			Expect(repo.IsRecreateRequired()).To(BeFalse())
+
+			repo.Spec.Path = "/Repos/ci/other"
+			Expect(repo.IsRecreateRequired()).To(BeTrue())
+
+			repo.Spec.Path = ""
+			repo.Spec.URL = "https://github.com/org/other"
+			Expect(repo.IsRecreateRequired()).To(BeTrue())
+		})
+
+		It("should correctly handle update requests", func() {
+			now := time.Now()
+			repo := &DatabricksRepo{
+				Spec:   &DatabricksRepoSpec{},
+				Status: &DatabricksRepoStatus{ID: 1, LastUpdatedTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+			}
+			Expect(repo.IsUpdateRequested()).To(BeFalse())
+
+			repo.SetAnnotations(map[string]string{DatabricksRepoUpdateAnnotation: "1"})
+			Expect(repo.IsUpdateRequested()).To(BeTrue())
+			repo.Status.ObservedUpdateToken = "1"
+			Expect(repo.IsUpdateRequested()).To(BeFalse())
+
+			_, periodic := repo.NextUpdate(now)
+			Expect(periodic).To(BeFalse())
+
+			repo.Spec.UpdateIntervalSeconds = 300
+			wait, periodic := repo.NextUpdate(now)
+			Expect(periodic).To(BeTrue())
+			Expect(wait).To(Equal(4 * time.Minute))
+
+			repo.Spec.UpdateIntervalSeconds = 30
+			wait, _ = repo.NextUpdate(now)
+			Expect(wait).To(Equal(time.Duration(0)))
+
+			repo.Spec.Tag = "v1.0.0"
+			_, periodic = repo.NextUpdate(now)
+			Expect(periodic).To(BeFalse())
+		})
+	})
+
+})
@@ -25,9 +25,7 @@ SOFTWARE.
 package v1alpha1
 
 import (
-	"crypto/sha1"
 	"encoding/base64"
-	"fmt"
 
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -37,12 +35,23 @@ import (
 type DbfsBlockSpec struct {
 	Path string `json:"path,omitempty"`
 	Data string `json:"data,omitempty"`
+	// DataFrom is used instead of Data to read the content from a config map, secret or URL
+	DataFrom *ContentFrom `json:"data_from,omitempty"`
+	// DriftDetection periodically verifies that the file in DBFS has not been changed or deleted
+	DriftDetection *DriftDetection `json:"drift_detection,omitempty"`
 }
 
 // DbfsBlockStatus defines the observed state of DbfsBlock
 type DbfsBlockStatus struct {
 	FileInfo *dbmodels.FileInfo `json:"file_info,omitempty"`
 	FileHash string             `json:"file_hash,omitempty"`
+	// SourceVersion identifies the version of the DataFrom source that was last uploaded
+	SourceVersion string `json:"source_version,omitempty"`
+	// LastVerifiedTime is when the file in DBFS was last checked for drift
+	LastVerifiedTime *metav1.Time `json:"last_verified_time,omitempty"`
+	// LastDriftTime is when the file in DBFS was last found to differ from the last upload
+	LastDriftTime *metav1.Time `json:"last_drift_time,omitempty"`
+	Conditions    []Condition  `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -52,6 +61,7 @@ type DbfsBlockStatus struct {
 // +kubebuilder:printcolumn:name="SHA1SUM",type="string",JSONPath=".status.file_hash"
 // +kubebuilder:printcolumn:name="Path",type="string",JSONPath=".status.file_info.path"
 // +kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".status.file_info.file_size"
+// +kubebuilder:printcolumn:name="InSync",type="string",JSONPath=`.status.conditions[?(@.type=="InSync")].status`,priority=1
 type DbfsBlock struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -75,6 +85,11 @@ func (dbfsBlock *DbfsBlock) IsSubmitted() bool {
 	return true
 }
 
+// HasDataFrom returns true if the data is read from an external source rather than the spec
+func (dbfsBlock *DbfsBlock) HasDataFrom() bool {
+	return dbfsBlock.Spec != nil && dbfsBlock.Spec.DataFrom != nil
+}
+
 // IsUpToDate tells you whether the data is up-to-date with the status
 func (dbfsBlock *DbfsBlock) IsUpToDate() bool {
 	if dbfsBlock.Status == nil {
@@ -84,19 +99,29 @@ func (dbfsBlock *DbfsBlock) IsUpToDate() bool {
 	return h == dbfsBlock.Status.FileHash
 }
 
+// IsSourceUpToDate tells you whether the DataFrom source version is the one last uploaded
+func (dbfsBlock *DbfsBlock) IsSourceUpToDate(sourceVersion string) bool {
+	if dbfsBlock.Status == nil {
+		return false
+	}
+	return sourceVersion != "" && sourceVersion == dbfsBlock.Status.SourceVersion
+}
+
+// GetDriftDetection returns the drift detection settings, or nil if drift is not detected
+func (dbfsBlock *DbfsBlock) GetDriftDetection() *DriftDetection {
+	if dbfsBlock.Spec == nil {
+		return nil
+	}
+	return dbfsBlock.Spec.DriftDetection
+}
+
 // GetHash returns the sha1 hash of the decoded data attribute
 func (dbfsBlock *DbfsBlock) GetHash() string {
 	data, err := base64.StdEncoding.DecodeString(dbfsBlock.Spec.Data)
 	if err != nil {
 		return ""
 	}
-	h := sha1.New()
-	_, err = h.Write(data)
-	if err != nil {
-		return ""
-	}
-	bs := h.Sum(nil)
-	return fmt.Sprintf("%x", bs)
+	return HashContent(data)
 }
 
 // DbfsBlockFinalizerName is the name of the dbfs block finalizer
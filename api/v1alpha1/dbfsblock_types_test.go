@@ -151,6 +151,26 @@ var _ = Describe("DbfsBlock", func() {
 			}
 			Expect(dbfsBlockError.GetHash()).To(Equal(""))
 		})
+
+		It("should correctly handle data source version", func() {
+			dbfsBlock := &DbfsBlock{
+				Spec: &DbfsBlockSpec{
+					DataFrom: &ContentFrom{
+						ConfigMapKeyRef: &ContentKeyRef{Name: "config", Key: "data"},
+					},
+				},
+			}
+
+			Expect(dbfsBlock.HasDataFrom()).To(BeTrue())
+			Expect(dbfsBlock.IsSourceUpToDate("configmap/config/data@1")).To(BeFalse())
+
+			dbfsBlock.Status = &DbfsBlockStatus{
+				SourceVersion: "configmap/config/data@1",
+			}
+			Expect(dbfsBlock.IsSourceUpToDate("configmap/config/data@1")).To(BeTrue())
+			Expect(dbfsBlock.IsSourceUpToDate("configmap/config/data@2")).To(BeFalse())
+			Expect(dbfsBlock.IsSourceUpToDate("")).To(BeFalse())
+		})
 	})
 
 })
@@ -0,0 +1,142 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DbfsDirectorySpec defines the desired state of DbfsDirectory
+type DbfsDirectorySpec struct {
+	// Path is the DBFS directory the files are synced into
+	Path string `json:"path,omitempty"`
+	// ConfigMaps lists config maps whose keys are each synced as a file
+	ConfigMaps []DbfsDirectoryConfigMapSource `json:"config_maps,omitempty"`
+	// Files lists individual files to sync
+	Files []DbfsDirectoryFile `json:"files,omitempty"`
+}
+
+// DbfsDirectoryConfigMapSource syncs every key of a config map as a file
+type DbfsDirectoryConfigMapSource struct {
+	Name string `json:"name,omitempty"`
+	// Prefix is prepended to each key to give the file path relative to the directory
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// DbfsDirectoryFile is a single file synced into the directory
+type DbfsDirectoryFile struct {
+	// Path of the file relative to the directory
+	Path     string       `json:"path,omitempty"`
+	Data     string       `json:"data,omitempty"`
+	DataFrom *ContentFrom `json:"data_from,omitempty"`
+}
+
+// DbfsDirectoryStatus defines the observed state of DbfsDirectory
+type DbfsDirectoryStatus struct {
+	Files          []DbfsDirectoryFileStatus `json:"files,omitempty"`
+	SyncedFiles    int32                     `json:"synced_files,omitempty"`
+	FailedFiles    int32                     `json:"failed_files,omitempty"`
+	LastSyncedTime *metav1.Time              `json:"last_synced_time,omitempty"`
+}
+
+// DbfsDirectoryFileStatus reports the last sync of a single file
+type DbfsDirectoryFileStatus struct {
+	// Path is the full DBFS path of the file
+	Path           string       `json:"path,omitempty"`
+	FileHash       string       `json:"file_hash,omitempty"`
+	FileSize       int64        `json:"file_size,omitempty"`
+	SourceVersion  string       `json:"source_version,omitempty"`
+	LastSyncedTime *metav1.Time `json:"last_synced_time,omitempty"`
+	Error          string       `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DbfsDirectory is the Schema for the dbfsdirectories API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Path",type="string",JSONPath=".spec.path"
+// +kubebuilder:printcolumn:name="Synced",type="integer",JSONPath=".status.synced_files"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed_files"
+type DbfsDirectory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   *DbfsDirectorySpec   `json:"spec,omitempty"`
+	Status *DbfsDirectoryStatus `json:"status,omitempty"`
+}
+
+// IsBeingDeleted returns true if a deletion timestamp is set
+func (dbfsDirectory *DbfsDirectory) IsBeingDeleted() bool {
+	return !dbfsDirectory.ObjectMeta.DeletionTimestamp.IsZero()
+}
+
+// IsSubmitted returns true if the directory has been synced to DataBricks at least once
+func (dbfsDirectory *DbfsDirectory) IsSubmitted() bool {
+	return dbfsDirectory.Status != nil && dbfsDirectory.Status.LastSyncedTime != nil
+}
+
+// GetFileStatus returns the status of the file at the specified DBFS path, or nil if it has not been synced
+func (dbfsDirectory *DbfsDirectory) GetFileStatus(path string) *DbfsDirectoryFileStatus {
+	if dbfsDirectory.Status == nil {
+		return nil
+	}
+	for i := range dbfsDirectory.Status.Files {
+		if dbfsDirectory.Status.Files[i].Path == path {
+			return &dbfsDirectory.Status.Files[i]
+		}
+	}
+	return nil
+}
+
+// DbfsDirectoryFinalizerName is the name of the dbfs directory finalizer
+const DbfsDirectoryFinalizerName = "dbfsdirectory.finalizers.databricks.microsoft.com"
+
+// HasFinalizer returns true if the item has the specified finalizer
+func (dbfsDirectory *DbfsDirectory) HasFinalizer(finalizerName string) bool {
+	return containsString(dbfsDirectory.ObjectMeta.Finalizers, finalizerName)
+}
+
+// AddFinalizer adds the specified finalizer
+func (dbfsDirectory *DbfsDirectory) AddFinalizer(finalizerName string) {
+	dbfsDirectory.ObjectMeta.Finalizers = append(dbfsDirectory.ObjectMeta.Finalizers, finalizerName)
+}
+
+// RemoveFinalizer removes the specified finalizer
+func (dbfsDirectory *DbfsDirectory) RemoveFinalizer(finalizerName string) {
+	dbfsDirectory.ObjectMeta.Finalizers = removeString(dbfsDirectory.ObjectMeta.Finalizers, finalizerName)
+}
+
+// +kubebuilder:object:root=true
+
+// DbfsDirectoryList contains a list of DbfsDirectory
+type DbfsDirectoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DbfsDirectory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DbfsDirectory{}, &DbfsDirectoryList{})
+}
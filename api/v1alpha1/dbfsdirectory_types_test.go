@@ -0,0 +1,133 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// These tests are written in BDD-style using Ginkgo framework. Refer to
+// http://onsi.github.io/ginkgo to learn more.
+
+var _ = Describe("DbfsDirectory", func() {
+	var (
+		key              types.NamespacedName
+		created, fetched *DbfsDirectory
+	)
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	// Add Tests for OpenAPI validation (or additional CRD features) specified in
+	// your API definition.
+	// Avoid adding tests for vanilla CRUD operations because they would
+	// test Kubernetes API server, which isn't the goal here.
+	Context("Create API", func() {
+
+		It("should create an object successfully", func() {
+
+			key = types.NamespacedName{
+				Name:      "foo" + RandomString(5),
+				Namespace: "default",
+			}
+			created = &DbfsDirectory{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				}}
+
+			By("creating an API obj")
+			Expect(k8sClient.Create(context.TODO(), created)).To(Succeed())
+
+			fetched = &DbfsDirectory{}
+			Expect(k8sClient.Get(context.TODO(), key, fetched)).To(Succeed())
+			Expect(fetched).To(Equal(created))
+
+			By("deleting the created object")
+			Expect(k8sClient.Delete(context.TODO(), created)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, created)).ToNot(Succeed())
+		})
+
+		It("should correctly handle isSubmitted", func() {
+			dbfsDirectory := &DbfsDirectory{}
+			Expect(dbfsDirectory.IsSubmitted()).To(BeFalse())
+
+			dbfsDirectory.Status = &DbfsDirectoryStatus{}
+			Expect(dbfsDirectory.IsSubmitted()).To(BeFalse())
+
+			dbfsDirectory.Status.LastSyncedTime = &metav1.Time{Time: time.Now()}
+			Expect(dbfsDirectory.IsSubmitted()).To(BeTrue())
+		})
+
+		It("should correctly handle finalizers", func() {
+			dbfsDirectory := &DbfsDirectory{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: &metav1.Time{
+						Time: time.Now(),
+					},
+				},
+			}
+			Expect(dbfsDirectory.IsBeingDeleted()).To(BeTrue())
+
+			dbfsDirectory.AddFinalizer(DbfsDirectoryFinalizerName)
+			Expect(len(dbfsDirectory.GetFinalizers())).To(Equal(1))
+			Expect(dbfsDirectory.HasFinalizer(DbfsDirectoryFinalizerName)).To(BeTrue())
+
+			dbfsDirectory.RemoveFinalizer(DbfsDirectoryFinalizerName)
+			Expect(len(dbfsDirectory.GetFinalizers())).To(Equal(0))
+			Expect(dbfsDirectory.HasFinalizer(DbfsDirectoryFinalizerName)).To(BeFalse())
+		})
+
+		It("should correctly handle file status", func() {
+			dbfsDirectory := &DbfsDirectory{}
+			Expect(dbfsDirectory.GetFileStatus("/dir/a.py")).To(BeNil())
+
+			dbfsDirectory.Status = &DbfsDirectoryStatus{
+				Files: []DbfsDirectoryFileStatus{
+					{Path: "/dir/a.py", FileHash: "a"},
+					{Path: "/dir/b.py", FileHash: "b"},
+				},
+			}
+			Expect(dbfsDirectory.GetFileStatus("/dir/b.py").FileHash).To(Equal("b"))
+			Expect(dbfsDirectory.GetFileStatus("/dir/c.py")).To(BeNil())
+
+			dbfsDirectory.GetFileStatus("/dir/a.py").Error = "failed"
+			Expect(dbfsDirectory.Status.Files[0].Error).To(Equal("failed"))
+		})
+	})
+
+})
@@ -25,6 +25,8 @@ SOFTWARE.
 package v1alpha1
 
 import (
+	"fmt"
+
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -33,6 +35,38 @@ import (
 type DjobStatus struct {
 	JobStatus  *dbmodels.Job  `json:"job_status,omitempty"`
 	Last10Runs []dbmodels.Run `json:"last_10_runs,omitempty"`
+	// Tasks and JobClusters are the settings of a multi-task job as returned by Jobs API 2.1,
+	// which job_status.settings has no fields for
+	Tasks       []JobTaskSettings `json:"tasks,omitempty"`
+	JobClusters []JobCluster      `json:"job_clusters,omitempty"`
+	// TaskRuns is the state of each task in the latest run of a multi-task job
+	TaskRuns []DjobTaskRun `json:"task_runs,omitempty"`
+	// LastTriggeredRun is the Run created for the last spec.run_now trigger
+	LastTriggeredRun *DjobTriggeredRun `json:"last_triggered_run,omitempty"`
+	// LastMirroredRunID is the latest run of the job considered by spec.mirror_runs,
+	// older runs are not mirrored again once their Run is deleted
+	LastMirroredRunID int64 `json:"last_mirrored_run_id,omitempty"`
+}
+
+// DjobTriggeredRun records a Run created through spec.run_now
+type DjobTriggeredRun struct {
+	Trigger     int32       `json:"trigger"`
+	RunName     string      `json:"run_name"`
+	TriggerTime metav1.Time `json:"trigger_time,omitempty"`
+	// Phase is the phase of the Run as last seen by the Djob controller
+	Phase RunPhase `json:"phase,omitempty"`
+}
+
+// DjobLabel is set on the Runs created for a Djob to the name of the Djob
+const DjobLabel = "databricks.microsoft.com/djob"
+
+// DjobTaskRun is the state of a single task within a run of a multi-task job
+type DjobTaskRun struct {
+	TaskKey   string             `json:"task_key"`
+	RunID     int64              `json:"run_id,omitempty"`
+	State     *dbmodels.RunState `json:"state,omitempty"`
+	StartTime int64              `json:"start_time,omitempty"`
+	EndTime   int64              `json:"end_time,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -40,6 +74,7 @@ type DjobStatus struct {
 // Djob is the Schema for the djobs API
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="JobID",type="integer",JSONPath=".status.job_status.job_id"
+// +kubebuilder:printcolumn:name="LastRun",type="string",JSONPath=".status.last_triggered_run.run_name",priority=1
 type Djob struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -61,6 +96,27 @@ func (djob *Djob) IsSubmitted() bool {
 	return djob.Status.JobStatus.JobID > 0
 }
 
+// IsRunNowRequested returns true if spec.run_now.trigger was set or bumped since the last triggered run
+func (djob *Djob) IsRunNowRequested() bool {
+	if djob.Spec == nil || djob.Spec.RunNow == nil || djob.Spec.RunNow.Trigger == 0 {
+		return false
+	}
+	if djob.Status == nil || djob.Status.LastTriggeredRun == nil {
+		return true
+	}
+	return djob.Status.LastTriggeredRun.Trigger != djob.Spec.RunNow.Trigger
+}
+
+// GetTriggeredRunName returns the name of the Run created for the trigger
+func (djob *Djob) GetTriggeredRunName(trigger int32) string {
+	return fmt.Sprintf("%s-run-%d", djob.GetName(), trigger)
+}
+
+// GetMirroredRunName returns the name of the Run that mirrors the DataBricks run
+func (djob *Djob) GetMirroredRunName(runID int64) string {
+	return fmt.Sprintf("%s-%d", djob.GetName(), runID)
+}
+
 // DjobFinalizerName is the name of the djob finalizer
 const DjobFinalizerName = "djob.finalizers.databricks.microsoft.com"
 
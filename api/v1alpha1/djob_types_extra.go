@@ -25,6 +25,8 @@ SOFTWARE.
 package v1alpha1
 
 import (
+	"fmt"
+
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
 )
 
@@ -48,6 +50,115 @@ type JobSettings struct {
 	RetryOnTimeout         bool                            `json:"retry_on_timeout,omitempty" url:"retry_on_timeout,omitempty"`
 	Schedule               *dbmodels.CronSchedule          `json:"schedule,omitempty" url:"schedule,omitempty"`
 	MaxConcurrentRuns      int32                           `json:"max_concurrent_runs,omitempty" url:"max_concurrent_runs,omitempty"`
+	// Tasks turns the job into a multi-task job managed through Jobs API 2.1.
+	// It cannot be combined with the single task fields above.
+	Tasks []JobTaskSettings `json:"tasks,omitempty" url:"tasks,omitempty"`
+	// JobClusters are clusters that tasks of a multi-task job can share via job_cluster_key
+	JobClusters []JobCluster `json:"job_clusters,omitempty" url:"job_clusters,omitempty"`
+	// RunNow creates a Run of the job whenever its trigger is bumped. It is not part of the job in DataBricks.
+	RunNow *JobRunNow `json:"run_now,omitempty"`
+	// MirrorRuns creates a read-only Run for every run of the job that is not tracked by a Run yet,
+	// such as the runs started by the schedule or from the workspace. When it is turned on, only the
+	// latest 25 runs are mirrored rather than the whole history of the job. It cannot be combined with tasks.
+	MirrorRuns bool `json:"mirror_runs,omitempty"`
+}
+
+// JobRunNow triggers runs of a Djob from its spec
+type JobRunNow struct {
+	// Trigger creates a Run of the job when it is set or bumped
+	Trigger int32 `json:"trigger,omitempty"`
+	// Parameters override the parameters of the job for the triggered run
+	Parameters *dbmodels.RunParameters `json:"parameters,omitempty"`
+}
+
+// JobTaskSettings is a single task of a multi-task job.
+// Like JobSettings it allows referencing a Dcluster with ExistingClusterName.
+type JobTaskSettings struct {
+	TaskKey                string                    `json:"task_key"`
+	Description            string                    `json:"description,omitempty"`
+	DependsOn              []TaskDependency          `json:"depends_on,omitempty"`
+	ExistingClusterID      string                    `json:"existing_cluster_id,omitempty"`
+	ExistingClusterName    string                    `json:"existing_cluster_name,omitempty"`
+	NewCluster             *dbmodels.NewCluster      `json:"new_cluster,omitempty"`
+	JobClusterKey          string                    `json:"job_cluster_key,omitempty"`
+	NotebookTask           *dbmodels.NotebookTask    `json:"notebook_task,omitempty"`
+	SparkJarTask           *dbmodels.SparkJarTask    `json:"spark_jar_task,omitempty"`
+	SparkPythonTask        *dbmodels.SparkPythonTask `json:"spark_python_task,omitempty"`
+	SparkSubmitTask        *dbmodels.SparkSubmitTask `json:"spark_submit_task,omitempty"`
+	Libraries              []dbmodels.Library        `json:"libraries,omitempty"`
+	TimeoutSeconds         int32                     `json:"timeout_seconds,omitempty"`
+	MaxRetries             int32                     `json:"max_retries,omitempty"`
+	MinRetryIntervalMillis int32                     `json:"min_retry_interval_millis,omitempty"`
+	RetryOnTimeout         bool                      `json:"retry_on_timeout,omitempty"`
+}
+
+// TaskDependency references a task that has to complete before the dependent task starts
+type TaskDependency struct {
+	TaskKey string `json:"task_key"`
+}
+
+// JobCluster is a cluster definition shared by the tasks of a multi-task job
+type JobCluster struct {
+	JobClusterKey string               `json:"job_cluster_key"`
+	NewCluster    *dbmodels.NewCluster `json:"new_cluster"`
+}
+
+// IsMultiTask returns true if the job is defined with tasks
+func (k8sjs *JobSettings) IsMultiTask() bool {
+	return len(k8sjs.Tasks) > 0
+}
+
+// ValidateTasks checks that a multi-task job does not mix in single task
+// settings and that all task and job cluster references can be resolved
+func (k8sjs *JobSettings) ValidateTasks() error {
+	if !k8sjs.IsMultiTask() {
+		if len(k8sjs.JobClusters) > 0 {
+			return fmt.Errorf("job_clusters requires tasks")
+		}
+		return nil
+	}
+	if k8sjs.ExistingClusterID != "" || k8sjs.ExistingClusterName != "" || k8sjs.NewCluster != nil ||
+		k8sjs.NotebookTask != nil || k8sjs.SparkJarTask != nil || k8sjs.SparkPythonTask != nil ||
+		k8sjs.SparkSubmitTask != nil || len(k8sjs.Libraries) > 0 {
+		return fmt.Errorf("tasks cannot be combined with a job level cluster, task or libraries")
+	}
+	if k8sjs.MirrorRuns {
+		return fmt.Errorf("mirror_runs is not supported for jobs with tasks")
+	}
+
+	jobClusters := map[string]bool{}
+	for _, jobCluster := range k8sjs.JobClusters {
+		if jobCluster.JobClusterKey == "" {
+			return fmt.Errorf("job cluster without job_cluster_key")
+		}
+		if jobClusters[jobCluster.JobClusterKey] {
+			return fmt.Errorf("duplicate job cluster %s", jobCluster.JobClusterKey)
+		}
+		jobClusters[jobCluster.JobClusterKey] = true
+	}
+
+	tasks := map[string]bool{}
+	for _, task := range k8sjs.Tasks {
+		if task.TaskKey == "" {
+			return fmt.Errorf("task without task_key")
+		}
+		if tasks[task.TaskKey] {
+			return fmt.Errorf("duplicate task %s", task.TaskKey)
+		}
+		tasks[task.TaskKey] = true
+	}
+
+	for _, task := range k8sjs.Tasks {
+		for _, dependency := range task.DependsOn {
+			if !tasks[dependency.TaskKey] {
+				return fmt.Errorf("task %s depends on unknown task %s", task.TaskKey, dependency.TaskKey)
+			}
+		}
+		if task.JobClusterKey != "" && !jobClusters[task.JobClusterKey] {
+			return fmt.Errorf("task %s references unknown job cluster %s", task.TaskKey, task.JobClusterKey)
+		}
+	}
+	return nil
 }
 
 // ToK8sJobSettings converts a databricks JobSettings object to k8s JobSettings object.
@@ -100,6 +100,65 @@ var _ = Describe("Djob", func() {
 			Expect(djob2.IsSubmitted()).To(BeFalse())
 		})
 
+		It("should correctly handle run now triggers", func() {
+			djob := &Djob{
+				ObjectMeta: metav1.ObjectMeta{Name: "nightly"},
+				Spec:       &JobSettings{},
+				Status:     &DjobStatus{JobStatus: &dbmodels.Job{JobID: 20}},
+			}
+			Expect(djob.IsRunNowRequested()).To(BeFalse())
+
+			djob.Spec.RunNow = &JobRunNow{}
+			Expect(djob.IsRunNowRequested()).To(BeFalse())
+
+			djob.Spec.RunNow.Trigger = 1
+			Expect(djob.IsRunNowRequested()).To(BeTrue())
+			Expect(djob.GetTriggeredRunName(1)).To(Equal("nightly-run-1"))
+
+			djob.Status.LastTriggeredRun = &DjobTriggeredRun{Trigger: 1, RunName: "nightly-run-1"}
+			Expect(djob.IsRunNowRequested()).To(BeFalse())
+
+			djob.Spec.RunNow.Trigger = 2
+			Expect(djob.IsRunNowRequested()).To(BeTrue())
+		})
+
+		It("should correctly validate tasks", func() {
+			settings := &JobSettings{
+				NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/ingest"},
+			}
+			Expect(settings.IsMultiTask()).To(BeFalse())
+			Expect(settings.ValidateTasks()).To(Succeed())
+
+			settings.JobClusters = []JobCluster{{JobClusterKey: "etl"}}
+			Expect(settings.ValidateTasks()).ToNot(Succeed())
+
+			settings.Tasks = []JobTaskSettings{
+				{TaskKey: "ingest", JobClusterKey: "etl"},
+				{TaskKey: "train", DependsOn: []TaskDependency{{TaskKey: "ingest"}}, ExistingClusterName: "shared"},
+			}
+			Expect(settings.IsMultiTask()).To(BeTrue())
+			Expect(settings.ValidateTasks()).ToNot(Succeed())
+
+			settings.NotebookTask = nil
+			Expect(settings.ValidateTasks()).To(Succeed())
+
+			settings.Tasks[1].DependsOn = []TaskDependency{{TaskKey: "prepare"}}
+			Expect(settings.ValidateTasks()).ToNot(Succeed())
+
+			settings.Tasks[1].DependsOn = nil
+			settings.Tasks[0].JobClusterKey = "shared"
+			Expect(settings.ValidateTasks()).ToNot(Succeed())
+
+			settings.Tasks[0].JobClusterKey = "etl"
+			settings.Tasks[1].TaskKey = "ingest"
+			Expect(settings.ValidateTasks()).ToNot(Succeed())
+
+			settings.Tasks[1].TaskKey = "train"
+			Expect(settings.ValidateTasks()).To(Succeed())
+			settings.MirrorRuns = true
+			Expect(settings.ValidateTasks()).ToNot(Succeed())
+		})
+
 		It("should correctly handle finalizers", func() {
 			djob := &Djob{
 				ObjectMeta: metav1.ObjectMeta{
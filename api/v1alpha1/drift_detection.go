@@ -0,0 +1,95 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DriftPolicy is what the operator does when the remote object no longer matches what it uploaded
+type DriftPolicy string
+
+const (
+	// DriftPolicyReport raises an event and sets the InSync condition to false
+	DriftPolicyReport DriftPolicy = "report"
+	// DriftPolicyEnforce reports the drift and uploads the object again
+	DriftPolicyEnforce DriftPolicy = "enforce"
+)
+
+// ConditionTypeInSync is true when the remote object was last verified to match what was uploaded
+const ConditionTypeInSync ConditionType = "InSync"
+
+// Reasons for the InSync condition
+const (
+	DriftReasonVerified        = "Verified"
+	DriftReasonMissing         = "Missing"
+	DriftReasonSizeMismatch    = "SizeMismatch"
+	DriftReasonContentMismatch = "ContentMismatch"
+	DriftReasonCorrected       = "DriftCorrected"
+)
+
+// DriftDetection configures periodic verification that the remote object still matches what was uploaded
+type DriftDetection struct {
+	// IntervalSeconds is the time between verifications. Verification is disabled when it is 0.
+	IntervalSeconds int32 `json:"interval_seconds,omitempty"`
+	// VerifyContent also reads the remote object back and compares its hash, rather than
+	// only checking that it exists
+	VerifyContent bool `json:"verify_content,omitempty"`
+	// +kubebuilder:validation:Enum=report;enforce
+	Policy DriftPolicy `json:"policy,omitempty"`
+}
+
+// IsEnabled returns true if periodic verification is configured
+func (dd *DriftDetection) IsEnabled() bool {
+	return dd != nil && dd.IntervalSeconds > 0
+}
+
+// IsEnforced returns true if drift should be corrected by uploading again
+func (dd *DriftDetection) IsEnforced() bool {
+	return dd.IsEnabled() && dd.Policy == DriftPolicyEnforce
+}
+
+// Interval returns the time between verifications
+func (dd *DriftDetection) Interval() time.Duration {
+	if !dd.IsEnabled() {
+		return 0
+	}
+	return time.Duration(dd.IntervalSeconds) * time.Second
+}
+
+// NextVerification returns how long to wait from now until the next verification is due,
+// or 0 if it is due already
+func (dd *DriftDetection) NextVerification(lastVerified *metav1.Time, now time.Time) time.Duration {
+	if !dd.IsEnabled() || lastVerified == nil {
+		return 0
+	}
+	next := lastVerified.Add(dd.Interval())
+	if !next.After(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
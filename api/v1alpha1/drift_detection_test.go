@@ -0,0 +1,119 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("DriftDetection", func() {
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	Context("Settings", func() {
+		It("should correctly handle enabled and enforced", func() {
+			var detection *DriftDetection
+			Expect(detection.IsEnabled()).To(BeFalse())
+			Expect(detection.IsEnforced()).To(BeFalse())
+			Expect(detection.Interval()).To(Equal(time.Duration(0)))
+
+			detection = &DriftDetection{Policy: DriftPolicyEnforce}
+			Expect(detection.IsEnabled()).To(BeFalse())
+			Expect(detection.IsEnforced()).To(BeFalse())
+
+			detection.IntervalSeconds = 60
+			Expect(detection.IsEnabled()).To(BeTrue())
+			Expect(detection.IsEnforced()).To(BeTrue())
+			Expect(detection.Interval()).To(Equal(time.Minute))
+
+			detection.Policy = DriftPolicyReport
+			Expect(detection.IsEnforced()).To(BeFalse())
+		})
+
+		It("should correctly compute the next verification", func() {
+			detection := &DriftDetection{IntervalSeconds: 60}
+			now := time.Now()
+
+			Expect(detection.NextVerification(nil, now)).To(Equal(time.Duration(0)))
+
+			lastVerified := metav1.NewTime(now.Add(-20 * time.Second))
+			Expect(detection.NextVerification(&lastVerified, now)).To(Equal(40 * time.Second))
+
+			lastVerified = metav1.NewTime(now.Add(-2 * time.Minute))
+			Expect(detection.NextVerification(&lastVerified, now)).To(Equal(time.Duration(0)))
+		})
+	})
+
+	Context("Conditions", func() {
+		It("should add and update conditions", func() {
+			var conditions []Condition
+			Expect(GetCondition(conditions, ConditionTypeInSync)).To(BeNil())
+
+			conditions = SetCondition(conditions, Condition{
+				Type:   ConditionTypeInSync,
+				Status: corev1.ConditionTrue,
+				Reason: DriftReasonVerified,
+			})
+			Expect(conditions).To(HaveLen(1))
+			transitioned := GetCondition(conditions, ConditionTypeInSync).LastTransitionTime
+			Expect(transitioned.IsZero()).To(BeFalse())
+
+			By("keeping the transition time when the status is unchanged")
+			conditions = SetCondition(conditions, Condition{
+				Type:    ConditionTypeInSync,
+				Status:  corev1.ConditionTrue,
+				Reason:  DriftReasonVerified,
+				Message: "verified again",
+			})
+			Expect(conditions).To(HaveLen(1))
+			Expect(GetCondition(conditions, ConditionTypeInSync).LastTransitionTime).To(Equal(transitioned))
+			Expect(GetCondition(conditions, ConditionTypeInSync).Message).To(Equal("verified again"))
+
+			By("moving the transition time when the status changes")
+			changed := metav1.NewTime(transitioned.Add(time.Hour))
+			conditions = SetCondition(conditions, Condition{
+				Type:               ConditionTypeInSync,
+				Status:             corev1.ConditionFalse,
+				Reason:             DriftReasonMissing,
+				LastTransitionTime: changed,
+			})
+			Expect(conditions).To(HaveLen(1))
+			Expect(GetCondition(conditions, ConditionTypeInSync).Status).To(Equal(corev1.ConditionFalse))
+			Expect(GetCondition(conditions, ConditionTypeInSync).Reason).To(Equal(DriftReasonMissing))
+			Expect(GetCondition(conditions, ConditionTypeInSync).LastTransitionTime).To(Equal(changed))
+		})
+	})
+})
@@ -25,6 +25,8 @@ SOFTWARE..
 package v1alpha1
 
 import (
+	"crypto/sha1"
+	"fmt"
 	"math/rand"
 	"time"
 )
@@ -63,3 +65,14 @@ func randomStringWithCharset(length int, charset string) string {
 func RandomString(length int) string {
 	return randomStringWithCharset(length, charset)
 }
+
+// HashContent returns the hex encoded sha1 hash of the data
+func HashContent(data []byte) string {
+	h := sha1.New()
+	_, err := h.Write(data)
+	if err != nil {
+		return ""
+	}
+	bs := h.Sum(nil)
+	return fmt.Sprintf("%x", bs)
+}
@@ -84,5 +84,10 @@ var _ = Describe("Helpers", func() {
 			Expect(len(a1)).To(Equal(len(a2)))
 			Expect(len(b1)).To(Equal(10))
 		})
+
+		It("should hash content", func() {
+			Expect(HashContent([]byte("test"))).To(Equal("a94a8fe5ccb19ba61c4c0873d391e987982fbbd3"))
+			Expect(HashContent([]byte{})).To(Equal("da39a3ee5e6b4b0d3255bfef95601890afd80709"))
+		})
 	})
 })
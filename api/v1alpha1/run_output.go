@@ -0,0 +1,65 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+// RunOutputTarget names the ConfigMap or Secret the output of the run is written to when it terminates.
+// Exactly one of the names should be set. The object is created in the namespace of the run and owned by it.
+type RunOutputTarget struct {
+	ConfigMapName string `json:"config_map_name,omitempty"`
+	SecretName    string `json:"secret_name,omitempty"`
+}
+
+// RunOutputStatus records the output that was last written to the output target
+type RunOutputStatus struct {
+	// RunID is the DataBricks run the output was read from
+	RunID int64 `json:"run_id,omitempty"`
+	// SHA256 is the hash of the written output, it is also set as an annotation of the object
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Keys of the output written to the output target
+const (
+	RunOutputResultKey      = "result"
+	RunOutputResultStateKey = "result_state"
+	RunOutputErrorKey       = "error"
+	RunOutputErrorTraceKey  = "error_trace"
+)
+
+// RunOutputSHA256Annotation is set on the output target to the hash of the output
+const RunOutputSHA256Annotation = "databricks.microsoft.com/output-sha256"
+
+// GetOutputTarget returns where the output of the run is written, or nil if it is not written anywhere
+func (run *Run) GetOutputTarget() *RunOutputTarget {
+	spec := run.GetEffectiveSpec()
+	if spec == nil || spec.OutputTo == nil || (spec.OutputTo.ConfigMapName == "" && spec.OutputTo.SecretName == "") {
+		return nil
+	}
+	return spec.OutputTo
+}
+
+// IsOutputExported returns true if the output of the current attempt was written to the output target
+func (run *Run) IsOutputExported() bool {
+	return run.Status != nil && run.Status.ExportedOutput != nil && run.Status.ExportedOutput.RunID == run.Status.Metadata.RunID
+}
@@ -0,0 +1,144 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+)
+
+// RunParameter sets a parameter of the run when it is submitted, from a config map, a secret,
+// a field of the Run or a value that references fields of the Run. Exactly one source should be set.
+type RunParameter struct {
+	// Name is the name of a notebook parameter, it is ignored for jar, python and spark submit parameters
+	Name            string         `json:"name,omitempty"`
+	ConfigMapKeyRef *ContentKeyRef `json:"config_map_key_ref,omitempty"`
+	SecretKeyRef    *ContentKeyRef `json:"secret_key_ref,omitempty"`
+	// FieldPath selects a field of the Run: metadata.name, metadata.namespace, metadata.uid,
+	// metadata.creationTimestamp, metadata.labels['<key>'] or metadata.annotations['<key>']
+	FieldPath string `json:"field_path,omitempty"`
+	// Value is a template where $(<field path>) is replaced with the field of the Run,
+	// such as "/output/$(metadata.namespace)/$(metadata.name)"
+	Value string `json:"value,omitempty"`
+}
+
+var fieldPathMapKeyRegexp = regexp.MustCompile(`^metadata\.(labels|annotations)\['([^']*)'\]$`)
+
+var fieldPathTemplateRegexp = regexp.MustCompile(`\$\(([^)]+)\)`)
+
+// GetFieldValue returns the value of a field of the Run, selected like in the downward API
+func (run *Run) GetFieldValue(fieldPath string) (string, error) {
+	switch fieldPath {
+	case "metadata.name":
+		return run.GetName(), nil
+	case "metadata.namespace":
+		return run.GetNamespace(), nil
+	case "metadata.uid":
+		return string(run.GetUID()), nil
+	case "metadata.creationTimestamp":
+		return run.GetCreationTimestamp().UTC().Format(time.RFC3339), nil
+	}
+	if match := fieldPathMapKeyRegexp.FindStringSubmatch(fieldPath); match != nil {
+		if match[1] == "labels" {
+			return run.GetLabels()[match[2]], nil
+		}
+		return run.GetAnnotations()[match[2]], nil
+	}
+	return "", fmt.Errorf("unsupported field path %s", fieldPath)
+}
+
+// ExpandFieldValues replaces the $(<field path>) references in the template with the fields of the Run
+func (run *Run) ExpandFieldValues(template string) (string, error) {
+	var err error
+	expanded := fieldPathTemplateRegexp.ReplaceAllStringFunc(template, func(reference string) string {
+		value, fieldErr := run.GetFieldValue(strings.TrimSpace(reference[2 : len(reference)-1]))
+		if fieldErr != nil && err == nil {
+			err = fieldErr
+		}
+		return value
+	})
+	return expanded, err
+}
+
+// HasParametersFrom returns true if some parameters of the run are only resolved when it is submitted
+func (spec *RunSpec) HasParametersFrom() bool {
+	return len(spec.NotebookParamsFrom) > 0 || len(spec.JarParamsFrom) > 0 ||
+		len(spec.PythonParamsFrom) > 0 || len(spec.SparkSubmitParamsFrom) > 0
+}
+
+// AppendJarParameters appends parameters of the jar, as jar params of the job
+// for runs of a Djob and as parameters of the spark jar task otherwise
+func (spec *RunSpec) AppendJarParameters(values ...string) error {
+	if spec.JobName != "" {
+		spec.ensureRunParameters()
+		spec.JarParams = append(spec.JarParams, values...)
+		return nil
+	}
+	if spec.JobTask == nil || spec.SparkJarTask == nil {
+		return fmt.Errorf("cannot set jar parameters as the run has no spark jar task")
+	}
+	spec.SparkJarTask.Parameters = append(spec.SparkJarTask.Parameters, values...)
+	return nil
+}
+
+// AppendPythonParameters appends parameters of the python file, as python params of the job
+// for runs of a Djob and as parameters of the spark python task otherwise
+func (spec *RunSpec) AppendPythonParameters(values ...string) error {
+	if spec.JobName != "" {
+		spec.ensureRunParameters()
+		spec.PythonParams = append(spec.PythonParams, values...)
+		return nil
+	}
+	if spec.JobTask == nil || spec.SparkPythonTask == nil {
+		return fmt.Errorf("cannot set python parameters as the run has no spark python task")
+	}
+	spec.SparkPythonTask.Parameters = append(spec.SparkPythonTask.Parameters, values...)
+	return nil
+}
+
+// AppendSparkSubmitParameters appends parameters of spark submit, as spark submit params of
+// the job for runs of a Djob and as parameters of the spark submit task otherwise
+func (spec *RunSpec) AppendSparkSubmitParameters(values ...string) error {
+	if spec.JobName != "" {
+		spec.ensureRunParameters()
+		spec.SparkSubmitParams = append(spec.SparkSubmitParams, values...)
+		return nil
+	}
+	if spec.JobTask == nil || spec.SparkSubmitTask == nil {
+		return fmt.Errorf("cannot set spark submit parameters as the run has no spark submit task")
+	}
+	spec.SparkSubmitTask.Parameters = append(spec.SparkSubmitTask.Parameters, values...)
+	return nil
+}
+
+func (spec *RunSpec) ensureRunParameters() {
+	if spec.RunParameters == nil {
+		spec.RunParameters = &dbmodels.RunParameters{}
+	}
+}
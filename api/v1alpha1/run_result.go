@@ -0,0 +1,189 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RunResultFormat is how the exit value of the notebook is interpreted
+type RunResultFormat string
+
+const (
+	// RunResultFormatText keeps the exit value as a string
+	RunResultFormatText RunResultFormat = "text"
+	// RunResultFormatJSON parses the exit value as JSON
+	RunResultFormatJSON RunResultFormat = "json"
+)
+
+// RunResultField projects a field of a JSON result into status.results
+type RunResultField struct {
+	Name string `json:"name"`
+	// Path is the dot separated path of the field in the result, such as metrics.accuracy
+	Path string `json:"path"`
+}
+
+// ConditionTypeResultValid is true when the JSON result of a succeeded run was parsed and matches its schema
+const ConditionTypeResultValid ConditionType = "ResultValid"
+
+// Reasons for the ResultValid condition
+const (
+	ResultReasonValid          = "Valid"
+	ResultReasonTruncated      = "Truncated"
+	ResultReasonInvalidJSON    = "InvalidJSON"
+	ResultReasonInvalidSchema  = "InvalidSchema"
+	ResultReasonSchemaMismatch = "SchemaMismatch"
+	ResultReasonMissingField   = "MissingField"
+)
+
+// resultSchema is the subset of JSON schema that results are validated against
+type resultSchema struct {
+	Type       string                   `json:"type,omitempty"`
+	Properties map[string]*resultSchema `json:"properties,omitempty"`
+	Required   []string                 `json:"required,omitempty"`
+	Items      *resultSchema            `json:"items,omitempty"`
+	Enum       []interface{}            `json:"enum,omitempty"`
+}
+
+// ParseResult parses the JSON result of the run, validates it against the schema of the spec and
+// returns the projected fields. The returned condition reports whether the result is valid.
+func (spec *RunSpec) ParseResult(result string, truncated bool) (map[string]string, Condition) {
+	invalid := func(reason, message string) (map[string]string, Condition) {
+		return nil, Condition{Type: ConditionTypeResultValid, Status: corev1.ConditionFalse, Reason: reason, Message: message}
+	}
+	if truncated {
+		return invalid(ResultReasonTruncated, "Result was truncated by DataBricks")
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(result), &value); err != nil {
+		return invalid(ResultReasonInvalidJSON, err.Error())
+	}
+	if spec.ResultSchema != "" {
+		var schema resultSchema
+		if err := json.Unmarshal([]byte(spec.ResultSchema), &schema); err != nil {
+			return invalid(ResultReasonInvalidSchema, err.Error())
+		}
+		if err := schema.validate("result", value); err != nil {
+			return invalid(ResultReasonSchemaMismatch, err.Error())
+		}
+	}
+
+	var results map[string]string
+	for _, field := range spec.ResultFields {
+		fieldValue, ok := lookupResultField(value, field.Path)
+		if !ok {
+			return invalid(ResultReasonMissingField, fmt.Sprintf("result has no field %s", field.Path))
+		}
+		if results == nil {
+			results = map[string]string{}
+		}
+		results[field.Name] = fieldValue
+	}
+	return results, Condition{Type: ConditionTypeResultValid, Status: corev1.ConditionTrue, Reason: ResultReasonValid}
+}
+
+// lookupResultField returns the value at the dot separated path, strings are returned as is and other values as JSON
+func lookupResultField(value interface{}, path string) (string, bool) {
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if value, ok = object[key]; !ok {
+			return "", false
+		}
+	}
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	data, err := json.Marshal(value)
+	return string(data), err == nil
+}
+
+func (s *resultSchema) validate(path string, value interface{}) error {
+	if s.Type != "" && !matchesSchemaType(s.Type, value) {
+		return fmt.Errorf("%s should be of type %s", path, s.Type)
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s should be one of %v", path, s.Enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				return fmt.Errorf("%s.%s is required", path, key)
+			}
+		}
+		for key, property := range s.Properties {
+			if fieldValue, ok := v[key]; ok && property != nil {
+				if err := property.validate(path+"."+key, fieldValue); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func matchesSchemaType(schemaType string, value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return schemaType == "object"
+	case []interface{}:
+		return schemaType == "array"
+	case string:
+		return schemaType == "string"
+	case bool:
+		return schemaType == "boolean"
+	case float64:
+		return schemaType == "number" || (schemaType == "integer" && v == float64(int64(v)))
+	case nil:
+		return schemaType == "null"
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+)
+
+// RetryPolicy resubmits runs that terminated in one of the specified states
+type RetryPolicy struct {
+	MaxRetries int32 `json:"max_retries,omitempty"`
+	// BackoffSeconds is the delay before the first retry, it doubles with every retry
+	BackoffSeconds int32 `json:"backoff_seconds,omitempty"`
+	// RetryOn lists the states that are retried, such as INTERNAL_ERROR, FAILED or TIMEDOUT.
+	// It defaults to INTERNAL_ERROR.
+	RetryOn []string `json:"retry_on,omitempty"`
+}
+
+// ShouldRetry returns true if a run that terminated in the state, after the specified number of retries, is retried
+func (p *RetryPolicy) ShouldRetry(state string, retries int32) bool {
+	if state == "" || state == dbmodels.RunResultStateSuccess || retries >= p.MaxRetries {
+		return false
+	}
+	retryOn := p.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = []string{dbmodels.RunLifeCycleStateInternalError}
+	}
+	return containsString(retryOn, state)
+}
+
+// Backoff returns the delay before the retry that follows the specified number of retries
+func (p *RetryPolicy) Backoff(retries int32) time.Duration {
+	backoff := time.Duration(p.BackoffSeconds) * time.Second
+	for i := int32(0); i < retries && backoff < time.Hour; i++ {
+		backoff *= 2
+	}
+	return backoff
+}
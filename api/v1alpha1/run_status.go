@@ -0,0 +1,195 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+	"unicode/utf8"
+
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Conditions of a run. Succeeded, Failed and Canceled become true once the run reaches the
+// matching phase, so that `kubectl wait --for=condition=Succeeded` works on runs.
+const (
+	// ConditionTypeSubmitted is true once the current attempt is submitted to DataBricks
+	ConditionTypeSubmitted ConditionType = "Submitted"
+	// ConditionTypeSucceeded is true if the run succeeded, false if it failed or was canceled and unknown until then
+	ConditionTypeSucceeded ConditionType = "Succeeded"
+	// ConditionTypeFailed is true if the run failed
+	ConditionTypeFailed ConditionType = "Failed"
+	// ConditionTypeCanceled is true if the run was canceled
+	ConditionTypeCanceled ConditionType = "Canceled"
+	// ConditionTypeReconciling is true until the run reaches a final phase
+	ConditionTypeReconciling ConditionType = "Reconciling"
+	// ConditionTypeStalled is true if the run failed
+	ConditionTypeStalled ConditionType = "Stalled"
+)
+
+// GetPhase returns the phase of the current attempt of the run
+func (run *Run) GetPhase() RunPhase {
+	if run.Status != nil && run.Status.QueuePosition > 0 {
+		return RunPhaseQueued
+	}
+	if !run.IsSubmitted() {
+		if run.IsCancelRequested() {
+			return RunPhaseCanceled
+		}
+		return RunPhasePending
+	}
+	if !run.IsTerminated() {
+		state := run.Status.Metadata.State
+		if state == nil || state.LifeCycleState == nil || *state.LifeCycleState == dbmodels.RunLifeCycleStatePending {
+			return RunPhasePending
+		}
+		return RunPhaseRunning
+	}
+	if run.Status.NextRetryTime != nil {
+		return RunPhaseRetrying
+	}
+	switch run.GetTerminalState() {
+	case dbmodels.RunResultStateSuccess:
+		return RunPhaseSucceeded
+	case dbmodels.RunResultStateCanceled:
+		return RunPhaseCanceled
+	}
+	return RunPhaseFailed
+}
+
+// IsFinal returns true if the phase does not change unless the run is submitted again
+func (phase RunPhase) IsFinal() bool {
+	return phase == RunPhaseSucceeded || phase == RunPhaseFailed || phase == RunPhaseCanceled
+}
+
+// SetOutput records the output reported by DataBricks, whose result is not capped yet
+func (run *Run) SetOutput(output dbazure.JobsRunsGetOutputResponse) {
+	if run.Status == nil {
+		run.Status = &RunStatus{}
+	}
+	run.Status.JobsRunsGetOutputResponse = output
+	run.Status.ResultTruncated = false
+}
+
+// UpdateStatus derives the phase, conditions, times and run page link of the run from the output
+// reported by DataBricks, and caps the notebook result and the error at maxResultBytes.
+// The output is kept as is when maxResultBytes is 0.
+func (run *Run) UpdateStatus(maxResultBytes int, now time.Time) {
+	if run.Status == nil {
+		run.Status = &RunStatus{}
+	}
+	status := run.Status
+	status.ObservedGeneration = run.GetGeneration()
+	status.Phase = run.GetPhase()
+	status.RunPageURL = status.Metadata.RunPageURL
+
+	status.StartTime = nil
+	if status.Metadata.StartTime > 0 {
+		startTime := metav1.NewTime(time.Unix(status.Metadata.StartTime/1000, 0))
+		status.StartTime = &startTime
+	}
+	switch {
+	case run.IsTerminated() && status.Metadata.StartTime > 0:
+		endTime := metav1.NewTime(run.GetFinishedTime().Truncate(time.Second))
+		status.EndTime = &endTime
+	case status.Phase.IsFinal():
+		if status.EndTime == nil {
+			endTime := metav1.NewTime(now.Truncate(time.Second))
+			status.EndTime = &endTime
+		}
+	default:
+		status.EndTime = nil
+	}
+
+	// the result is parsed before it is capped, a capped result keeps what was parsed from the whole result
+	spec := run.GetEffectiveSpec()
+	switch {
+	case spec == nil || spec.ResultFormat != RunResultFormatJSON || status.Phase != RunPhaseSucceeded:
+		status.Results = nil
+		status.Conditions = RemoveCondition(status.Conditions, ConditionTypeResultValid)
+	case !status.ResultTruncated || GetCondition(status.Conditions, ConditionTypeResultValid) == nil:
+		results, condition := spec.ParseResult(status.NotebookOutput.Result, status.NotebookOutput.Truncated)
+		status.Results = results
+		status.Conditions = SetCondition(status.Conditions, condition)
+	}
+
+	if result, truncated := truncateResult(status.NotebookOutput.Result, maxResultBytes); truncated {
+		status.NotebookOutput.Result = result
+		status.ResultTruncated = true
+	}
+	status.Error, _ = truncateResult(status.Error, maxResultBytes)
+
+	run.updateConditions()
+}
+
+func (run *Run) updateConditions() {
+	status := run.Status
+	reason := string(status.Phase)
+	message := ""
+	if status.Metadata.State != nil {
+		message = status.Metadata.State.StateMessage
+	}
+	if status.Error != "" {
+		message = status.Error
+	}
+
+	conditionStatus := func(value bool) corev1.ConditionStatus {
+		if value {
+			return corev1.ConditionTrue
+		}
+		return corev1.ConditionFalse
+	}
+	succeeded := corev1.ConditionUnknown
+	if status.Phase.IsFinal() {
+		succeeded = conditionStatus(status.Phase == RunPhaseSucceeded)
+	}
+
+	for _, condition := range []Condition{
+		{Type: ConditionTypeSubmitted, Status: conditionStatus(run.IsSubmitted() && status.Phase != RunPhaseQueued)},
+		{Type: ConditionTypeSucceeded, Status: succeeded},
+		{Type: ConditionTypeFailed, Status: conditionStatus(status.Phase == RunPhaseFailed)},
+		{Type: ConditionTypeCanceled, Status: conditionStatus(status.Phase == RunPhaseCanceled)},
+		{Type: ConditionTypeReconciling, Status: conditionStatus(!status.Phase.IsFinal())},
+		{Type: ConditionTypeStalled, Status: conditionStatus(status.Phase == RunPhaseFailed)},
+	} {
+		condition.Reason = reason
+		condition.Message = message
+		status.Conditions = SetCondition(status.Conditions, condition)
+	}
+}
+
+// truncateResult caps the value at maxBytes without splitting a character, it returns true if the value was cut
+func truncateResult(value string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(value) <= maxBytes {
+		return value, false
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut], true
+}
@@ -25,6 +25,9 @@ SOFTWARE.
 package v1alpha1
 
 import (
+	"fmt"
+	"time"
+
 	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,6 +38,10 @@ import (
 
 // RunSpec defines the desired state of Run
 type RunSpec struct {
+	// TemplateRef creates the run from a RunTemplate, the other fields of the spec override the template
+	TemplateRef *RunTemplateRef `json:"template_ref,omitempty"`
+	// TemplateParams sets the parameters of the template
+	TemplateParams map[string]string `json:"template_params,omitempty"`
 	// dedicated for job run
 	JobName                 string `json:"job_name,omitempty"`
 	*dbmodels.RunParameters `json:",inline"`
@@ -43,20 +50,163 @@ type RunSpec struct {
 	ClusterSpec       `json:",inline"`
 	*dbmodels.JobTask `json:",inline"`
 	TimeoutSeconds    int32 `json:"timeout_seconds,omitempty"`
+	// TTLSecondsAfterFinished deletes the Run this long after it terminated,
+	// it defaults to the RUN_TTL_SECONDS_AFTER_FINISHED setting of the operator
+	TTLSecondsAfterFinished *int32 `json:"ttl_seconds_after_finished,omitempty"`
+	// DeleteRemoteAfterTTL also deletes the run from DataBricks when the TTL expires,
+	// by default DataBricks keeps the run in its history
+	DeleteRemoteAfterTTL bool `json:"delete_remote_after_ttl,omitempty"`
+	// Cancel cancels the run in DataBricks and keeps the object, a run that has not been submitted yet is not submitted
+	Cancel bool `json:"cancel,omitempty"`
+	// NotebookParamsFrom sets notebook parameters when the run is submitted
+	NotebookParamsFrom []RunParameter `json:"notebook_params_from,omitempty"`
+	// JarParamsFrom, PythonParamsFrom and SparkSubmitParamsFrom are appended to the
+	// parameters of the task when the run is submitted
+	JarParamsFrom         []RunParameter `json:"jar_params_from,omitempty"`
+	PythonParamsFrom      []RunParameter `json:"python_params_from,omitempty"`
+	SparkSubmitParamsFrom []RunParameter `json:"spark_submit_params_from,omitempty"`
+	// QueueName is the RunQueue that admits the run, it defaults to the queue named default
+	// in the namespace of the run. Runs are not queued when the namespace has no such queue.
+	QueueName string `json:"queue_name,omitempty"`
+	// PriorityClassName is one of the priority classes of the queue
+	PriorityClassName string `json:"priority_class_name,omitempty"`
+	// Attempt resubmits the run when it is bumped after the current attempt terminated
+	Attempt int32 `json:"attempt,omitempty"`
+	// RetryPolicy resubmits runs that are not part of a Djob when they fail,
+	// it defaults to the RUN_RETRY_* settings of the operator
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+	// OutputTo writes the result, result state, error and error trace of the run into a ConfigMap
+	// or Secret when it terminates, the whole result is written regardless of the size of the status
+	OutputTo *RunOutputTarget `json:"output_to,omitempty"`
+	// ResultFormat is json to parse the exit value of the notebook, it defaults to text
+	// +kubebuilder:validation:Enum=text;json
+	ResultFormat RunResultFormat `json:"result_format,omitempty"`
+	// ResultSchema is a JSON schema the parsed result is validated against. The type, properties,
+	// required, items and enum keywords are supported.
+	ResultSchema string `json:"result_schema,omitempty"`
+	// ResultFields are projected from the parsed result into status.results
+	ResultFields []RunResultField `json:"result_fields,omitempty"`
+	// MirrorRunID follows the existing DataBricks run with this ID instead of submitting one.
+	// Mirrored runs are read-only, they are not canceled, rerun, retried or deleted from DataBricks.
+	MirrorRunID int64 `json:"mirror_run_id,omitempty"`
+}
+
+// RunStatus is the observed state of the run. The output of the current attempt as returned by
+// DataBricks is kept inline, with the notebook result and error capped to a configurable size.
+type RunStatus struct {
+	dbazure.JobsRunsGetOutputResponse `json:",inline"`
+	// Phase summarizes the state of the current attempt
+	Phase RunPhase `json:"phase,omitempty"`
+	// Conditions are the Submitted, Succeeded, Failed and Canceled conditions of the run, along with
+	// the Reconciling and Stalled conditions that generic tools look for
+	Conditions []Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the run the status was computed for,
+	// it is spelled the way generic tools expect it
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// StartTime is when the current attempt started in DataBricks
+	StartTime *metav1.Time `json:"start_time,omitempty"`
+	// EndTime is when the current attempt terminated
+	EndTime *metav1.Time `json:"end_time,omitempty"`
+	// RunPageURL links to the page of the current attempt in the DataBricks workspace
+	RunPageURL string `json:"run_page_url,omitempty"`
+	// ResultTruncated is true when the operator capped notebook_output.result, the whole
+	// result can be read from DataBricks. notebook_output.truncated is set by DataBricks.
+	ResultTruncated bool `json:"result_truncated,omitempty"`
+	// QueuePosition is the position of a queued run in its queue, the first run to be submitted is at position 1
+	QueuePosition int32 `json:"queue_position,omitempty"`
+	// Attempts lists the previous attempts, oldest first
+	Attempts []RunAttempt `json:"attempts,omitempty"`
+	// EffectiveSpec is the spec rendered from the RunTemplate when the run was first submitted,
+	// later attempts are submitted with the same spec
+	EffectiveSpec *RunSpec `json:"effective_spec,omitempty"`
+	// TemplateGeneration is the generation of the RunTemplate the effective spec was rendered from
+	TemplateGeneration int64 `json:"template_generation,omitempty"`
+	// ObservedAttempt is the spec.attempt the current attempt was submitted for
+	ObservedAttempt int32 `json:"observed_attempt,omitempty"`
+	// Retries counts the attempts submitted by the retry policy
+	Retries int32 `json:"retries,omitempty"`
+	// NextRetryTime is when the terminated attempt is retried by the retry policy
+	NextRetryTime *metav1.Time `json:"next_retry_time,omitempty"`
+	// Results are the fields of the parsed result selected by spec.result_fields
+	Results map[string]string `json:"results,omitempty"`
+	// ExportedOutput records the output written to spec.output_to
+	ExportedOutput *RunOutputStatus `json:"exported_output,omitempty"`
+}
+
+// RunPhase is the phase of a run
+type RunPhase string
+
+const (
+	// RunPhaseQueued means the run waits for its queue to have capacity before it is submitted
+	RunPhaseQueued RunPhase = "Queued"
+	// RunPhasePending means the run is being submitted or DataBricks is setting up its cluster
+	RunPhasePending RunPhase = "Pending"
+	// RunPhaseRunning means the run is executing or terminating in DataBricks
+	RunPhaseRunning RunPhase = "Running"
+	// RunPhaseRetrying means the attempt terminated and the retry policy submits the run again
+	RunPhaseRetrying RunPhase = "Retrying"
+	// RunPhaseSucceeded means the run terminated successfully
+	RunPhaseSucceeded RunPhase = "Succeeded"
+	// RunPhaseFailed means the run failed, timed out, was skipped or hit an internal error
+	RunPhaseFailed RunPhase = "Failed"
+	// RunPhaseCanceled means the run was canceled, in DataBricks or before it was submitted
+	RunPhaseCanceled RunPhase = "Canceled"
+)
+
+// RunAttempt records a previous attempt of a run
+type RunAttempt struct {
+	RunID          int64                       `json:"run_id,omitempty"`
+	LifeCycleState *dbmodels.RunLifeCycleState `json:"life_cycle_state,omitempty"`
+	ResultState    *dbmodels.RunResultState    `json:"result_state,omitempty"`
+	Error          string                      `json:"error,omitempty"`
+	StartTime      int64                       `json:"start_time,omitempty"`
+	// Retry is true if the attempt that followed was submitted by the retry policy
+	Retry bool `json:"retry,omitempty"`
+}
+
+const (
+	// RunKeepRemoteAnnotation is set to "true" on Runs whose DataBricks run should be kept when the Run is deleted
+	RunKeepRemoteAnnotation = "databricks.microsoft.com/keep-remote-run"
+	// RunCancelAnnotation is set to "true" to cancel a Run, like setting spec.cancel
+	RunCancelAnnotation = "databricks.microsoft.com/cancel"
+)
+
+// SetNotebookParameter sets a parameter of the notebook, as a notebook param of the
+// job for runs of a Djob and as a base parameter of the notebook task otherwise
+func (spec *RunSpec) SetNotebookParameter(name, value string) error {
+	if spec.JobName != "" {
+		spec.ensureRunParameters()
+		if spec.NotebookParams == nil {
+			spec.NotebookParams = map[string]string{}
+		}
+		spec.NotebookParams[name] = value
+		return nil
+	}
+	if spec.JobTask == nil || spec.NotebookTask == nil {
+		return fmt.Errorf("cannot set parameter %s as the run has no notebook task", name)
+	}
+	if spec.NotebookTask.BaseParameters == nil {
+		spec.NotebookTask.BaseParameters = map[string]string{}
+	}
+	spec.NotebookTask.BaseParameters[name] = value
+	return nil
 }
 
 // +kubebuilder:object:root=true
 
 // Run is the Schema for the runs API
+// +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="RunID",type="integer",JSONPath=".status.metadata.run_id"
-// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.metadata.state.life_cycle_state"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.metadata.state.life_cycle_state",priority=1
+// +kubebuilder:printcolumn:name="Results",type="string",JSONPath=".status.results",priority=1
 type Run struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   *RunSpec                           `json:"spec,omitempty"`
-	Status *dbazure.JobsRunsGetOutputResponse `json:"status,omitempty"`
+	Spec   *RunSpec   `json:"spec,omitempty"`
+	Status *RunStatus `json:"status,omitempty"`
 }
 
 // IsBeingDeleted returns true if a deletion timestamp is set
@@ -84,6 +234,152 @@ func (run *Run) IsTerminated() bool {
 	return false
 }
 
+// IsFinal returns true if the run terminated and is not retried, so its outcome only
+// changes when a rerun is requested
+func (run *Run) IsFinal() bool {
+	return run.IsTerminated() && run.Status.NextRetryTime == nil
+}
+
+// IsSucceeded returns true if the run terminated with a successful result
+func (run *Run) IsSucceeded() bool {
+	if !run.IsTerminated() || run.Status.Metadata.State.ResultState == nil {
+		return false
+	}
+	return *run.Status.Metadata.State.ResultState == dbmodels.RunResultStateSuccess
+}
+
+// GetFinishedTime returns when the run terminated, which is its start time plus the setup,
+// execution and cleanup durations reported by DataBricks. It falls back to the creation of
+// the object when the run never started.
+func (run *Run) GetFinishedTime() time.Time {
+	if run.Status == nil || run.Status.Metadata.StartTime == 0 {
+		return run.GetCreationTimestamp().Time
+	}
+	metadata := run.Status.Metadata
+	finishedMillis := metadata.StartTime + metadata.SetupDuration + metadata.ExecutionDuration + metadata.CleanupDuration
+	return time.Unix(0, finishedMillis*int64(time.Millisecond))
+}
+
+// GetTTLExpiry returns when a terminated run should be deleted. The TTL of the spec
+// takes precedence over the default; false is returned if the run is not terminated or has no TTL.
+func (run *Run) GetTTLExpiry(defaultTTLSeconds *int32) (time.Time, bool) {
+	if !run.IsTerminated() {
+		return time.Time{}, false
+	}
+	ttlSeconds := defaultTTLSeconds
+	if run.Spec != nil && run.Spec.TTLSecondsAfterFinished != nil {
+		ttlSeconds = run.Spec.TTLSecondsAfterFinished
+	}
+	if ttlSeconds == nil {
+		return time.Time{}, false
+	}
+	return run.GetFinishedTime().Add(time.Duration(*ttlSeconds) * time.Second), true
+}
+
+// GetTerminalState returns INTERNAL_ERROR or SKIPPED when the run ended in that life cycle
+// state and the result state of the run otherwise. It is empty while the run has not terminated.
+func (run *Run) GetTerminalState() string {
+	if !run.IsTerminated() {
+		return ""
+	}
+	state := run.Status.Metadata.State
+	if *state.LifeCycleState != dbmodels.RunLifeCycleStateTerminated || state.ResultState == nil {
+		return string(*state.LifeCycleState)
+	}
+	return string(*state.ResultState)
+}
+
+// GetEffectiveSpec returns the spec the run is submitted with, which is rendered
+// from the RunTemplate for runs that reference one
+func (run *Run) GetEffectiveSpec() *RunSpec {
+	if run.Status != nil && run.Status.EffectiveSpec != nil {
+		return run.Status.EffectiveSpec
+	}
+	return run.Spec
+}
+
+// GetQueueName returns the name of the RunQueue that admits the run
+func (run *Run) GetQueueName() string {
+	if run.Spec == nil || run.Spec.QueueName == "" {
+		return DefaultRunQueueName
+	}
+	return run.Spec.QueueName
+}
+
+// IsMirrored returns true if the run follows an existing DataBricks run rather than submitting one
+func (run *Run) IsMirrored() bool {
+	return run.Spec != nil && run.Spec.MirrorRunID != 0
+}
+
+// IsActive returns true if the run is submitted and has not terminated
+func (run *Run) IsActive() bool {
+	return run.IsSubmitted() && !run.IsTerminated()
+}
+
+// IsQueued returns true if the run waits to be submitted
+func (run *Run) IsQueued() bool {
+	return !run.IsSubmitted() && !run.IsCancelRequested() && !run.IsBeingDeleted()
+}
+
+// IsRerunRequested returns true if spec.attempt was bumped since the current attempt was submitted
+func (run *Run) IsRerunRequested() bool {
+	return run.IsSubmitted() && !run.IsMirrored() && run.Spec != nil && run.Spec.Attempt != run.Status.ObservedAttempt
+}
+
+// GetRetryPolicy returns the retry policy of the run, falling back to the specified
+// default. Runs of a Djob are retried by DataBricks and have no retry policy.
+func (run *Run) GetRetryPolicy(defaultPolicy *RetryPolicy) *RetryPolicy {
+	spec := run.GetEffectiveSpec()
+	if spec == nil || spec.JobName != "" || run.IsMirrored() {
+		return nil
+	}
+	if spec.RetryPolicy != nil {
+		return spec.RetryPolicy
+	}
+	return defaultPolicy
+}
+
+// NextRetry returns whether the terminated run should be retried according to
+// the policy and how long to wait before the retry is due
+func (run *Run) NextRetry(policy *RetryPolicy, now time.Time) (time.Duration, bool) {
+	if policy == nil || run.IsCancelRequested() || !policy.ShouldRetry(run.GetTerminalState(), run.Status.Retries) {
+		return 0, false
+	}
+	due := run.GetFinishedTime().Add(policy.Backoff(run.Status.Retries))
+	if due.Before(now) {
+		return 0, true
+	}
+	return due.Sub(now), true
+}
+
+// SetNextRetryTime records when the terminated run is retried according to the policy,
+// and clears it when the run is not retried
+func (run *Run) SetNextRetryTime(policy *RetryPolicy) {
+	if run.Status == nil {
+		return
+	}
+	run.Status.NextRetryTime = nil
+	if _, retry := run.NextRetry(policy, time.Time{}); !retry {
+		return
+	}
+	due := metav1.NewTime(run.GetFinishedTime().Add(policy.Backoff(run.Status.Retries)).Truncate(time.Second))
+	run.Status.NextRetryTime = &due
+}
+
+// IsCancelRequested returns true if the run should be canceled, through the spec or the cancel annotation.
+// Mirrored runs are never canceled.
+func (run *Run) IsCancelRequested() bool {
+	if run.IsMirrored() {
+		return false
+	}
+	return (run.Spec != nil && run.Spec.Cancel) || run.GetAnnotations()[RunCancelAnnotation] == "true"
+}
+
+// IsRemoteKept returns true if the DataBricks run should be kept when the Run is deleted
+func (run *Run) IsRemoteKept() bool {
+	return run.IsMirrored() || run.GetAnnotations()[RunKeepRemoteAnnotation] == "true"
+}
+
 // RunFinalizerName is the name of the run finalizer
 const RunFinalizerName = "run.finalizers.databricks.microsoft.com"
 
@@ -33,6 +33,7 @@ import (
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
 
 	"golang.org/x/net/context"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -88,11 +89,11 @@ var _ = Describe("Run", func() {
 
 	It("should correctly handle isSubmitted", func() {
 		run := &Run{
-			Status: &dbazure.JobsRunsGetOutputResponse{
+			Status: &RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
 				Metadata: dbmodels.Run{
 					JobID: 23,
 				},
-			},
+			}},
 		}
 		Expect(run.IsSubmitted()).To(BeTrue())
 
@@ -102,6 +103,310 @@ var _ = Describe("Run", func() {
 		Expect(run2.IsSubmitted()).To(BeFalse())
 	})
 
+	It("should correctly handle reruns and retries", func() {
+		started := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateRunning)
+		resultState := dbmodels.RunResultState(dbmodels.RunResultStateFailed)
+		run := &Run{
+			Spec: &RunSpec{},
+			Status: &RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				Metadata: dbmodels.Run{
+					JobID:             23,
+					StartTime:         started.UnixNano() / int64(time.Millisecond),
+					ExecutionDuration: 60000,
+					State:             &dbmodels.RunState{LifeCycleState: &lifeCycleState},
+				},
+			}},
+		}
+		policy := &RetryPolicy{MaxRetries: 2, BackoffSeconds: 60, RetryOn: []string{"FAILED"}}
+		Expect(run.GetTerminalState()).To(BeEmpty())
+		_, retry := run.NextRetry(policy, started)
+		Expect(retry).To(BeFalse())
+
+		By("retrying failed runs with a backoff")
+		lifeCycleState = dbmodels.RunLifeCycleStateTerminated
+		run.Status.Metadata.State.ResultState = &resultState
+		Expect(run.GetTerminalState()).To(Equal("FAILED"))
+		delay, retry := run.NextRetry(policy, started.Add(time.Minute))
+		Expect(retry).To(BeTrue())
+		Expect(delay).To(Equal(time.Minute))
+		run.Status.Retries = 1
+		delay, _ = run.NextRetry(policy, started.Add(time.Hour))
+		Expect(delay).To(BeZero())
+		run.Status.Retries = 2
+		_, retry = run.NextRetry(policy, started.Add(time.Hour))
+		Expect(retry).To(BeFalse())
+
+		By("reporting a retrying phase until the retry is submitted")
+		run.Status.Retries = 1
+		run.SetNextRetryTime(policy)
+		Expect(run.Status.NextRetryTime.Time).To(BeTemporally("==", started.Add(3*time.Minute)))
+		Expect(run.IsTerminated()).To(BeTrue())
+		Expect(run.IsFinal()).To(BeFalse())
+		run.UpdateStatus(0, started.Add(time.Hour))
+		Expect(run.Status.Phase).To(Equal(RunPhaseRetrying))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeFailed).Status).To(Equal(corev1.ConditionFalse))
+		run.Status.Retries = 2
+		run.SetNextRetryTime(policy)
+		Expect(run.Status.NextRetryTime).To(BeNil())
+		Expect(run.IsFinal()).To(BeTrue())
+		run.UpdateStatus(0, started.Add(time.Hour))
+		Expect(run.Status.Phase).To(Equal(RunPhaseFailed))
+
+		By("retrying internal errors by default")
+		lifeCycleState = dbmodels.RunLifeCycleStateInternalError
+		Expect(run.GetTerminalState()).To(Equal(dbmodels.RunLifeCycleStateInternalError))
+		Expect((&RetryPolicy{MaxRetries: 1}).ShouldRetry(run.GetTerminalState(), 0)).To(BeTrue())
+		Expect((&RetryPolicy{MaxRetries: 1}).ShouldRetry(dbmodels.RunResultStateFailed, 0)).To(BeFalse())
+		Expect((&RetryPolicy{MaxRetries: 1, RetryOn: []string{"SUCCESS"}}).ShouldRetry(dbmodels.RunResultStateSuccess, 0)).To(BeFalse())
+		Expect((&RetryPolicy{BackoffSeconds: 10}).Backoff(2)).To(Equal(40 * time.Second))
+
+		By("not retrying canceled runs or runs of a Djob")
+		Expect(run.GetRetryPolicy(policy)).To(Equal(policy))
+		run.Spec.Cancel = true
+		_, retry = run.NextRetry(&RetryPolicy{MaxRetries: 1}, started.Add(time.Hour))
+		Expect(retry).To(BeFalse())
+		run.Spec.JobName = "job"
+		Expect(run.GetRetryPolicy(policy)).To(BeNil())
+
+		By("rerunning when the attempt is bumped")
+		Expect(run.IsRerunRequested()).To(BeFalse())
+		run.Spec.Attempt = 1
+		Expect(run.IsRerunRequested()).To(BeTrue())
+		run.Status.ObservedAttempt = 1
+		Expect(run.IsRerunRequested()).To(BeFalse())
+	})
+
+	It("should correctly resolve fields of the run", func() {
+		created := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		run := &Run{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "run",
+				Namespace:         "staging",
+				Labels:            map[string]string{"team": "data"},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+		}
+		Expect(run.GetFieldValue("metadata.namespace")).To(Equal("staging"))
+		Expect(run.GetFieldValue("metadata.labels['team']")).To(Equal("data"))
+		Expect(run.GetFieldValue("metadata.annotations['missing']")).To(BeEmpty())
+		Expect(run.GetFieldValue("metadata.creationTimestamp")).To(Equal("2020-01-01T12:00:00Z"))
+		_, err := run.GetFieldValue("spec.job_name")
+		Expect(err).To(HaveOccurred())
+
+		Expect(run.ExpandFieldValues("/output/$(metadata.namespace)/$(metadata.name)")).To(Equal("/output/staging/run"))
+		_, err = run.ExpandFieldValues("$(status)")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should correctly append task parameters", func() {
+		spec := &RunSpec{JobTask: &dbmodels.JobTask{SparkJarTask: &dbmodels.SparkJarTask{Parameters: []string{"a"}}}}
+		Expect(spec.AppendJarParameters("b")).To(Succeed())
+		Expect(spec.SparkJarTask.Parameters).To(Equal([]string{"a", "b"}))
+		Expect(spec.AppendPythonParameters("c")).ToNot(Succeed())
+
+		spec = &RunSpec{JobName: "job"}
+		Expect(spec.AppendSparkSubmitParameters("d")).To(Succeed())
+		Expect(spec.SparkSubmitParams).To(Equal([]string{"d"}))
+	})
+
+	It("should correctly handle TTL after finished", func() {
+		started := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+		lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateRunning)
+		run := &Run{
+			Spec: &RunSpec{},
+			Status: &RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				Metadata: dbmodels.Run{
+					JobID:             23,
+					StartTime:         started.UnixNano() / int64(time.Millisecond),
+					SetupDuration:     60000,
+					ExecutionDuration: 120000,
+					State:             &dbmodels.RunState{LifeCycleState: &lifeCycleState},
+				},
+			}},
+		}
+		defaultTTL := int32(3600)
+		_, hasTTL := run.GetTTLExpiry(&defaultTTL)
+		Expect(hasTTL).To(BeFalse())
+
+		lifeCycleState = dbmodels.RunLifeCycleStateTerminated
+		Expect(run.GetFinishedTime()).To(BeTemporally("==", started.Add(3*time.Minute)))
+		_, hasTTL = run.GetTTLExpiry(nil)
+		Expect(hasTTL).To(BeFalse())
+
+		expiry, hasTTL := run.GetTTLExpiry(&defaultTTL)
+		Expect(hasTTL).To(BeTrue())
+		Expect(expiry).To(BeTemporally("==", started.Add(63*time.Minute)))
+
+		ttl := int32(0)
+		run.Spec.TTLSecondsAfterFinished = &ttl
+		expiry, _ = run.GetTTLExpiry(&defaultTTL)
+		Expect(expiry).To(BeTemporally("==", started.Add(3*time.Minute)))
+
+		Expect(run.IsRemoteKept()).To(BeFalse())
+		run.SetAnnotations(map[string]string{RunKeepRemoteAnnotation: "true"})
+		Expect(run.IsRemoteKept()).To(BeTrue())
+	})
+
+	It("should correctly derive the phase and conditions", func() {
+		lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStatePending)
+		run := &Run{Spec: &RunSpec{}}
+		run.UpdateStatus(0, time.Now())
+		Expect(run.Status.Phase).To(Equal(RunPhasePending))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeSubmitted).Status).To(Equal(corev1.ConditionFalse))
+
+		run.Status.QueuePosition = 2
+		run.UpdateStatus(0, time.Now())
+		Expect(run.Status.Phase).To(Equal(RunPhaseQueued))
+
+		run.Status.QueuePosition = 0
+		run.Status.Metadata = dbmodels.Run{JobID: 1, RunID: 2, StartTime: 1577880000000, RunPageURL: "https://run",
+			State: &dbmodels.RunState{LifeCycleState: &lifeCycleState}}
+		run.UpdateStatus(0, time.Now())
+		Expect(run.Status.Phase).To(Equal(RunPhasePending))
+		Expect(run.Status.RunPageURL).To(Equal("https://run"))
+		Expect(run.Status.StartTime.Time).To(BeTemporally("==", time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeSubmitted).Status).To(Equal(corev1.ConditionTrue))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeSucceeded).Status).To(Equal(corev1.ConditionUnknown))
+
+		lifeCycleState = dbmodels.RunLifeCycleStateRunning
+		run.UpdateStatus(0, time.Now())
+		Expect(run.Status.Phase).To(Equal(RunPhaseRunning))
+		Expect(run.Status.EndTime).To(BeNil())
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeReconciling).Status).To(Equal(corev1.ConditionTrue))
+
+		failed := dbmodels.RunResultState(dbmodels.RunResultStateFailed)
+		lifeCycleState = dbmodels.RunLifeCycleStateTerminated
+		run.Status.Metadata.State.ResultState = &failed
+		run.Status.Metadata.ExecutionDuration = 60000
+		run.Status.Error = "notebook failed"
+		run.UpdateStatus(0, time.Now())
+		Expect(run.Status.Phase).To(Equal(RunPhaseFailed))
+		Expect(run.Status.EndTime.Time).To(BeTemporally("==", time.Date(2020, 1, 1, 12, 1, 0, 0, time.UTC)))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeSucceeded).Status).To(Equal(corev1.ConditionFalse))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeFailed).Status).To(Equal(corev1.ConditionTrue))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeStalled).Message).To(Equal("notebook failed"))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeReconciling).Status).To(Equal(corev1.ConditionFalse))
+		Expect(run.Status.Conditions).To(HaveLen(6))
+
+		canceled := dbmodels.RunResultState(dbmodels.RunResultStateCanceled)
+		run.Status.Metadata.State.ResultState = &canceled
+		run.UpdateStatus(0, time.Now())
+		Expect(run.Status.Phase).To(Equal(RunPhaseCanceled))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeCanceled).Status).To(Equal(corev1.ConditionTrue))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeFailed).Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("should correctly cap the result of the run", func() {
+		run := &Run{Status: &RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+			NotebookOutput: dbmodels.NotebookOutput{Result: "héllo"},
+			Error:          "error",
+		}}}
+		run.UpdateStatus(0, time.Now())
+		Expect(run.Status.NotebookOutput.Result).To(Equal("héllo"))
+		Expect(run.Status.ResultTruncated).To(BeFalse())
+
+		run.UpdateStatus(2, time.Now())
+		Expect(run.Status.NotebookOutput.Result).To(Equal("h"))
+		Expect(run.Status.ResultTruncated).To(BeTrue())
+		Expect(run.Status.NotebookOutput.Truncated).To(BeFalse())
+		Expect(run.Status.Error).To(Equal("er"))
+
+		By("resetting the flag for a new output")
+		run.SetOutput(dbazure.JobsRunsGetOutputResponse{NotebookOutput: dbmodels.NotebookOutput{Result: "hi"}})
+		Expect(run.Status.ResultTruncated).To(BeFalse())
+	})
+
+	It("should correctly parse JSON results", func() {
+		spec := &RunSpec{
+			ResultFormat: RunResultFormatJSON,
+			ResultSchema: `{"type": "object", "required": ["status"], "properties": {
+				"status": {"enum": ["ok", "degraded"]},
+				"rows": {"type": "integer"},
+				"tables": {"type": "array", "items": {"type": "string"}}}}`,
+			ResultFields: []RunResultField{{Name: "status", Path: "status"}, {Name: "accuracy", Path: "metrics.accuracy"}},
+		}
+		results, condition := spec.ParseResult(`{"status": "ok", "rows": 12, "tables": ["a"], "metrics": {"accuracy": 0.9}}`, false)
+		Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+		Expect(results).To(Equal(map[string]string{"status": "ok", "accuracy": "0.9"}))
+
+		_, condition = spec.ParseResult(`done`, false)
+		Expect(condition.Reason).To(Equal(ResultReasonInvalidJSON))
+		_, condition = spec.ParseResult(`{"status": "ok", "rows": 1.5}`, false)
+		Expect(condition.Reason).To(Equal(ResultReasonSchemaMismatch))
+		_, condition = spec.ParseResult(`{"status": "failed"}`, false)
+		Expect(condition.Reason).To(Equal(ResultReasonSchemaMismatch))
+		_, condition = spec.ParseResult(`{"status": "ok", "tables": [1]}`, false)
+		Expect(condition.Message).To(Equal("result.tables[0] should be of type string"))
+		_, condition = spec.ParseResult(`{"status": "ok"}`, false)
+		Expect(condition.Reason).To(Equal(ResultReasonMissingField))
+		_, condition = spec.ParseResult(`{"status": "ok"`, true)
+		Expect(condition.Reason).To(Equal(ResultReasonTruncated))
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("should correctly parse the result before capping it", func() {
+		success := dbmodels.RunResultState(dbmodels.RunResultStateSuccess)
+		lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+		run := &Run{
+			Spec: &RunSpec{ResultFormat: RunResultFormatJSON, ResultFields: []RunResultField{{Name: "rows", Path: "rows"}}},
+			Status: &RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				NotebookOutput: dbmodels.NotebookOutput{Result: `{"rows": 12, "padding": "xxxxxxxx"}`},
+				Metadata: dbmodels.Run{JobID: 1, RunID: 2,
+					State: &dbmodels.RunState{LifeCycleState: &lifeCycleState, ResultState: &success}},
+			}},
+		}
+		run.UpdateStatus(10, time.Now())
+		Expect(run.Status.Results).To(Equal(map[string]string{"rows": "12"}))
+		Expect(run.Status.ResultTruncated).To(BeTrue())
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeResultValid).Status).To(Equal(corev1.ConditionTrue))
+
+		By("Keeping the results once the result is capped")
+		run.UpdateStatus(10, time.Now())
+		Expect(run.Status.Results).To(Equal(map[string]string{"rows": "12"}))
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeResultValid).Status).To(Equal(corev1.ConditionTrue))
+
+		By("Clearing them for the next attempt")
+		run.Status.JobsRunsGetOutputResponse = dbazure.JobsRunsGetOutputResponse{}
+		run.UpdateStatus(10, time.Now())
+		Expect(run.Status.Results).To(BeNil())
+		Expect(GetCondition(run.Status.Conditions, ConditionTypeResultValid)).To(BeNil())
+	})
+
+	It("should correctly handle cancel requests", func() {
+		run := &Run{Spec: &RunSpec{}}
+		Expect(run.IsCancelRequested()).To(BeFalse())
+
+		run.Spec.Cancel = true
+		Expect(run.IsCancelRequested()).To(BeTrue())
+
+		run.Spec.Cancel = false
+		run.SetAnnotations(map[string]string{RunCancelAnnotation: "true"})
+		Expect(run.IsCancelRequested()).To(BeTrue())
+	})
+
+	It("should correctly handle mirrored runs", func() {
+		lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+		run := &Run{
+			Spec: &RunSpec{Cancel: true, Attempt: 1, RetryPolicy: &RetryPolicy{MaxRetries: 1}},
+			Status: &RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				Metadata: dbmodels.Run{JobID: 1, RunID: 2, State: &dbmodels.RunState{LifeCycleState: &lifeCycleState}},
+			}},
+		}
+		Expect(run.IsMirrored()).To(BeFalse())
+		Expect(run.IsCancelRequested()).To(BeTrue())
+		Expect(run.IsRerunRequested()).To(BeTrue())
+		Expect(run.GetRetryPolicy(nil)).ToNot(BeNil())
+		Expect(run.IsRemoteKept()).To(BeFalse())
+
+		run.Spec.MirrorRunID = 2
+		Expect(run.IsMirrored()).To(BeTrue())
+		Expect(run.IsCancelRequested()).To(BeFalse())
+		Expect(run.IsRerunRequested()).To(BeFalse())
+		Expect(run.GetRetryPolicy(nil)).To(BeNil())
+		Expect(run.IsRemoteKept()).To(BeTrue())
+	})
+
 	It("should correctly handle finalizers", func() {
 		run := &Run{
 			ObjectMeta: metav1.ObjectMeta{
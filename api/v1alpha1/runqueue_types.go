@@ -0,0 +1,95 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultRunQueueName is the queue of the Runs that do not set a queue name
+const DefaultRunQueueName = "default"
+
+// RunQueueSpec defines the desired state of RunQueue
+type RunQueueSpec struct {
+	// MaxActiveRuns is the number of Runs of the queue that can be active in DataBricks at the same time
+	MaxActiveRuns int32 `json:"max_active_runs"`
+	// PriorityClasses are referenced by the priority_class_name of Runs, Runs with a higher value are submitted first
+	PriorityClasses []RunPriorityClass `json:"priority_classes,omitempty"`
+}
+
+// RunPriorityClass names a priority of the Runs in a queue
+type RunPriorityClass struct {
+	Name  string `json:"name"`
+	Value int32  `json:"value"`
+}
+
+// RunQueueStatus defines the observed state of RunQueue
+type RunQueueStatus struct {
+	// Active is the number of Runs of the queue that are submitted and have not terminated
+	Active int32 `json:"active,omitempty"`
+	// Queued is the number of Runs of the queue that wait to be submitted
+	Queued int32 `json:"queued,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RunQueue is the Schema for the runqueues API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="MaxActive",type="integer",JSONPath=".spec.max_active_runs"
+// +kubebuilder:printcolumn:name="Active",type="integer",JSONPath=".status.active"
+// +kubebuilder:printcolumn:name="Queued",type="integer",JSONPath=".status.queued"
+type RunQueue struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   *RunQueueSpec   `json:"spec,omitempty"`
+	Status *RunQueueStatus `json:"status,omitempty"`
+}
+
+// GetPriority returns the value of the priority class, unknown classes have priority 0
+func (rq *RunQueue) GetPriority(priorityClassName string) int32 {
+	if rq == nil || rq.Spec == nil {
+		return 0
+	}
+	for _, priorityClass := range rq.Spec.PriorityClasses {
+		if priorityClass.Name == priorityClassName {
+			return priorityClass.Value
+		}
+	}
+	return 0
+}
+
+// +kubebuilder:object:root=true
+
+// RunQueueList contains a list of RunQueue
+type RunQueueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RunQueue `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RunQueue{}, &RunQueueList{})
+}
@@ -0,0 +1,169 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RunSetPhase is the phase of a RunSet
+type RunSetPhase string
+
+const (
+	// RunSetPhaseRunning means Runs are still being created or are active
+	RunSetPhaseRunning RunSetPhase = "Running"
+	// RunSetPhaseSucceeded means the number of completions was reached
+	RunSetPhaseSucceeded RunSetPhase = "Succeeded"
+	// RunSetPhaseFailed means too many Runs failed to reach the number of completions
+	RunSetPhaseFailed RunSetPhase = "Failed"
+)
+
+const (
+	// RunSetLabel is set on the Runs created for a RunSet to the name of the RunSet
+	RunSetLabel = "databricks.microsoft.com/run-set"
+	// RunSetIndexLabel is set on the Runs created for a RunSet to the index of their parameters
+	RunSetIndexLabel = "databricks.microsoft.com/run-set-index"
+)
+
+// RunSetSpec defines the desired state of RunSet
+type RunSetSpec struct {
+	// Matrix creates a Run for every combination of the values of the parameters
+	Matrix map[string][]string `json:"matrix,omitempty"`
+	// Items creates a Run for every set of parameters, after the combinations of the matrix
+	Items []map[string]string `json:"items,omitempty"`
+	// Parallelism is the maximum number of Runs that are active at the same time, it defaults to 1
+	Parallelism *int32 `json:"parallelism,omitempty"`
+	// Completions is the number of Runs that have to succeed for the RunSet to succeed,
+	// it defaults to the number of parameter sets
+	Completions *int32 `json:"completions,omitempty"`
+	// RunTemplate is the spec of the Runs, the parameters are set as notebook parameters,
+	// or as template parameters when the spec references a RunTemplate
+	RunTemplate RunSpec `json:"run_template"`
+}
+
+// RunSetStatus defines the observed state of RunSet
+type RunSetStatus struct {
+	Phase RunSetPhase `json:"phase,omitempty"`
+	// Total is the number of parameter sets
+	Total     int32 `json:"total,omitempty"`
+	Active    int32 `json:"active,omitempty"`
+	Succeeded int32 `json:"succeeded,omitempty"`
+	Failed    int32 `json:"failed,omitempty"`
+	// SucceededIndexes and FailedIndexes list the parameter sets whose Run finished,
+	// so that Runs deleted after they finished are not created again
+	SucceededIndexes []int32 `json:"succeeded_indexes,omitempty"`
+	FailedIndexes    []int32 `json:"failed_indexes,omitempty"`
+	// LastCreationTime is when the last batch of Runs was created
+	LastCreationTime *metav1.Time `json:"last_creation_time,omitempty"`
+	StartTime        *metav1.Time `json:"start_time,omitempty"`
+	CompletionTime   *metav1.Time `json:"completion_time,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RunSet is the Schema for the runsets API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Succeeded",type="integer",JSONPath=".status.succeeded"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed"
+// +kubebuilder:printcolumn:name="Active",type="integer",JSONPath=".status.active"
+type RunSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   *RunSetSpec   `json:"spec,omitempty"`
+	Status *RunSetStatus `json:"status,omitempty"`
+}
+
+// IsFinished returns true if the RunSet succeeded or failed
+func (rs *RunSet) IsFinished() bool {
+	return rs.Status != nil && (rs.Status.Phase == RunSetPhaseSucceeded || rs.Status.Phase == RunSetPhaseFailed)
+}
+
+// GetParameterSets returns the parameters of every Run of the set, the combinations of
+// the matrix come first, ordered by parameter name with the last parameter varying fastest
+func (rs *RunSet) GetParameterSets() []map[string]string {
+	var names []string
+	for name := range rs.Spec.Matrix {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sets []map[string]string
+	if len(names) > 0 {
+		sets = []map[string]string{{}}
+	}
+	for _, name := range names {
+		var combinations []map[string]string
+		for _, set := range sets {
+			for _, value := range rs.Spec.Matrix[name] {
+				combination := map[string]string{name: value}
+				for k, v := range set {
+					combination[k] = v
+				}
+				combinations = append(combinations, combination)
+			}
+		}
+		sets = combinations
+	}
+	return append(sets, rs.Spec.Items...)
+}
+
+// GetParallelism returns the maximum number of active Runs
+func (rs *RunSet) GetParallelism() int32 {
+	if rs.Spec.Parallelism == nil {
+		return 1
+	}
+	return *rs.Spec.Parallelism
+}
+
+// GetCompletions returns the number of Runs that have to succeed out of the specified total
+func (rs *RunSet) GetCompletions(total int32) int32 {
+	if rs.Spec.Completions == nil {
+		return total
+	}
+	return *rs.Spec.Completions
+}
+
+// GetRunName returns the name of the Run created for the parameter set with the specified index
+func (rs *RunSet) GetRunName(index int32) string {
+	return fmt.Sprintf("%s-%d", rs.GetName(), index)
+}
+
+// +kubebuilder:object:root=true
+
+// RunSetList contains a list of RunSet
+type RunSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RunSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RunSet{}, &RunSetList{})
+}
@@ -0,0 +1,127 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// These tests are written in BDD-style using Ginkgo framework. Refer to
+// http://onsi.github.io/ginkgo to learn more.
+
+var _ = Describe("RunSet", func() {
+	var (
+		key              types.NamespacedName
+		created, fetched *RunSet
+	)
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	// Add Tests for OpenAPI validation (or additional CRD features) specified in
+	// your API definition.
+	// Avoid adding tests for vanilla CRUD operations because they would
+	// test Kubernetes API server, which isn't the goal here.
+	Context("Create API", func() {
+
+		It("should create an object successfully", func() {
+
+			key = types.NamespacedName{
+				Name:      "foo" + RandomString(5),
+				Namespace: "default",
+			}
+			created = &RunSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: &RunSetSpec{
+					Items: []map[string]string{{"date": "2020-01-01"}},
+				},
+			}
+
+			By("creating an API obj")
+			Expect(k8sClient.Create(context.TODO(), created)).To(Succeed())
+
+			fetched = &RunSet{}
+			Expect(k8sClient.Get(context.TODO(), key, fetched)).To(Succeed())
+			Expect(fetched).To(Equal(created))
+
+			By("deleting the created object")
+			Expect(k8sClient.Delete(context.TODO(), created)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, created)).ToNot(Succeed())
+		})
+
+		It("should correctly expand the parameter sets", func() {
+			rs := &RunSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "backfill"},
+				Spec: &RunSetSpec{
+					Matrix: map[string][]string{
+						"region": {"eu", "us"},
+						"date":   {"2020-01-01", "2020-01-02"},
+					},
+					Items: []map[string]string{{"date": "2020-02-01", "region": "ap"}},
+				},
+			}
+			Expect(rs.GetParameterSets()).To(Equal([]map[string]string{
+				{"date": "2020-01-01", "region": "eu"},
+				{"date": "2020-01-01", "region": "us"},
+				{"date": "2020-01-02", "region": "eu"},
+				{"date": "2020-01-02", "region": "us"},
+				{"date": "2020-02-01", "region": "ap"},
+			}))
+
+			rs.Spec.Matrix = nil
+			Expect(rs.GetParameterSets()).To(HaveLen(1))
+		})
+
+		It("should correctly handle defaults", func() {
+			rs := &RunSet{ObjectMeta: metav1.ObjectMeta{Name: "backfill"}, Spec: &RunSetSpec{}}
+			Expect(rs.GetParallelism()).To(Equal(int32(1)))
+			Expect(rs.GetCompletions(5)).To(Equal(int32(5)))
+			Expect(rs.GetRunName(3)).To(Equal("backfill-3"))
+			Expect(rs.IsFinished()).To(BeFalse())
+
+			parallelism, completions := int32(10), int32(2)
+			rs.Spec.Parallelism = &parallelism
+			rs.Spec.Completions = &completions
+			Expect(rs.GetParallelism()).To(Equal(int32(10)))
+			Expect(rs.GetCompletions(5)).To(Equal(int32(2)))
+			rs.Status = &RunSetStatus{Phase: RunSetPhaseFailed}
+			Expect(rs.IsFinished()).To(BeTrue())
+		})
+	})
+
+})
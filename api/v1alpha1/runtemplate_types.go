@@ -0,0 +1,189 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var templateParameterRegexp = regexp.MustCompile(`\$\(params\.([^)]*)\)`)
+
+// RunTemplateSpec defines the desired state of RunTemplate
+type RunTemplateSpec struct {
+	// Parameters declares the placeholders of the template, which are referenced
+	// in string values of the template as $(params.<name>)
+	Parameters []RunTemplateParameter `json:"parameters,omitempty"`
+	Template   RunSpec                `json:"template"`
+}
+
+// RunTemplateParameter is a placeholder of the template, parameters without a default must be set by the Run
+type RunTemplateParameter struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Default     *string `json:"default,omitempty"`
+}
+
+// RunTemplateRef refers to the RunTemplate a Run is created from
+type RunTemplateRef struct {
+	Name string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+
+// RunTemplate is the Schema for the runtemplates API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type RunTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec *RunTemplateSpec `json:"spec,omitempty"`
+}
+
+// Render merges the spec of the run into the template and replaces the parameter
+// placeholders, returning the effective spec of the run. Fields set in the Run
+// override the template, objects such as new_cluster are merged field by field.
+func (rt *RunTemplate) Render(spec *RunSpec) (*RunSpec, error) {
+	parameters, err := rt.getParameterValues(spec.TemplateParams)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := spec.DeepCopy()
+	overrides.TemplateRef = nil
+	overrides.TemplateParams = nil
+
+	var template, merged map[string]interface{}
+	if err := convertJSON(&rt.Spec.Template, &template); err != nil {
+		return nil, err
+	}
+	if err := convertJSON(overrides, &merged); err != nil {
+		return nil, err
+	}
+	rendered, err := replaceTemplateParameters(mergeJSON(template, merged), parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := &RunSpec{}
+	if err := convertJSON(rendered, effective); err != nil {
+		return nil, err
+	}
+	return effective, nil
+}
+
+func (rt *RunTemplate) getParameterValues(values map[string]string) (map[string]string, error) {
+	parameters := map[string]string{}
+	for _, parameter := range rt.Spec.Parameters {
+		if value, ok := values[parameter.Name]; ok {
+			parameters[parameter.Name] = value
+		} else if parameter.Default != nil {
+			parameters[parameter.Name] = *parameter.Default
+		} else {
+			return nil, fmt.Errorf("parameter %s of template %s is not set", parameter.Name, rt.GetName())
+		}
+	}
+	for name := range values {
+		if _, ok := parameters[name]; !ok {
+			return nil, fmt.Errorf("template %s has no parameter %s", rt.GetName(), name)
+		}
+	}
+	return parameters, nil
+}
+
+func convertJSON(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// mergeJSON overlays the overrides onto the base, merging nested objects
+func mergeJSON(base, overrides map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = map[string]interface{}{}
+	}
+	for key, override := range overrides {
+		baseObject, baseIsObject := base[key].(map[string]interface{})
+		overrideObject, overrideIsObject := override.(map[string]interface{})
+		if baseIsObject && overrideIsObject {
+			base[key] = mergeJSON(baseObject, overrideObject)
+		} else {
+			base[key] = override
+		}
+	}
+	return base
+}
+
+// replaceTemplateParameters replaces the $(params.<name>) placeholders in all string values
+func replaceTemplateParameters(value interface{}, parameters map[string]string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		var err error
+		replaced := templateParameterRegexp.ReplaceAllStringFunc(v, func(placeholder string) string {
+			name := templateParameterRegexp.FindStringSubmatch(placeholder)[1]
+			parameter, ok := parameters[name]
+			if !ok && err == nil {
+				err = fmt.Errorf("template references undeclared parameter %s", name)
+			}
+			return parameter
+		})
+		return replaced, err
+	case map[string]interface{}:
+		for key, item := range v {
+			replaced, err := replaceTemplateParameters(item, parameters)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = replaced
+		}
+	case []interface{}:
+		for i, item := range v {
+			replaced, err := replaceTemplateParameters(item, parameters)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = replaced
+		}
+	}
+	return value, nil
+}
+
+// +kubebuilder:object:root=true
+
+// RunTemplateList contains a list of RunTemplate
+type RunTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RunTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RunTemplate{}, &RunTemplateList{})
+}
@@ -0,0 +1,142 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+
+	"golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// These tests are written in BDD-style using Ginkgo framework. Refer to
+// http://onsi.github.io/ginkgo to learn more.
+
+var _ = Describe("RunTemplate", func() {
+	var (
+		key              types.NamespacedName
+		created, fetched *RunTemplate
+	)
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	// Add Tests for OpenAPI validation (or additional CRD features) specified in
+	// your API definition.
+	// Avoid adding tests for vanilla CRUD operations because they would
+	// test Kubernetes API server, which isn't the goal here.
+	Context("Create API", func() {
+
+		It("should create an object successfully", func() {
+
+			key = types.NamespacedName{
+				Name:      "foo" + RandomString(5),
+				Namespace: "default",
+			}
+			created = &RunTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: &RunTemplateSpec{
+					Template: RunSpec{
+						JobTask: &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/report"}},
+					},
+				},
+			}
+
+			By("creating an API obj")
+			Expect(k8sClient.Create(context.TODO(), created)).To(Succeed())
+
+			fetched = &RunTemplate{}
+			Expect(k8sClient.Get(context.TODO(), key, fetched)).To(Succeed())
+			Expect(fetched).To(Equal(created))
+
+			By("deleting the created object")
+			Expect(k8sClient.Delete(context.TODO(), created)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, created)).ToNot(Succeed())
+		})
+
+		It("should correctly render the effective spec", func() {
+			defaultEnv := "dev"
+			template := &RunTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "report"},
+				Spec: &RunTemplateSpec{
+					Parameters: []RunTemplateParameter{
+						{Name: "env", Default: &defaultEnv},
+						{Name: "date"},
+					},
+					Template: RunSpec{
+						ClusterSpec: ClusterSpec{
+							NewCluster: &dbmodels.NewCluster{SparkVersion: "5.3.x-scala2.11", NodeTypeID: "Standard_D3_v2", NumWorkers: 2},
+						},
+						JobTask: &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{
+							NotebookPath:   "/reports/$(params.env)/daily",
+							BaseParameters: map[string]string{"date": "$(params.date)"},
+						}},
+						TimeoutSeconds: 600,
+					},
+				},
+			}
+
+			spec, err := template.Render(&RunSpec{
+				TemplateRef:    &RunTemplateRef{Name: "report"},
+				TemplateParams: map[string]string{"date": "2020-01-01"},
+				ClusterSpec:    ClusterSpec{NewCluster: &dbmodels.NewCluster{NumWorkers: 8}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.TemplateRef).To(BeNil())
+			Expect(spec.NotebookTask.NotebookPath).To(Equal("/reports/dev/daily"))
+			Expect(spec.NotebookTask.BaseParameters).To(Equal(map[string]string{"date": "2020-01-01"}))
+			Expect(spec.NewCluster.NumWorkers).To(Equal(int32(8)))
+			Expect(spec.NewCluster.SparkVersion).To(Equal("5.3.x-scala2.11"))
+			Expect(spec.TimeoutSeconds).To(Equal(int32(600)))
+			Expect(template.Spec.Template.NotebookTask.NotebookPath).To(Equal("/reports/$(params.env)/daily"))
+
+			By("requiring parameters without a default")
+			_, err = template.Render(&RunSpec{})
+			Expect(err).To(HaveOccurred())
+
+			By("rejecting unknown parameters")
+			_, err = template.Render(&RunSpec{TemplateParams: map[string]string{"date": "2020-01-01", "region": "eu"}})
+			Expect(err).To(HaveOccurred())
+
+			By("rejecting undeclared placeholders")
+			template.Spec.Template.TimeoutSeconds = 0
+			template.Spec.Template.RunName = "$(params.name)"
+			_, err = template.Render(&RunSpec{TemplateParams: map[string]string{"date": "2020-01-01"}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+})
@@ -0,0 +1,254 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RunWorkflowStepCondition decides whether a step runs once its dependencies have finished
+type RunWorkflowStepCondition string
+
+const (
+	// RunWorkflowStepOnSuccess runs the step when all of its dependencies succeeded
+	RunWorkflowStepOnSuccess RunWorkflowStepCondition = "on_success"
+	// RunWorkflowStepOnFailure runs the step when any of its dependencies failed
+	RunWorkflowStepOnFailure RunWorkflowStepCondition = "on_failure"
+	// RunWorkflowStepAlways runs the step whatever the outcome of its dependencies
+	RunWorkflowStepAlways RunWorkflowStepCondition = "always"
+)
+
+// RunWorkflowPhase is the phase of a workflow or of one of its steps
+type RunWorkflowPhase string
+
+const (
+	// RunWorkflowPhasePending means the step is waiting for its dependencies
+	RunWorkflowPhasePending RunWorkflowPhase = "Pending"
+	// RunWorkflowPhaseRunning means the Run of the step, or a step of the workflow, is running
+	RunWorkflowPhaseRunning RunWorkflowPhase = "Running"
+	// RunWorkflowPhaseSucceeded means the Run of the step, or every step of the workflow, succeeded
+	RunWorkflowPhaseSucceeded RunWorkflowPhase = "Succeeded"
+	// RunWorkflowPhaseFailed means the Run of the step, or a step of the workflow, failed
+	RunWorkflowPhaseFailed RunWorkflowPhase = "Failed"
+	// RunWorkflowPhaseSkipped means the condition of the step was not met
+	RunWorkflowPhaseSkipped RunWorkflowPhase = "Skipped"
+)
+
+const (
+	// RunWorkflowLabel is set on the Runs created for a workflow to the name of the workflow
+	RunWorkflowLabel = "databricks.microsoft.com/workflow"
+	// RunWorkflowStepLabel is set on the Runs created for a workflow to the name of their step
+	RunWorkflowStepLabel = "databricks.microsoft.com/workflow-step"
+)
+
+// RunWorkflowSpec defines the desired state of RunWorkflow
+type RunWorkflowSpec struct {
+	Steps []RunWorkflowStep `json:"steps"`
+}
+
+// RunWorkflowStep creates a Run from its template once its dependencies have finished
+type RunWorkflowStep struct {
+	Name      string   `json:"name"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	// When decides whether the step runs depending on the outcome of its dependencies, it defaults to on_success
+	// +kubebuilder:validation:Enum=on_success;on_failure;always
+	When RunWorkflowStepCondition `json:"when,omitempty"`
+	// ParametersFrom sets notebook parameters from the notebook output of dependencies
+	ParametersFrom []RunWorkflowParameter `json:"parameters_from,omitempty"`
+	Template       RunSpec                `json:"template"`
+}
+
+// RunWorkflowParameter takes a notebook parameter from the value a dependency passed to dbutils.notebook.exit
+type RunWorkflowParameter struct {
+	Name string `json:"name"`
+	Step string `json:"step"`
+	// Key reads a single field when the notebook exits with a JSON object
+	Key string `json:"key,omitempty"`
+}
+
+// RunWorkflowStatus defines the observed state of RunWorkflow
+type RunWorkflowStatus struct {
+	Phase          RunWorkflowPhase        `json:"phase,omitempty"`
+	Message        string                  `json:"message,omitempty"`
+	Steps          []RunWorkflowStepStatus `json:"steps,omitempty"`
+	StartTime      *metav1.Time            `json:"start_time,omitempty"`
+	CompletionTime *metav1.Time            `json:"completion_time,omitempty"`
+}
+
+// RunWorkflowStepStatus is the observed state of a single step
+type RunWorkflowStepStatus struct {
+	Name    string           `json:"name"`
+	Phase   RunWorkflowPhase `json:"phase,omitempty"`
+	RunName string           `json:"run_name,omitempty"`
+	// Output is the notebook output of the Run once it has finished
+	Output  string `json:"output,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// IsFinished returns true if the step will not change phase anymore
+func (s *RunWorkflowStepStatus) IsFinished() bool {
+	switch s.Phase {
+	case RunWorkflowPhaseSucceeded, RunWorkflowPhaseFailed, RunWorkflowPhaseSkipped:
+		return true
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+
+// RunWorkflow is the Schema for the runworkflows API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+type RunWorkflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   *RunWorkflowSpec   `json:"spec,omitempty"`
+	Status *RunWorkflowStatus `json:"status,omitempty"`
+}
+
+// IsBeingDeleted returns true if a deletion timestamp is set
+func (rw *RunWorkflow) IsBeingDeleted() bool {
+	return !rw.ObjectMeta.DeletionTimestamp.IsZero()
+}
+
+// IsFinished returns true if every step of the workflow has finished
+func (rw *RunWorkflow) IsFinished() bool {
+	return rw.Status != nil && (rw.Status.Phase == RunWorkflowPhaseSucceeded || rw.Status.Phase == RunWorkflowPhaseFailed)
+}
+
+// GetStepRunName returns the name of the Run created for the specified step
+func (rw *RunWorkflow) GetStepRunName(step string) string {
+	return fmt.Sprintf("%s-%s", rw.GetName(), step)
+}
+
+// GetStepStatus returns the status of the specified step, or nil if it has not been observed
+func (rw *RunWorkflow) GetStepStatus(step string) *RunWorkflowStepStatus {
+	if rw.Status == nil {
+		return nil
+	}
+	for i := range rw.Status.Steps {
+		if rw.Status.Steps[i].Name == step {
+			return &rw.Status.Steps[i]
+		}
+	}
+	return nil
+}
+
+// SortedSteps validates the dependencies of the steps and returns them in an
+// order where every step comes after the steps it depends on
+func (rw *RunWorkflow) SortedSteps() ([]RunWorkflowStep, error) {
+	if rw.Spec == nil || len(rw.Spec.Steps) == 0 {
+		return nil, fmt.Errorf("workflow has no steps")
+	}
+
+	steps := map[string]RunWorkflowStep{}
+	for _, step := range rw.Spec.Steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("step without name")
+		}
+		if _, ok := steps[step.Name]; ok {
+			return nil, fmt.Errorf("duplicate step %s", step.Name)
+		}
+		steps[step.Name] = step
+	}
+	for _, step := range rw.Spec.Steps {
+		dependencies := map[string]bool{}
+		for _, dependency := range step.DependsOn {
+			if _, ok := steps[dependency]; !ok {
+				return nil, fmt.Errorf("step %s depends on unknown step %s", step.Name, dependency)
+			}
+			dependencies[dependency] = true
+		}
+		for _, parameter := range step.ParametersFrom {
+			if !dependencies[parameter.Step] {
+				return nil, fmt.Errorf("step %s takes parameter %s from step %s which it does not depend on", step.Name, parameter.Name, parameter.Step)
+			}
+		}
+	}
+
+	// depth first search, visiting marks steps whose dependencies are being sorted
+	var sorted []RunWorkflowStep
+	visiting := map[string]bool{}
+	visited := map[string]bool{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		if visited[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("step %s is part of a dependency cycle", name)
+		}
+		visiting[name] = true
+		for _, dependency := range steps[name].DependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+		sorted = append(sorted, steps[name])
+		return nil
+	}
+	for _, step := range rw.Spec.Steps {
+		if err := visit(step.Name); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// RunWorkflowFinalizerName is the name of the run workflow finalizer
+const RunWorkflowFinalizerName = "runworkflow.finalizers.databricks.microsoft.com"
+
+// HasFinalizer returns true if the item has the specified finalizer
+func (rw *RunWorkflow) HasFinalizer(finalizerName string) bool {
+	return containsString(rw.ObjectMeta.Finalizers, finalizerName)
+}
+
+// AddFinalizer adds the specified finalizer
+func (rw *RunWorkflow) AddFinalizer(finalizerName string) {
+	rw.ObjectMeta.Finalizers = append(rw.ObjectMeta.Finalizers, finalizerName)
+}
+
+// RemoveFinalizer removes the specified finalizer
+func (rw *RunWorkflow) RemoveFinalizer(finalizerName string) {
+	rw.ObjectMeta.Finalizers = removeString(rw.ObjectMeta.Finalizers, finalizerName)
+}
+
+// +kubebuilder:object:root=true
+
+// RunWorkflowList contains a list of RunWorkflow
+type RunWorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RunWorkflow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RunWorkflow{}, &RunWorkflowList{})
+}
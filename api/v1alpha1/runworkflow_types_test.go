@@ -0,0 +1,185 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	"golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// These tests are written in BDD-style using Ginkgo framework. Refer to
+// http://onsi.github.io/ginkgo to learn more.
+
+var _ = Describe("RunWorkflow", func() {
+	var (
+		key              types.NamespacedName
+		created, fetched *RunWorkflow
+	)
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	// Add Tests for OpenAPI validation (or additional CRD features) specified in
+	// your API definition.
+	// Avoid adding tests for vanilla CRUD operations because they would
+	// test Kubernetes API server, which isn't the goal here.
+	Context("Create API", func() {
+
+		It("should create an object successfully", func() {
+
+			key = types.NamespacedName{
+				Name:      "foo" + RandomString(5),
+				Namespace: "default",
+			}
+			created = &RunWorkflow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: &RunWorkflowSpec{
+					Steps: []RunWorkflowStep{{Name: "prepare"}},
+				},
+			}
+
+			By("creating an API obj")
+			Expect(k8sClient.Create(context.TODO(), created)).To(Succeed())
+
+			fetched = &RunWorkflow{}
+			Expect(k8sClient.Get(context.TODO(), key, fetched)).To(Succeed())
+			Expect(fetched).To(Equal(created))
+
+			By("deleting the created object")
+			Expect(k8sClient.Delete(context.TODO(), created)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, created)).ToNot(Succeed())
+		})
+
+		It("should correctly sort steps", func() {
+			rw := &RunWorkflow{
+				Spec: &RunWorkflowSpec{
+					Steps: []RunWorkflowStep{
+						{Name: "notify", DependsOn: []string{"train", "prepare"}, When: RunWorkflowStepAlways},
+						{Name: "train", DependsOn: []string{"prepare"}, ParametersFrom: []RunWorkflowParameter{{Name: "table", Step: "prepare"}}},
+						{Name: "prepare"},
+					},
+				},
+			}
+			steps, err := rw.SortedSteps()
+			Expect(err).ToNot(HaveOccurred())
+			var names []string
+			for _, step := range steps {
+				names = append(names, step.Name)
+			}
+			Expect(names).To(Equal([]string{"prepare", "train", "notify"}))
+
+			rw.Spec.Steps[1].ParametersFrom[0].Step = "notify"
+			_, err = rw.SortedSteps()
+			Expect(err).To(HaveOccurred())
+
+			rw.Spec.Steps[1].ParametersFrom = nil
+			rw.Spec.Steps[2].DependsOn = []string{"notify"}
+			_, err = rw.SortedSteps()
+			Expect(err).To(MatchError(ContainSubstring("cycle")))
+
+			rw.Spec.Steps[2].DependsOn = []string{"cleanup"}
+			_, err = rw.SortedSteps()
+			Expect(err).To(MatchError(ContainSubstring("unknown step cleanup")))
+
+			rw.Spec.Steps[2] = RunWorkflowStep{Name: "train"}
+			_, err = rw.SortedSteps()
+			Expect(err).To(MatchError(ContainSubstring("duplicate step train")))
+
+			_, err = (&RunWorkflow{Spec: &RunWorkflowSpec{}}).SortedSteps()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should correctly handle step status", func() {
+			rw := &RunWorkflow{
+				ObjectMeta: metav1.ObjectMeta{Name: "nightly"},
+			}
+			Expect(rw.GetStepRunName("train")).To(Equal("nightly-train"))
+			Expect(rw.GetStepStatus("train")).To(BeNil())
+			Expect(rw.IsFinished()).To(BeFalse())
+
+			rw.Status = &RunWorkflowStatus{
+				Phase: RunWorkflowPhaseRunning,
+				Steps: []RunWorkflowStepStatus{
+					{Name: "prepare", Phase: RunWorkflowPhaseSucceeded},
+					{Name: "train", Phase: RunWorkflowPhaseRunning},
+				},
+			}
+			Expect(rw.GetStepStatus("prepare").IsFinished()).To(BeTrue())
+			Expect(rw.GetStepStatus("train").IsFinished()).To(BeFalse())
+			Expect(rw.IsFinished()).To(BeFalse())
+
+			rw.Status.Phase = RunWorkflowPhaseFailed
+			Expect(rw.IsFinished()).To(BeTrue())
+		})
+
+		It("should correctly set notebook parameters", func() {
+			spec := &RunSpec{JobName: "nightly"}
+			Expect(spec.SetNotebookParameter("table", "sales")).To(Succeed())
+			Expect(spec.NotebookParams).To(Equal(map[string]string{"table": "sales"}))
+
+			spec = &RunSpec{}
+			Expect(spec.SetNotebookParameter("table", "sales")).ToNot(Succeed())
+
+			spec.JobTask = &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/train"}}
+			Expect(spec.SetNotebookParameter("table", "sales")).To(Succeed())
+			Expect(spec.NotebookTask.BaseParameters).To(Equal(map[string]string{"table": "sales"}))
+		})
+
+		It("should correctly handle finalizers", func() {
+			rw := &RunWorkflow{
+				ObjectMeta: metav1.ObjectMeta{
+					DeletionTimestamp: &metav1.Time{
+						Time: time.Now(),
+					},
+				},
+			}
+			Expect(rw.IsBeingDeleted()).To(BeTrue())
+
+			rw.AddFinalizer(RunWorkflowFinalizerName)
+			Expect(len(rw.GetFinalizers())).To(Equal(1))
+			Expect(rw.HasFinalizer(RunWorkflowFinalizerName)).To(BeTrue())
+
+			rw.RemoveFinalizer(RunWorkflowFinalizerName)
+			Expect(len(rw.GetFinalizers())).To(Equal(0))
+			Expect(rw.HasFinalizer(RunWorkflowFinalizerName)).To(BeFalse())
+		})
+	})
+
+})
@@ -0,0 +1,151 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy decides what happens when a run is due while a previous one is still running
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent starts the run alongside the runs that are still running
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the run while a previous run is still running
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent cancels the runs that are still running and starts the new run
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+const (
+	// ScheduledRunLabel is set on the Runs created for a ScheduledRun to its name
+	ScheduledRunLabel = "databricks.microsoft.com/scheduled-run"
+	// ScheduledRunTimeAnnotation is set on the Runs created for a ScheduledRun to the time they were scheduled for
+	ScheduledRunTimeAnnotation = "databricks.microsoft.com/scheduled-time"
+
+	defaultSuccessfulRunsHistoryLimit = 3
+	defaultFailedRunsHistoryLimit     = 1
+)
+
+// ScheduledRunSpec defines the desired state of ScheduledRun
+type ScheduledRunSpec struct {
+	// Schedule is a cron expression, such as "0 2 * * *"
+	Schedule string `json:"schedule"`
+	// TimeZone is the IANA time zone the schedule is evaluated in, it defaults to UTC
+	TimeZone string `json:"time_zone,omitempty"`
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrency_policy,omitempty"`
+	// StartingDeadlineSeconds is how late a run may start, runs that missed the deadline are skipped
+	StartingDeadlineSeconds *int64 `json:"starting_deadline_seconds,omitempty"`
+	// Suspend stops new runs from being created, runs already started are not affected
+	Suspend bool `json:"suspend,omitempty"`
+	// SuccessfulRunsHistoryLimit is the number of successful Runs to keep, it defaults to 3
+	SuccessfulRunsHistoryLimit *int32 `json:"successful_runs_history_limit,omitempty"`
+	// FailedRunsHistoryLimit is the number of failed Runs to keep, it defaults to 1
+	FailedRunsHistoryLimit *int32  `json:"failed_runs_history_limit,omitempty"`
+	RunTemplate            RunSpec `json:"run_template"`
+}
+
+// ScheduledRunStatus defines the observed state of ScheduledRun
+type ScheduledRunStatus struct {
+	// Active lists the names of the Runs that are still running
+	Active             []string     `json:"active,omitempty"`
+	LastScheduleTime   *metav1.Time `json:"last_schedule_time,omitempty"`
+	LastSuccessfulTime *metav1.Time `json:"last_successful_time,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScheduledRun is the Schema for the scheduledruns API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Suspend",type="boolean",JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="LastSchedule",type="date",JSONPath=".status.last_schedule_time"
+type ScheduledRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   *ScheduledRunSpec   `json:"spec,omitempty"`
+	Status *ScheduledRunStatus `json:"status,omitempty"`
+}
+
+// GetSchedule parses the cron expression of the schedule
+func (sr *ScheduledRun) GetSchedule() (cron.Schedule, error) {
+	return cron.ParseStandard(sr.Spec.Schedule)
+}
+
+// GetLocation returns the time zone the schedule is evaluated in
+func (sr *ScheduledRun) GetLocation() (*time.Location, error) {
+	if sr.Spec.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(sr.Spec.TimeZone)
+}
+
+// GetConcurrencyPolicy returns the concurrency policy, which defaults to Allow
+func (sr *ScheduledRun) GetConcurrencyPolicy() ConcurrencyPolicy {
+	if sr.Spec.ConcurrencyPolicy == "" {
+		return AllowConcurrent
+	}
+	return sr.Spec.ConcurrencyPolicy
+}
+
+// GetSuccessfulRunsHistoryLimit returns the number of successful Runs to keep
+func (sr *ScheduledRun) GetSuccessfulRunsHistoryLimit() int {
+	if sr.Spec.SuccessfulRunsHistoryLimit == nil {
+		return defaultSuccessfulRunsHistoryLimit
+	}
+	return int(*sr.Spec.SuccessfulRunsHistoryLimit)
+}
+
+// GetFailedRunsHistoryLimit returns the number of failed Runs to keep
+func (sr *ScheduledRun) GetFailedRunsHistoryLimit() int {
+	if sr.Spec.FailedRunsHistoryLimit == nil {
+		return defaultFailedRunsHistoryLimit
+	}
+	return int(*sr.Spec.FailedRunsHistoryLimit)
+}
+
+// GetRunName returns the name of the Run created for the specified schedule time
+func (sr *ScheduledRun) GetRunName(scheduledTime time.Time) string {
+	return sr.GetName() + "-" + scheduledTime.UTC().Format("200601021504")
+}
+
+// +kubebuilder:object:root=true
+
+// ScheduledRunList contains a list of ScheduledRun
+type ScheduledRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScheduledRun `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScheduledRun{}, &ScheduledRunList{})
+}
@@ -0,0 +1,133 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// These tests are written in BDD-style using Ginkgo framework. Refer to
+// http://onsi.github.io/ginkgo to learn more.
+
+var _ = Describe("ScheduledRun", func() {
+	var (
+		key              types.NamespacedName
+		created, fetched *ScheduledRun
+	)
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	// Add Tests for OpenAPI validation (or additional CRD features) specified in
+	// your API definition.
+	// Avoid adding tests for vanilla CRUD operations because they would
+	// test Kubernetes API server, which isn't the goal here.
+	Context("Create API", func() {
+
+		It("should create an object successfully", func() {
+
+			key = types.NamespacedName{
+				Name:      "foo" + RandomString(5),
+				Namespace: "default",
+			}
+			created = &ScheduledRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: &ScheduledRunSpec{
+					Schedule: "0 2 * * *",
+				},
+			}
+
+			By("creating an API obj")
+			Expect(k8sClient.Create(context.TODO(), created)).To(Succeed())
+
+			fetched = &ScheduledRun{}
+			Expect(k8sClient.Get(context.TODO(), key, fetched)).To(Succeed())
+			Expect(fetched).To(Equal(created))
+
+			By("deleting the created object")
+			Expect(k8sClient.Delete(context.TODO(), created)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, created)).ToNot(Succeed())
+		})
+
+		It("should correctly handle defaults", func() {
+			sr := &ScheduledRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "nightly"},
+				Spec:       &ScheduledRunSpec{Schedule: "0 2 * * *"},
+			}
+			Expect(sr.GetConcurrencyPolicy()).To(Equal(AllowConcurrent))
+			Expect(sr.GetSuccessfulRunsHistoryLimit()).To(Equal(3))
+			Expect(sr.GetFailedRunsHistoryLimit()).To(Equal(1))
+			location, err := sr.GetLocation()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(location).To(Equal(time.UTC))
+
+			var zero int32
+			sr.Spec.ConcurrencyPolicy = ForbidConcurrent
+			sr.Spec.SuccessfulRunsHistoryLimit = &zero
+			sr.Spec.FailedRunsHistoryLimit = &zero
+			Expect(sr.GetConcurrencyPolicy()).To(Equal(ForbidConcurrent))
+			Expect(sr.GetSuccessfulRunsHistoryLimit()).To(Equal(0))
+			Expect(sr.GetFailedRunsHistoryLimit()).To(Equal(0))
+		})
+
+		It("should correctly handle schedules", func() {
+			sr := &ScheduledRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "nightly"},
+				Spec:       &ScheduledRunSpec{Schedule: "0 2 * * *", TimeZone: "Europe/Amsterdam"},
+			}
+			schedule, err := sr.GetSchedule()
+			Expect(err).ToNot(HaveOccurred())
+			location, err := sr.GetLocation()
+			Expect(err).ToNot(HaveOccurred())
+			next := schedule.Next(time.Date(2020, 1, 1, 12, 0, 0, 0, location))
+			Expect(next.UTC()).To(Equal(time.Date(2020, 1, 2, 1, 0, 0, 0, time.UTC)))
+			Expect(sr.GetRunName(next)).To(Equal("nightly-202001020100"))
+
+			sr.Spec.Schedule = "every night"
+			_, err = sr.GetSchedule()
+			Expect(err).To(HaveOccurred())
+
+			sr.Spec.TimeZone = "Nowhere/Special"
+			_, err = sr.GetLocation()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+})
@@ -45,8 +45,9 @@ type SecretScopeSpec struct {
 type SecretScopeStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	SecretScope              *dbmodels.SecretScope `json:"secretscope,omitempty"`
-	SecretInClusterAvailable bool                  `json:"secretinclusteravailable,omitempty"`
+	SecretScope              *dbmodels.SecretScope  `json:"secretscope,omitempty"`
+	SecretInClusterAvailable bool                   `json:"secretinclusteravailable,omitempty"`
+	Keys                     []SecretScopeKeyStatus `json:"keys,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -75,6 +76,16 @@ func (ss *SecretScope) IsBeingDeleted() bool {
 	return !ss.ObjectMeta.DeletionTimestamp.IsZero()
 }
 
+// GetKeyStatus returns the status of the specified key, or nil if the key has not been synced
+func (ss *SecretScope) GetKeyStatus(key string) *SecretScopeKeyStatus {
+	for i := range ss.Status.Keys {
+		if ss.Status.Keys[i].Key == key {
+			return &ss.Status.Keys[i]
+		}
+	}
+	return nil
+}
+
 // SecretScopeFinalizerName is the name of the secretscope finalizer
 const SecretScopeFinalizerName = "secretscope.finalizers.databricks.microsoft.com"
 
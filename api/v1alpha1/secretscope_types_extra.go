@@ -24,6 +24,24 @@ SOFTWARE.
 
 package v1alpha1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SecretScopeKeySource describes where the value of a secret scope key comes from
+type SecretScopeKeySource string
+
+const (
+	// SecretScopeKeySourceInline is a key whose value is set in string_value
+	SecretScopeKeySourceInline SecretScopeKeySource = "inline"
+	// SecretScopeKeySourceByte is a key whose value is set in byte_value
+	SecretScopeKeySourceByte SecretScopeKeySource = "byte"
+	// SecretScopeKeySourceSecretRef is a key whose value is read from a k8s secret
+	SecretScopeKeySourceSecretRef SecretScopeKeySource = "secretRef"
+	// SecretScopeKeySourceConfigMap is a key whose value is read from a k8s config map
+	SecretScopeKeySourceConfigMap SecretScopeKeySource = "configMap"
+)
+
 // SecretScopeSecret represents a secret in a secret scope
 type SecretScopeSecret struct {
 	Key         string                `json:"key,omitempty"`
@@ -32,18 +50,43 @@ type SecretScopeSecret struct {
 	ValueFrom   *SecretScopeValueFrom `json:"value_from,omitempty"`
 }
 
+// Source returns where the value of the secret comes from, or an empty string if no value is set
+func (secret SecretScopeSecret) Source() SecretScopeKeySource {
+	if secret.StringValue != "" {
+		return SecretScopeKeySourceInline
+	} else if secret.ByteValue != "" {
+		return SecretScopeKeySourceByte
+	} else if secret.ValueFrom != nil {
+		if secret.ValueFrom.ConfigMapKeyRef != nil {
+			return SecretScopeKeySourceConfigMap
+		}
+		return SecretScopeKeySourceSecretRef
+	}
+	return ""
+}
+
+// SecretScopeKeyStatus reports the last sync of a key managed in a secret scope
+type SecretScopeKeyStatus struct {
+	Key             string               `json:"key,omitempty"`
+	Source          SecretScopeKeySource `json:"source,omitempty"`
+	ResourceVersion string               `json:"resource_version,omitempty"`
+	LastSyncedTime  *metav1.Time         `json:"last_synced_time,omitempty"`
+	Error           string               `json:"error,omitempty"`
+}
+
 // SecretScopeACL represents ACLs for a secret scope
 type SecretScopeACL struct {
 	Principal  string `json:"principal,omitempty"`
 	Permission string `json:"permission,omitempty"`
 }
 
-// SecretScopeValueFrom references a secret scope
+// SecretScopeValueFrom references a k8s secret or config map holding the value of a secret scope key
 type SecretScopeValueFrom struct {
-	SecretKeyRef SecretScopeKeyRef `json:"secret_key_ref,omitempty"`
+	SecretKeyRef    SecretScopeKeyRef  `json:"secret_key_ref,omitempty"`
+	ConfigMapKeyRef *SecretScopeKeyRef `json:"config_map_key_ref,omitempty"`
 }
 
-// SecretScopeKeyRef refers to a secret scope Key
+// SecretScopeKeyRef refers to a key in a k8s secret or config map
 type SecretScopeKeyRef struct {
 	Name string `json:"name,omitempty"`
 	Key  string `json:"key,omitempty"`
@@ -105,6 +105,32 @@ var _ = Describe("SecretScope", func() {
 			Expect(len(secretScope.GetFinalizers())).To(Equal(0))
 			Expect(secretScope.HasFinalizer(SecretScopeFinalizerName)).To(BeFalse())
 		})
+
+		It("should correctly report the source of secrets", func() {
+			Expect(SecretScopeSecret{Key: "a", StringValue: "value"}.Source()).To(Equal(SecretScopeKeySourceInline))
+			Expect(SecretScopeSecret{Key: "b", ByteValue: "dGVzdA=="}.Source()).To(Equal(SecretScopeKeySourceByte))
+			Expect(SecretScopeSecret{Key: "c", ValueFrom: &SecretScopeValueFrom{
+				SecretKeyRef: SecretScopeKeyRef{Name: "secret", Key: "key"},
+			}}.Source()).To(Equal(SecretScopeKeySourceSecretRef))
+			Expect(SecretScopeSecret{Key: "d", ValueFrom: &SecretScopeValueFrom{
+				ConfigMapKeyRef: &SecretScopeKeyRef{Name: "configmap", Key: "key"},
+			}}.Source()).To(Equal(SecretScopeKeySourceConfigMap))
+			Expect(SecretScopeSecret{Key: "e"}.Source()).To(BeEmpty())
+		})
+
+		It("should correctly find key status", func() {
+			secretScope := &SecretScope{
+				Status: SecretScopeStatus{
+					Keys: []SecretScopeKeyStatus{
+						{Key: "a", Source: SecretScopeKeySourceInline},
+						{Key: "b", Source: SecretScopeKeySourceSecretRef, ResourceVersion: "42"},
+					},
+				},
+			}
+			Expect(secretScope.GetKeyStatus("b")).ToNot(BeNil())
+			Expect(secretScope.GetKeyStatus("b").ResourceVersion).To(Equal("42"))
+			Expect(secretScope.GetKeyStatus("c")).To(BeNil())
+		})
 	})
 
 })
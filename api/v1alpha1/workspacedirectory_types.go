@@ -0,0 +1,157 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package v1alpha1
+
+import (
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkspaceDirectorySpec defines the desired state of WorkspaceDirectory
+type WorkspaceDirectorySpec struct {
+	// Path is the workspace folder the notebooks are synced into
+	Path string `json:"path,omitempty"`
+	// ConfigMaps lists config maps whose keys are each synced as a notebook
+	ConfigMaps []WorkspaceDirectoryConfigMapSource `json:"config_maps,omitempty"`
+	// GitSnapshot syncs the notebooks in an archive of a git repository
+	GitSnapshot *WorkspaceDirectoryGitSnapshot `json:"git_snapshot,omitempty"`
+}
+
+// WorkspaceDirectoryConfigMapSource syncs every key of a config map as a notebook
+type WorkspaceDirectoryConfigMapSource struct {
+	Name string `json:"name,omitempty"`
+	// Prefix is prepended to each key to give the notebook path relative to the folder
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// WorkspaceDirectoryGitSnapshot is a gzipped tar archive of a git repository, such as
+// https://github.com/<org>/<repo>/archive/<commit>.tar.gz
+type WorkspaceDirectoryGitSnapshot struct {
+	Archive ContentHTTPSource `json:"archive,omitempty"`
+	// StripComponents removes this many leading directories from the paths in the archive
+	StripComponents int32 `json:"strip_components,omitempty"`
+	// Path is the directory within the archive to sync, after leading directories are stripped
+	Path string `json:"path,omitempty"`
+}
+
+// WorkspaceDirectoryStatus defines the observed state of WorkspaceDirectory
+type WorkspaceDirectoryStatus struct {
+	Items []WorkspaceDirectoryItemStatus `json:"items,omitempty"`
+	// SourceVersion identifies the versions of the config maps and snapshot that were last synced
+	SourceVersion  string       `json:"source_version,omitempty"`
+	SyncedItems    int32        `json:"synced_items,omitempty"`
+	FailedItems    int32        `json:"failed_items,omitempty"`
+	LastSyncedTime *metav1.Time `json:"last_synced_time,omitempty"`
+}
+
+// WorkspaceDirectoryItemStatus reports the last sync of a single notebook
+type WorkspaceDirectoryItemStatus struct {
+	// Path is the full workspace path of the notebook
+	Path string `json:"path,omitempty"`
+	// SourcePath is the path of the file the notebook was read from, relative to the folder
+	SourcePath     string                `json:"source_path,omitempty"`
+	Language       dbmodels.Language     `json:"language,omitempty"`
+	Format         dbmodels.ExportFormat `json:"format,omitempty"`
+	ObjectHash     string                `json:"object_hash,omitempty"`
+	LastSyncedTime *metav1.Time          `json:"last_synced_time,omitempty"`
+	Error          string                `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceDirectory is the Schema for the workspacedirectories API
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Path",type="string",JSONPath=".spec.path"
+// +kubebuilder:printcolumn:name="Synced",type="integer",JSONPath=".status.synced_items"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed_items"
+type WorkspaceDirectory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   *WorkspaceDirectorySpec   `json:"spec,omitempty"`
+	Status *WorkspaceDirectoryStatus `json:"status,omitempty"`
+}
+
+// IsBeingDeleted returns true if a deletion timestamp is set
+func (wd *WorkspaceDirectory) IsBeingDeleted() bool {
+	return !wd.ObjectMeta.DeletionTimestamp.IsZero()
+}
+
+// IsSubmitted returns true if the folder has been synced to DataBricks at least once
+func (wd *WorkspaceDirectory) IsSubmitted() bool {
+	return wd.Status != nil && wd.Status.LastSyncedTime != nil
+}
+
+// IsUpToDate tells you whether the last sync used the specified source version and had no failures
+func (wd *WorkspaceDirectory) IsUpToDate(sourceVersion string) bool {
+	if !wd.IsSubmitted() {
+		return false
+	}
+	return wd.Status.SourceVersion == sourceVersion && wd.Status.FailedItems == 0
+}
+
+// GetItemStatus returns the status of the notebook at the specified workspace path, or nil if it has not been synced
+func (wd *WorkspaceDirectory) GetItemStatus(path string) *WorkspaceDirectoryItemStatus {
+	if wd.Status == nil {
+		return nil
+	}
+	for i := range wd.Status.Items {
+		if wd.Status.Items[i].Path == path {
+			return &wd.Status.Items[i]
+		}
+	}
+	return nil
+}
+
+// WorkspaceDirectoryFinalizerName is the name of the workspace directory finalizer
+const WorkspaceDirectoryFinalizerName = "workspacedirectory.finalizers.databricks.microsoft.com"
+
+// HasFinalizer returns true if the item has the specified finalizer
+func (wd *WorkspaceDirectory) HasFinalizer(finalizerName string) bool {
+	return containsString(wd.ObjectMeta.Finalizers, finalizerName)
+}
+
+// AddFinalizer adds the specified finalizer
+func (wd *WorkspaceDirectory) AddFinalizer(finalizerName string) {
+	wd.ObjectMeta.Finalizers = append(wd.ObjectMeta.Finalizers, finalizerName)
+}
+
+// RemoveFinalizer removes the specified finalizer
+func (wd *WorkspaceDirectory) RemoveFinalizer(finalizerName string) {
+	wd.ObjectMeta.Finalizers = removeString(wd.ObjectMeta.Finalizers, finalizerName)
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceDirectoryList contains a list of WorkspaceDirectory
+type WorkspaceDirectoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceDirectory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspaceDirectory{}, &WorkspaceDirectoryList{})
+}
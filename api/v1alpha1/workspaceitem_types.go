@@ -28,6 +28,8 @@ import (
 	"crypto/sha1"
 	"encoding/base64"
 	"fmt"
+	"path"
+	"time"
 
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,16 +37,74 @@ import (
 
 // WorkspaceItemSpec defines the desired state of WorkspaceItem
 type WorkspaceItemSpec struct {
-	Content  string                `json:"content,omitempty"`
-	Path     string                `json:"path,omitempty"`
-	Language dbmodels.Language     `json:"language,omitempty"`
-	Format   dbmodels.ExportFormat `json:"format,omitempty"`
+	Content string `json:"content,omitempty"`
+	// ContentFrom is used instead of Content to read the notebook from a config map, secret or URL
+	ContentFrom *ContentFrom          `json:"content_from,omitempty"`
+	Path        string                `json:"path,omitempty"`
+	Language    dbmodels.Language     `json:"language,omitempty"`
+	Format      dbmodels.ExportFormat `json:"format,omitempty"`
+	// DriftDetection periodically verifies that the item in the workspace has not been changed or deleted
+	DriftDetection *DriftDetection `json:"drift_detection,omitempty"`
+	// Direction is import, the default, to manage the item from the spec, or export to mirror
+	// the item in the workspace into a config map
+	// +kubebuilder:validation:Enum=import;export
+	Direction WorkspaceItemDirection `json:"direction,omitempty"`
+	// ExportTo is the config map the item is written to when Direction is export
+	ExportTo *WorkspaceItemExportTarget `json:"export_to,omitempty"`
+}
+
+// WorkspaceItemDirection is whether the item is imported into or exported from the workspace
+type WorkspaceItemDirection string
+
+const (
+	// WorkspaceItemDirectionImport imports the content in the spec into the workspace
+	WorkspaceItemDirectionImport WorkspaceItemDirection = "import"
+	// WorkspaceItemDirectionExport exports the item in the workspace into a config map
+	WorkspaceItemDirectionExport WorkspaceItemDirection = "export"
+)
+
+// Annotations set on the config maps written in export mode. Several items can share a config map,
+// so each annotation is qualified with the data key, e.g. databricks.microsoft.com/analysis.content-hash
+const (
+	WorkspaceItemAnnotationPrefix       = "databricks.microsoft.com/"
+	WorkspaceItemContentHashAnnotation  = "content-hash"
+	WorkspaceItemLastModifiedAnnotation = "last-modified"
+	WorkspaceItemSourcePathAnnotation   = "source-path"
+)
+
+// maxExportKeyLength keeps the annotations qualified with the key within the 63 character limit on names
+const maxExportKeyLength = 63 - len(".") - len(WorkspaceItemLastModifiedAnnotation)
+
+// defaultExportIntervalSeconds is used when ExportTo does not set an interval
+const defaultExportIntervalSeconds = 300
+
+// WorkspaceItemExportTarget is the config map key a workspace item is exported to
+type WorkspaceItemExportTarget struct {
+	ConfigMapName string `json:"config_map_name,omitempty"`
+	// Key defaults to the name of the item
+	Key string `json:"key,omitempty"`
+	// IntervalSeconds is the time between exports, 300 if not set
+	IntervalSeconds int32 `json:"interval_seconds,omitempty"`
 }
 
 // WorkspaceItemStatus defines the observed state of WorkspaceItem
 type WorkspaceItemStatus struct {
 	ObjectInfo *dbmodels.ObjectInfo `json:"object_info,omitempty"`
 	ObjectHash string               `json:"object_hash,omitempty"`
+	// RemoteHash is the hash of the item as exported right after it was imported, which is
+	// what later exports are compared against when verifying content
+	RemoteHash string `json:"remote_hash,omitempty"`
+	// SourceVersion identifies the version of the ContentFrom source that was last imported
+	SourceVersion string `json:"source_version,omitempty"`
+	// LastExportedTime is when the item was last exported in export mode
+	LastExportedTime *metav1.Time `json:"last_exported_time,omitempty"`
+	// LastModifiedTime is when an export last found content that differed from the previous export
+	LastModifiedTime *metav1.Time `json:"last_modified_time,omitempty"`
+	// LastVerifiedTime is when the item in the workspace was last checked for drift
+	LastVerifiedTime *metav1.Time `json:"last_verified_time,omitempty"`
+	// LastDriftTime is when the item in the workspace was last found to differ from the last import
+	LastDriftTime *metav1.Time `json:"last_drift_time,omitempty"`
+	Conditions    []Condition  `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -55,6 +115,8 @@ type WorkspaceItemStatus struct {
 // +kubebuilder:printcolumn:name="Language",type="string",JSONPath=".status.object_info.language",priority=0
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".status.object_info.object_type",priority=1
 // +kubebuilder:printcolumn:name="Path",type="string",JSONPath=".status.object_info.path",priority=1
+// +kubebuilder:printcolumn:name="Direction",type="string",JSONPath=".spec.direction",priority=1
+// +kubebuilder:printcolumn:name="InSync",type="string",JSONPath=`.status.conditions[?(@.type=="InSync")].status`,priority=1
 type WorkspaceItem struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -85,6 +147,73 @@ func (wi *WorkspaceItem) IsUpToDate() bool {
 	return h == wi.Status.ObjectHash
 }
 
+// IsExport returns true if the item is exported from the workspace rather than imported into it
+func (wi *WorkspaceItem) IsExport() bool {
+	return wi.Spec != nil && wi.Spec.Direction == WorkspaceItemDirectionExport
+}
+
+// GetExportKey returns the config map key the item is exported to
+func (wi *WorkspaceItem) GetExportKey() string {
+	if wi.Spec.ExportTo != nil && wi.Spec.ExportTo.Key != "" {
+		return wi.Spec.ExportTo.Key
+	}
+	return path.Base(wi.Spec.Path)
+}
+
+// GetExportAnnotation returns the annotation on the config map for the export key of the item
+func (wi *WorkspaceItem) GetExportAnnotation(annotation string) string {
+	return WorkspaceItemAnnotationPrefix + wi.GetExportKey() + "." + annotation
+}
+
+// ValidateExportKey returns an error if the export key is too long to qualify the annotations with
+func (wi *WorkspaceItem) ValidateExportKey() error {
+	if key := wi.GetExportKey(); len(key) > maxExportKeyLength {
+		return fmt.Errorf("export key %s is longer than %d characters, set export_to.key to a shorter one", key, maxExportKeyLength)
+	}
+	return nil
+}
+
+// GetExportInterval returns the time between exports
+func (wi *WorkspaceItem) GetExportInterval() time.Duration {
+	if wi.Spec.ExportTo != nil && wi.Spec.ExportTo.IntervalSeconds > 0 {
+		return time.Duration(wi.Spec.ExportTo.IntervalSeconds) * time.Second
+	}
+	return defaultExportIntervalSeconds * time.Second
+}
+
+// NextExport returns how long to wait from now until the next export is due, or 0 if it is due already
+func (wi *WorkspaceItem) NextExport(now time.Time) time.Duration {
+	if wi.Status == nil || wi.Status.LastExportedTime == nil {
+		return 0
+	}
+	next := wi.Status.LastExportedTime.Add(wi.GetExportInterval())
+	if !next.After(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// HasContentFrom returns true if the content is read from an external source rather than the spec
+func (wi *WorkspaceItem) HasContentFrom() bool {
+	return wi.Spec != nil && wi.Spec.ContentFrom != nil
+}
+
+// IsSourceUpToDate tells you whether the ContentFrom source version is the one last imported
+func (wi *WorkspaceItem) IsSourceUpToDate(sourceVersion string) bool {
+	if wi.Status == nil {
+		return false
+	}
+	return sourceVersion != "" && sourceVersion == wi.Status.SourceVersion
+}
+
+// GetDriftDetection returns the drift detection settings, or nil if drift is not detected
+func (wi *WorkspaceItem) GetDriftDetection() *DriftDetection {
+	if wi.Spec == nil {
+		return nil
+	}
+	return wi.Spec.DriftDetection
+}
+
 // GetHash returns the sha1 hash of the decoded data attribute
 func (wi *WorkspaceItem) GetHash() string {
 	data, err := base64.StdEncoding.DecodeString(wi.Spec.Content)
@@ -152,6 +152,48 @@ var _ = Describe("WorkspaceItem", func() {
 			Expect(wiItemError.GetHash()).To(Equal(""))
 		})
 
+		It("should correctly handle export settings", func() {
+			wiItem := &WorkspaceItem{
+				Spec: &WorkspaceItemSpec{
+					Path: "/Users/someone/analysis",
+				},
+			}
+			Expect(wiItem.IsExport()).To(BeFalse())
+
+			wiItem.Spec.Direction = WorkspaceItemDirectionExport
+			Expect(wiItem.IsExport()).To(BeTrue())
+			Expect(wiItem.GetExportKey()).To(Equal("analysis"))
+			Expect(wiItem.GetExportInterval()).To(Equal(5 * time.Minute))
+
+			wiItem.Spec.ExportTo = &WorkspaceItemExportTarget{Key: "analysis.py", IntervalSeconds: 60}
+			Expect(wiItem.GetExportKey()).To(Equal("analysis.py"))
+			Expect(wiItem.GetExportInterval()).To(Equal(time.Minute))
+
+			now := time.Now()
+			Expect(wiItem.NextExport(now)).To(Equal(time.Duration(0)))
+			wiItem.Status = &WorkspaceItemStatus{LastExportedTime: &metav1.Time{Time: now.Add(-20 * time.Second)}}
+			Expect(wiItem.NextExport(now)).To(Equal(40 * time.Second))
+		})
+
+		It("should correctly handle content source version", func() {
+			wiItem := &WorkspaceItem{
+				Spec: &WorkspaceItemSpec{},
+			}
+			Expect(wiItem.HasContentFrom()).To(BeFalse())
+
+			wiItem.Spec.ContentFrom = &ContentFrom{
+				SecretKeyRef: &ContentKeyRef{Name: "notebooks", Key: "main.py"},
+			}
+			Expect(wiItem.HasContentFrom()).To(BeTrue())
+			Expect(wiItem.IsSourceUpToDate("secret/notebooks/main.py@1")).To(BeFalse())
+
+			wiItem.Status = &WorkspaceItemStatus{
+				SourceVersion: "secret/notebooks/main.py@1",
+			}
+			Expect(wiItem.IsSourceUpToDate("secret/notebooks/main.py@1")).To(BeTrue())
+			Expect(wiItem.IsSourceUpToDate("secret/notebooks/main.py@2")).To(BeFalse())
+		})
+
 	})
 
 })
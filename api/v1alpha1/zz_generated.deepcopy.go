@@ -29,7 +29,6 @@ SOFTWARE.
 package v1alpha1
 
 import (
-	"github.com/xinsnake/databricks-sdk-golang/azure"
 	"github.com/xinsnake/databricks-sdk-golang/azure/models"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -62,34 +61,110 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DbfsBlock) DeepCopyInto(out *DbfsBlock) {
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentFrom) DeepCopyInto(out *ContentFrom) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(ContentKeyRef)
+		**out = **in
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(ContentKeyRef)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(ContentHTTPSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentFrom.
+func (in *ContentFrom) DeepCopy() *ContentFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentHTTPSource) DeepCopyInto(out *ContentHTTPSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentHTTPSource.
+func (in *ContentHTTPSource) DeepCopy() *ContentHTTPSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentHTTPSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentKeyRef) DeepCopyInto(out *ContentKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentKeyRef.
+func (in *ContentKeyRef) DeepCopy() *ContentKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ContentKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabricksRepo) DeepCopyInto(out *DatabricksRepo) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	if in.Spec != nil {
 		in, out := &in.Spec, &out.Spec
-		*out = new(DbfsBlockSpec)
+		*out = new(DatabricksRepoSpec)
 		**out = **in
 	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(DbfsBlockStatus)
+		*out = new(DatabricksRepoStatus)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsBlock.
-func (in *DbfsBlock) DeepCopy() *DbfsBlock {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabricksRepo.
+func (in *DatabricksRepo) DeepCopy() *DatabricksRepo {
 	if in == nil {
 		return nil
 	}
-	out := new(DbfsBlock)
+	out := new(DatabricksRepo)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DbfsBlock) DeepCopyObject() runtime.Object {
+func (in *DatabricksRepo) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -97,31 +172,31 @@ func (in *DbfsBlock) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DbfsBlockList) DeepCopyInto(out *DbfsBlockList) {
+func (in *DatabricksRepoList) DeepCopyInto(out *DatabricksRepoList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DbfsBlock, len(*in))
+		*out = make([]DatabricksRepo, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsBlockList.
-func (in *DbfsBlockList) DeepCopy() *DbfsBlockList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabricksRepoList.
+func (in *DatabricksRepoList) DeepCopy() *DatabricksRepoList {
 	if in == nil {
 		return nil
 	}
-	out := new(DbfsBlockList)
+	out := new(DatabricksRepoList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DbfsBlockList) DeepCopyObject() runtime.Object {
+func (in *DatabricksRepoList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -129,69 +204,68 @@ func (in *DbfsBlockList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DbfsBlockSpec) DeepCopyInto(out *DbfsBlockSpec) {
+func (in *DatabricksRepoSpec) DeepCopyInto(out *DatabricksRepoSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsBlockSpec.
-func (in *DbfsBlockSpec) DeepCopy() *DbfsBlockSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabricksRepoSpec.
+func (in *DatabricksRepoSpec) DeepCopy() *DatabricksRepoSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DbfsBlockSpec)
+	out := new(DatabricksRepoSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DbfsBlockStatus) DeepCopyInto(out *DbfsBlockStatus) {
+func (in *DatabricksRepoStatus) DeepCopyInto(out *DatabricksRepoStatus) {
 	*out = *in
-	if in.FileInfo != nil {
-		in, out := &in.FileInfo, &out.FileInfo
-		*out = new(models.FileInfo)
-		**out = **in
+	if in.LastUpdatedTime != nil {
+		in, out := &in.LastUpdatedTime, &out.LastUpdatedTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsBlockStatus.
-func (in *DbfsBlockStatus) DeepCopy() *DbfsBlockStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabricksRepoStatus.
+func (in *DatabricksRepoStatus) DeepCopy() *DatabricksRepoStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DbfsBlockStatus)
+	out := new(DatabricksRepoStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Dcluster) DeepCopyInto(out *Dcluster) {
+func (in *DbfsBlock) DeepCopyInto(out *DbfsBlock) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	if in.Spec != nil {
 		in, out := &in.Spec, &out.Spec
-		*out = new(models.NewCluster)
+		*out = new(DbfsBlockSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(DclusterStatus)
+		*out = new(DbfsBlockStatus)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dcluster.
-func (in *Dcluster) DeepCopy() *Dcluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsBlock.
+func (in *DbfsBlock) DeepCopy() *DbfsBlock {
 	if in == nil {
 		return nil
 	}
-	out := new(Dcluster)
+	out := new(DbfsBlock)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Dcluster) DeepCopyObject() runtime.Object {
+func (in *DbfsBlock) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -199,107 +273,31 @@ func (in *Dcluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DclusterInfo) DeepCopyInto(out *DclusterInfo) {
-	*out = *in
-	if in.AutoScale != nil {
-		in, out := &in.AutoScale, &out.AutoScale
-		*out = new(models.AutoScale)
-		**out = **in
-	}
-	if in.Driver != nil {
-		in, out := &in.Driver, &out.Driver
-		*out = new(models.SparkNode)
-		**out = **in
-	}
-	if in.Executors != nil {
-		in, out := &in.Executors, &out.Executors
-		*out = make([]models.SparkNode, len(*in))
-		copy(*out, *in)
-	}
-	if in.SparkConf != nil {
-		in, out := &in.SparkConf, &out.SparkConf
-		*out = new(models.SparkConfPair)
-		**out = **in
-	}
-	if in.ClusterLogConf != nil {
-		in, out := &in.ClusterLogConf, &out.ClusterLogConf
-		*out = new(models.ClusterLogConf)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.InitScripts != nil {
-		in, out := &in.InitScripts, &out.InitScripts
-		*out = make([]models.InitScriptInfo, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.SparkEnvVars != nil {
-		in, out := &in.SparkEnvVars, &out.SparkEnvVars
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.State != nil {
-		in, out := &in.State, &out.State
-		*out = new(models.ClusterState)
-		**out = **in
-	}
-	if in.DefaultTags != nil {
-		in, out := &in.DefaultTags, &out.DefaultTags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ClusterLogStatus != nil {
-		in, out := &in.ClusterLogStatus, &out.ClusterLogStatus
-		*out = new(models.LogSyncStatus)
-		**out = **in
-	}
-	if in.TerminationReason != nil {
-		in, out := &in.TerminationReason, &out.TerminationReason
-		*out = new(models.TerminationReason)
-		(*in).DeepCopyInto(*out)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DclusterInfo.
-func (in *DclusterInfo) DeepCopy() *DclusterInfo {
-	if in == nil {
-		return nil
-	}
-	out := new(DclusterInfo)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DclusterList) DeepCopyInto(out *DclusterList) {
+func (in *DbfsBlockList) DeepCopyInto(out *DbfsBlockList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Dcluster, len(*in))
+		*out = make([]DbfsBlock, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DclusterList.
-func (in *DclusterList) DeepCopy() *DclusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsBlockList.
+func (in *DbfsBlockList) DeepCopy() *DbfsBlockList {
 	if in == nil {
 		return nil
 	}
-	out := new(DclusterList)
+	out := new(DbfsBlockList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DclusterList) DeepCopyObject() runtime.Object {
+func (in *DbfsBlockList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -307,54 +305,94 @@ func (in *DclusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DclusterStatus) DeepCopyInto(out *DclusterStatus) {
+func (in *DbfsBlockSpec) DeepCopyInto(out *DbfsBlockSpec) {
 	*out = *in
-	if in.ClusterInfo != nil {
-		in, out := &in.ClusterInfo, &out.ClusterInfo
-		*out = new(DclusterInfo)
+	if in.DataFrom != nil {
+		in, out := &in.DataFrom, &out.DataFrom
+		*out = new(ContentFrom)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetection)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DclusterStatus.
-func (in *DclusterStatus) DeepCopy() *DclusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsBlockSpec.
+func (in *DbfsBlockSpec) DeepCopy() *DbfsBlockSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DclusterStatus)
+	out := new(DbfsBlockSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Djob) DeepCopyInto(out *Djob) {
+func (in *DbfsBlockStatus) DeepCopyInto(out *DbfsBlockStatus) {
+	*out = *in
+	if in.FileInfo != nil {
+		in, out := &in.FileInfo, &out.FileInfo
+		*out = new(models.FileInfo)
+		**out = **in
+	}
+	if in.LastVerifiedTime != nil {
+		in, out := &in.LastVerifiedTime, &out.LastVerifiedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsBlockStatus.
+func (in *DbfsBlockStatus) DeepCopy() *DbfsBlockStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DbfsBlockStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DbfsDirectory) DeepCopyInto(out *DbfsDirectory) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	if in.Spec != nil {
 		in, out := &in.Spec, &out.Spec
-		*out = new(JobSettings)
+		*out = new(DbfsDirectorySpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(DjobStatus)
+		*out = new(DbfsDirectoryStatus)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Djob.
-func (in *Djob) DeepCopy() *Djob {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsDirectory.
+func (in *DbfsDirectory) DeepCopy() *DbfsDirectory {
 	if in == nil {
 		return nil
 	}
-	out := new(Djob)
+	out := new(DbfsDirectory)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Djob) DeepCopyObject() runtime.Object {
+func (in *DbfsDirectory) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -362,150 +400,1571 @@ func (in *Djob) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjobList) DeepCopyInto(out *DjobList) {
+func (in *DbfsDirectoryConfigMapSource) DeepCopyInto(out *DbfsDirectoryConfigMapSource) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Djob, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjobList.
-func (in *DjobList) DeepCopy() *DjobList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsDirectoryConfigMapSource.
+func (in *DbfsDirectoryConfigMapSource) DeepCopy() *DbfsDirectoryConfigMapSource {
 	if in == nil {
 		return nil
 	}
-	out := new(DjobList)
+	out := new(DbfsDirectoryConfigMapSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DjobList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DjobStatus) DeepCopyInto(out *DjobStatus) {
+func (in *DbfsDirectoryFile) DeepCopyInto(out *DbfsDirectoryFile) {
 	*out = *in
-	if in.JobStatus != nil {
-		in, out := &in.JobStatus, &out.JobStatus
-		*out = new(models.Job)
+	if in.DataFrom != nil {
+		in, out := &in.DataFrom, &out.DataFrom
+		*out = new(ContentFrom)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Last10Runs != nil {
-		in, out := &in.Last10Runs, &out.Last10Runs
-		*out = make([]models.Run, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjobStatus.
-func (in *DjobStatus) DeepCopy() *DjobStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsDirectoryFile.
+func (in *DbfsDirectoryFile) DeepCopy() *DbfsDirectoryFile {
 	if in == nil {
 		return nil
 	}
-	out := new(DjobStatus)
+	out := new(DbfsDirectoryFile)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JobSettings) DeepCopyInto(out *JobSettings) {
+func (in *DbfsDirectoryFileStatus) DeepCopyInto(out *DbfsDirectoryFileStatus) {
 	*out = *in
-	if in.NewCluster != nil {
-		in, out := &in.NewCluster, &out.NewCluster
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsDirectoryFileStatus.
+func (in *DbfsDirectoryFileStatus) DeepCopy() *DbfsDirectoryFileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DbfsDirectoryFileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DbfsDirectoryList) DeepCopyInto(out *DbfsDirectoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DbfsDirectory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsDirectoryList.
+func (in *DbfsDirectoryList) DeepCopy() *DbfsDirectoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(DbfsDirectoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DbfsDirectoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DbfsDirectorySpec) DeepCopyInto(out *DbfsDirectorySpec) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]DbfsDirectoryConfigMapSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]DbfsDirectoryFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsDirectorySpec.
+func (in *DbfsDirectorySpec) DeepCopy() *DbfsDirectorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DbfsDirectorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DbfsDirectoryStatus) DeepCopyInto(out *DbfsDirectoryStatus) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]DbfsDirectoryFileStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DbfsDirectoryStatus.
+func (in *DbfsDirectoryStatus) DeepCopy() *DbfsDirectoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DbfsDirectoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Dcluster) DeepCopyInto(out *Dcluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
 		*out = new(models.NewCluster)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.NotebookTask != nil {
-		in, out := &in.NotebookTask, &out.NotebookTask
-		*out = new(models.NotebookTask)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(DclusterStatus)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.SparkJarTask != nil {
-		in, out := &in.SparkJarTask, &out.SparkJarTask
-		*out = new(models.SparkJarTask)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Dcluster.
+func (in *Dcluster) DeepCopy() *Dcluster {
+	if in == nil {
+		return nil
 	}
-	if in.SparkPythonTask != nil {
-		in, out := &in.SparkPythonTask, &out.SparkPythonTask
-		*out = new(models.SparkPythonTask)
+	out := new(Dcluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Dcluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DclusterInfo) DeepCopyInto(out *DclusterInfo) {
+	*out = *in
+	if in.AutoScale != nil {
+		in, out := &in.AutoScale, &out.AutoScale
+		*out = new(models.AutoScale)
+		**out = **in
+	}
+	if in.Driver != nil {
+		in, out := &in.Driver, &out.Driver
+		*out = new(models.SparkNode)
+		**out = **in
+	}
+	if in.Executors != nil {
+		in, out := &in.Executors, &out.Executors
+		*out = make([]models.SparkNode, len(*in))
+		copy(*out, *in)
+	}
+	if in.SparkConf != nil {
+		in, out := &in.SparkConf, &out.SparkConf
+		*out = new(models.SparkConfPair)
+		**out = **in
+	}
+	if in.ClusterLogConf != nil {
+		in, out := &in.ClusterLogConf, &out.ClusterLogConf
+		*out = new(models.ClusterLogConf)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.SparkSubmitTask != nil {
-		in, out := &in.SparkSubmitTask, &out.SparkSubmitTask
-		*out = new(models.SparkSubmitTask)
+	if in.InitScripts != nil {
+		in, out := &in.InitScripts, &out.InitScripts
+		*out = make([]models.InitScriptInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SparkEnvVars != nil {
+		in, out := &in.SparkEnvVars, &out.SparkEnvVars
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(models.ClusterState)
+		**out = **in
+	}
+	if in.DefaultTags != nil {
+		in, out := &in.DefaultTags, &out.DefaultTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClusterLogStatus != nil {
+		in, out := &in.ClusterLogStatus, &out.ClusterLogStatus
+		*out = new(models.LogSyncStatus)
+		**out = **in
+	}
+	if in.TerminationReason != nil {
+		in, out := &in.TerminationReason, &out.TerminationReason
+		*out = new(models.TerminationReason)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Libraries != nil {
-		in, out := &in.Libraries, &out.Libraries
-		*out = make([]models.Library, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DclusterInfo.
+func (in *DclusterInfo) DeepCopy() *DclusterInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(DclusterInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DclusterList) DeepCopyInto(out *DclusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Dcluster, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.EmailNotifications != nil {
-		in, out := &in.EmailNotifications, &out.EmailNotifications
-		*out = new(models.JobEmailNotifications)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DclusterList.
+func (in *DclusterList) DeepCopy() *DclusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(DclusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DclusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DclusterStatus) DeepCopyInto(out *DclusterStatus) {
+	*out = *in
+	if in.ClusterInfo != nil {
+		in, out := &in.ClusterInfo, &out.ClusterInfo
+		*out = new(DclusterInfo)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DclusterStatus.
+func (in *DclusterStatus) DeepCopy() *DclusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DclusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Djob) DeepCopyInto(out *Djob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(JobSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(DjobStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Djob.
+func (in *Djob) DeepCopy() *Djob {
+	if in == nil {
+		return nil
+	}
+	out := new(Djob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Djob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjobList) DeepCopyInto(out *DjobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Djob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjobList.
+func (in *DjobList) DeepCopy() *DjobList {
+	if in == nil {
+		return nil
+	}
+	out := new(DjobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DjobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjobStatus) DeepCopyInto(out *DjobStatus) {
+	*out = *in
+	if in.JobStatus != nil {
+		in, out := &in.JobStatus, &out.JobStatus
+		*out = new(models.Job)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Last10Runs != nil {
+		in, out := &in.Last10Runs, &out.Last10Runs
+		*out = make([]models.Run, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]JobTaskSettings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.JobClusters != nil {
+		in, out := &in.JobClusters, &out.JobClusters
+		*out = make([]JobCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TaskRuns != nil {
+		in, out := &in.TaskRuns, &out.TaskRuns
+		*out = make([]DjobTaskRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastTriggeredRun != nil {
+		in, out := &in.LastTriggeredRun, &out.LastTriggeredRun
+		*out = new(DjobTriggeredRun)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjobStatus.
+func (in *DjobStatus) DeepCopy() *DjobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DjobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjobTaskRun) DeepCopyInto(out *DjobTaskRun) {
+	*out = *in
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(models.RunState)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjobTaskRun.
+func (in *DjobTaskRun) DeepCopy() *DjobTaskRun {
+	if in == nil {
+		return nil
+	}
+	out := new(DjobTaskRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DjobTriggeredRun) DeepCopyInto(out *DjobTriggeredRun) {
+	*out = *in
+	in.TriggerTime.DeepCopyInto(&out.TriggerTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DjobTriggeredRun.
+func (in *DjobTriggeredRun) DeepCopy() *DjobTriggeredRun {
+	if in == nil {
+		return nil
+	}
+	out := new(DjobTriggeredRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetection) DeepCopyInto(out *DriftDetection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetection.
+func (in *DriftDetection) DeepCopy() *DriftDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobCluster) DeepCopyInto(out *JobCluster) {
+	*out = *in
+	if in.NewCluster != nil {
+		in, out := &in.NewCluster, &out.NewCluster
+		*out = new(models.NewCluster)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobCluster.
+func (in *JobCluster) DeepCopy() *JobCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(JobCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobRunNow) DeepCopyInto(out *JobRunNow) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = new(models.RunParameters)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobRunNow.
+func (in *JobRunNow) DeepCopy() *JobRunNow {
+	if in == nil {
+		return nil
+	}
+	out := new(JobRunNow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobSettings) DeepCopyInto(out *JobSettings) {
+	*out = *in
+	if in.NewCluster != nil {
+		in, out := &in.NewCluster, &out.NewCluster
+		*out = new(models.NewCluster)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotebookTask != nil {
+		in, out := &in.NotebookTask, &out.NotebookTask
+		*out = new(models.NotebookTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SparkJarTask != nil {
+		in, out := &in.SparkJarTask, &out.SparkJarTask
+		*out = new(models.SparkJarTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SparkPythonTask != nil {
+		in, out := &in.SparkPythonTask, &out.SparkPythonTask
+		*out = new(models.SparkPythonTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SparkSubmitTask != nil {
+		in, out := &in.SparkSubmitTask, &out.SparkSubmitTask
+		*out = new(models.SparkSubmitTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Libraries != nil {
+		in, out := &in.Libraries, &out.Libraries
+		*out = make([]models.Library, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EmailNotifications != nil {
+		in, out := &in.EmailNotifications, &out.EmailNotifications
+		*out = new(models.JobEmailNotifications)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(models.CronSchedule)
+		**out = **in
+	}
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]JobTaskSettings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.JobClusters != nil {
+		in, out := &in.JobClusters, &out.JobClusters
+		*out = make([]JobCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RunNow != nil {
+		in, out := &in.RunNow, &out.RunNow
+		*out = new(JobRunNow)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobSettings.
+func (in *JobSettings) DeepCopy() *JobSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(JobSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobTaskSettings) DeepCopyInto(out *JobTaskSettings) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]TaskDependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.NewCluster != nil {
+		in, out := &in.NewCluster, &out.NewCluster
+		*out = new(models.NewCluster)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotebookTask != nil {
+		in, out := &in.NotebookTask, &out.NotebookTask
+		*out = new(models.NotebookTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SparkJarTask != nil {
+		in, out := &in.SparkJarTask, &out.SparkJarTask
+		*out = new(models.SparkJarTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SparkPythonTask != nil {
+		in, out := &in.SparkPythonTask, &out.SparkPythonTask
+		*out = new(models.SparkPythonTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SparkSubmitTask != nil {
+		in, out := &in.SparkSubmitTask, &out.SparkSubmitTask
+		*out = new(models.SparkSubmitTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Libraries != nil {
+		in, out := &in.Libraries, &out.Libraries
+		*out = make([]models.Library, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTaskSettings.
+func (in *JobTaskSettings) DeepCopy() *JobTaskSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(JobTaskSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Run) DeepCopyInto(out *Run) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(RunSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(RunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Run.
+func (in *Run) DeepCopy() *Run {
+	if in == nil {
+		return nil
+	}
+	out := new(Run)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Run) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunAttempt) DeepCopyInto(out *RunAttempt) {
+	*out = *in
+	if in.LifeCycleState != nil {
+		in, out := &in.LifeCycleState, &out.LifeCycleState
+		*out = new(models.RunLifeCycleState)
+		**out = **in
+	}
+	if in.ResultState != nil {
+		in, out := &in.ResultState, &out.ResultState
+		*out = new(models.RunResultState)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunAttempt.
+func (in *RunAttempt) DeepCopy() *RunAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(RunAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunList) DeepCopyInto(out *RunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Run, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunList.
+func (in *RunList) DeepCopy() *RunList {
+	if in == nil {
+		return nil
+	}
+	out := new(RunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunOutputStatus) DeepCopyInto(out *RunOutputStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunOutputStatus.
+func (in *RunOutputStatus) DeepCopy() *RunOutputStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RunOutputStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunOutputTarget) DeepCopyInto(out *RunOutputTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunOutputTarget.
+func (in *RunOutputTarget) DeepCopy() *RunOutputTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RunOutputTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunParameter) DeepCopyInto(out *RunParameter) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(ContentKeyRef)
+		**out = **in
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(ContentKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunParameter.
+func (in *RunParameter) DeepCopy() *RunParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(RunParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunPriorityClass) DeepCopyInto(out *RunPriorityClass) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunPriorityClass.
+func (in *RunPriorityClass) DeepCopy() *RunPriorityClass {
+	if in == nil {
+		return nil
+	}
+	out := new(RunPriorityClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunQueue) DeepCopyInto(out *RunQueue) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(RunQueueSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(RunQueueStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunQueue.
+func (in *RunQueue) DeepCopy() *RunQueue {
+	if in == nil {
+		return nil
+	}
+	out := new(RunQueue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunQueue) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunQueueList) DeepCopyInto(out *RunQueueList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RunQueue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunQueueList.
+func (in *RunQueueList) DeepCopy() *RunQueueList {
+	if in == nil {
+		return nil
+	}
+	out := new(RunQueueList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunQueueList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunQueueSpec) DeepCopyInto(out *RunQueueSpec) {
+	*out = *in
+	if in.PriorityClasses != nil {
+		in, out := &in.PriorityClasses, &out.PriorityClasses
+		*out = make([]RunPriorityClass, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunQueueSpec.
+func (in *RunQueueSpec) DeepCopy() *RunQueueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RunQueueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunQueueStatus) DeepCopyInto(out *RunQueueStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunQueueStatus.
+func (in *RunQueueStatus) DeepCopy() *RunQueueStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RunQueueStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunResultField) DeepCopyInto(out *RunResultField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunResultField.
+func (in *RunResultField) DeepCopy() *RunResultField {
+	if in == nil {
+		return nil
+	}
+	out := new(RunResultField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunSet) DeepCopyInto(out *RunSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(RunSetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(RunSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunSet.
+func (in *RunSet) DeepCopy() *RunSet {
+	if in == nil {
+		return nil
+	}
+	out := new(RunSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunSetList) DeepCopyInto(out *RunSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RunSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunSetList.
+func (in *RunSetList) DeepCopy() *RunSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(RunSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunSetSpec) DeepCopyInto(out *RunSetSpec) {
+	*out = *in
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Completions != nil {
+		in, out := &in.Completions, &out.Completions
+		*out = new(int32)
+		**out = **in
+	}
+	in.RunTemplate.DeepCopyInto(&out.RunTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunSetSpec.
+func (in *RunSetSpec) DeepCopy() *RunSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RunSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunSetStatus) DeepCopyInto(out *RunSetStatus) {
+	*out = *in
+	if in.SucceededIndexes != nil {
+		in, out := &in.SucceededIndexes, &out.SucceededIndexes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedIndexes != nil {
+		in, out := &in.FailedIndexes, &out.FailedIndexes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastCreationTime != nil {
+		in, out := &in.LastCreationTime, &out.LastCreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunSetStatus.
+func (in *RunSetStatus) DeepCopy() *RunSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RunSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunSpec) DeepCopyInto(out *RunSpec) {
+	*out = *in
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(RunTemplateRef)
+		**out = **in
+	}
+	if in.TemplateParams != nil {
+		in, out := &in.TemplateParams, &out.TemplateParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RunParameters != nil {
+		in, out := &in.RunParameters, &out.RunParameters
+		*out = new(models.RunParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ClusterSpec.DeepCopyInto(&out.ClusterSpec)
+	if in.JobTask != nil {
+		in, out := &in.JobTask, &out.JobTask
+		*out = new(models.JobTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NotebookParamsFrom != nil {
+		in, out := &in.NotebookParamsFrom, &out.NotebookParamsFrom
+		*out = make([]RunParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.JarParamsFrom != nil {
+		in, out := &in.JarParamsFrom, &out.JarParamsFrom
+		*out = make([]RunParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PythonParamsFrom != nil {
+		in, out := &in.PythonParamsFrom, &out.PythonParamsFrom
+		*out = make([]RunParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SparkSubmitParamsFrom != nil {
+		in, out := &in.SparkSubmitParamsFrom, &out.SparkSubmitParamsFrom
+		*out = make([]RunParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OutputTo != nil {
+		in, out := &in.OutputTo, &out.OutputTo
+		*out = new(RunOutputTarget)
+		**out = **in
+	}
+	if in.ResultFields != nil {
+		in, out := &in.ResultFields, &out.ResultFields
+		*out = make([]RunResultField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunSpec.
+func (in *RunSpec) DeepCopy() *RunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunStatus) DeepCopyInto(out *RunStatus) {
+	*out = *in
+	in.JobsRunsGetOutputResponse.DeepCopyInto(&out.JobsRunsGetOutputResponse)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]RunAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EffectiveSpec != nil {
+		in, out := &in.EffectiveSpec, &out.EffectiveSpec
+		*out = new(RunSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExportedOutput != nil {
+		in, out := &in.ExportedOutput, &out.ExportedOutput
+		*out = new(RunOutputStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunStatus.
+func (in *RunStatus) DeepCopy() *RunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplate) DeepCopyInto(out *RunTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(RunTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplate.
+func (in *RunTemplate) DeepCopy() *RunTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplateList) DeepCopyInto(out *RunTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RunTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateList.
+func (in *RunTemplateList) DeepCopy() *RunTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplateParameter) DeepCopyInto(out *RunTemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateParameter.
+func (in *RunTemplateParameter) DeepCopy() *RunTemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplateRef) DeepCopyInto(out *RunTemplateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateRef.
+func (in *RunTemplateRef) DeepCopy() *RunTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunTemplateSpec) DeepCopyInto(out *RunTemplateSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]RunTemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunTemplateSpec.
+func (in *RunTemplateSpec) DeepCopy() *RunTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RunTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunWorkflow) DeepCopyInto(out *RunWorkflow) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(RunWorkflowSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(RunWorkflowStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunWorkflow.
+func (in *RunWorkflow) DeepCopy() *RunWorkflow {
+	if in == nil {
+		return nil
+	}
+	out := new(RunWorkflow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunWorkflow) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunWorkflowList) DeepCopyInto(out *RunWorkflowList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RunWorkflow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunWorkflowList.
+func (in *RunWorkflowList) DeepCopy() *RunWorkflowList {
+	if in == nil {
+		return nil
+	}
+	out := new(RunWorkflowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RunWorkflowList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunWorkflowParameter) DeepCopyInto(out *RunWorkflowParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunWorkflowParameter.
+func (in *RunWorkflowParameter) DeepCopy() *RunWorkflowParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(RunWorkflowParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunWorkflowSpec) DeepCopyInto(out *RunWorkflowSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RunWorkflowStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunWorkflowSpec.
+func (in *RunWorkflowSpec) DeepCopy() *RunWorkflowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RunWorkflowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunWorkflowStatus) DeepCopyInto(out *RunWorkflowStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RunWorkflowStepStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunWorkflowStatus.
+func (in *RunWorkflowStatus) DeepCopy() *RunWorkflowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RunWorkflowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunWorkflowStep) DeepCopyInto(out *RunWorkflowStep) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Schedule != nil {
-		in, out := &in.Schedule, &out.Schedule
-		*out = new(models.CronSchedule)
-		**out = **in
+	if in.ParametersFrom != nil {
+		in, out := &in.ParametersFrom, &out.ParametersFrom
+		*out = make([]RunWorkflowParameter, len(*in))
+		copy(*out, *in)
 	}
+	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobSettings.
-func (in *JobSettings) DeepCopy() *JobSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunWorkflowStep.
+func (in *RunWorkflowStep) DeepCopy() *RunWorkflowStep {
 	if in == nil {
 		return nil
 	}
-	out := new(JobSettings)
+	out := new(RunWorkflowStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Run) DeepCopyInto(out *Run) {
+func (in *RunWorkflowStepStatus) DeepCopyInto(out *RunWorkflowStepStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunWorkflowStepStatus.
+func (in *RunWorkflowStepStatus) DeepCopy() *RunWorkflowStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RunWorkflowStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledRun) DeepCopyInto(out *ScheduledRun) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	if in.Spec != nil {
 		in, out := &in.Spec, &out.Spec
-		*out = new(RunSpec)
+		*out = new(ScheduledRunSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
-		*out = new(azure.JobsRunsGetOutputResponse)
+		*out = new(ScheduledRunStatus)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Run.
-func (in *Run) DeepCopy() *Run {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledRun.
+func (in *ScheduledRun) DeepCopy() *ScheduledRun {
 	if in == nil {
 		return nil
 	}
-	out := new(Run)
+	out := new(ScheduledRun)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Run) DeepCopyObject() runtime.Object {
+func (in *ScheduledRun) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -513,31 +1972,31 @@ func (in *Run) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RunList) DeepCopyInto(out *RunList) {
+func (in *ScheduledRunList) DeepCopyInto(out *ScheduledRunList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Run, len(*in))
+		*out = make([]ScheduledRun, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunList.
-func (in *RunList) DeepCopy() *RunList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledRunList.
+func (in *ScheduledRunList) DeepCopy() *ScheduledRunList {
 	if in == nil {
 		return nil
 	}
-	out := new(RunList)
+	out := new(ScheduledRunList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RunList) DeepCopyObject() runtime.Object {
+func (in *ScheduledRunList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -545,27 +2004,60 @@ func (in *RunList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RunSpec) DeepCopyInto(out *RunSpec) {
+func (in *ScheduledRunSpec) DeepCopyInto(out *ScheduledRunSpec) {
 	*out = *in
-	if in.RunParameters != nil {
-		in, out := &in.RunParameters, &out.RunParameters
-		*out = new(models.RunParameters)
-		(*in).DeepCopyInto(*out)
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
 	}
-	in.ClusterSpec.DeepCopyInto(&out.ClusterSpec)
-	if in.JobTask != nil {
-		in, out := &in.JobTask, &out.JobTask
-		*out = new(models.JobTask)
-		(*in).DeepCopyInto(*out)
+	if in.SuccessfulRunsHistoryLimit != nil {
+		in, out := &in.SuccessfulRunsHistoryLimit, &out.SuccessfulRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRunsHistoryLimit != nil {
+		in, out := &in.FailedRunsHistoryLimit, &out.FailedRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
 	}
+	in.RunTemplate.DeepCopyInto(&out.RunTemplate)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunSpec.
-func (in *RunSpec) DeepCopy() *RunSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledRunSpec.
+func (in *ScheduledRunSpec) DeepCopy() *ScheduledRunSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RunSpec)
+	out := new(ScheduledRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledRunStatus) DeepCopyInto(out *ScheduledRunStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledRunStatus.
+func (in *ScheduledRunStatus) DeepCopy() *ScheduledRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledRunStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -627,6 +2119,25 @@ func (in *SecretScopeKeyRef) DeepCopy() *SecretScopeKeyRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretScopeKeyStatus) DeepCopyInto(out *SecretScopeKeyStatus) {
+	*out = *in
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretScopeKeyStatus.
+func (in *SecretScopeKeyStatus) DeepCopy() *SecretScopeKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretScopeKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretScopeList) DeepCopyInto(out *SecretScopeList) {
 	*out = *in
@@ -665,7 +2176,7 @@ func (in *SecretScopeSecret) DeepCopyInto(out *SecretScopeSecret) {
 	if in.ValueFrom != nil {
 		in, out := &in.ValueFrom, &out.ValueFrom
 		*out = new(SecretScopeValueFrom)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -714,6 +2225,13 @@ func (in *SecretScopeStatus) DeepCopyInto(out *SecretScopeStatus) {
 		*out = new(models.SecretScope)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]SecretScopeKeyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretScopeStatus.
@@ -730,6 +2248,11 @@ func (in *SecretScopeStatus) DeepCopy() *SecretScopeStatus {
 func (in *SecretScopeValueFrom) DeepCopyInto(out *SecretScopeValueFrom) {
 	*out = *in
 	out.SecretKeyRef = in.SecretKeyRef
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(SecretScopeKeyRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretScopeValueFrom.
@@ -742,6 +2265,189 @@ func (in *SecretScopeValueFrom) DeepCopy() *SecretScopeValueFrom {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskDependency) DeepCopyInto(out *TaskDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskDependency.
+func (in *TaskDependency) DeepCopy() *TaskDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceDirectory) DeepCopyInto(out *WorkspaceDirectory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(WorkspaceDirectorySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(WorkspaceDirectoryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceDirectory.
+func (in *WorkspaceDirectory) DeepCopy() *WorkspaceDirectory {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceDirectory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceDirectory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceDirectoryConfigMapSource) DeepCopyInto(out *WorkspaceDirectoryConfigMapSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceDirectoryConfigMapSource.
+func (in *WorkspaceDirectoryConfigMapSource) DeepCopy() *WorkspaceDirectoryConfigMapSource {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceDirectoryConfigMapSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceDirectoryGitSnapshot) DeepCopyInto(out *WorkspaceDirectoryGitSnapshot) {
+	*out = *in
+	out.Archive = in.Archive
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceDirectoryGitSnapshot.
+func (in *WorkspaceDirectoryGitSnapshot) DeepCopy() *WorkspaceDirectoryGitSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceDirectoryGitSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceDirectoryItemStatus) DeepCopyInto(out *WorkspaceDirectoryItemStatus) {
+	*out = *in
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceDirectoryItemStatus.
+func (in *WorkspaceDirectoryItemStatus) DeepCopy() *WorkspaceDirectoryItemStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceDirectoryItemStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceDirectoryList) DeepCopyInto(out *WorkspaceDirectoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceDirectory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceDirectoryList.
+func (in *WorkspaceDirectoryList) DeepCopy() *WorkspaceDirectoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceDirectoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceDirectoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceDirectorySpec) DeepCopyInto(out *WorkspaceDirectorySpec) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]WorkspaceDirectoryConfigMapSource, len(*in))
+		copy(*out, *in)
+	}
+	if in.GitSnapshot != nil {
+		in, out := &in.GitSnapshot, &out.GitSnapshot
+		*out = new(WorkspaceDirectoryGitSnapshot)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceDirectorySpec.
+func (in *WorkspaceDirectorySpec) DeepCopy() *WorkspaceDirectorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceDirectorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceDirectoryStatus) DeepCopyInto(out *WorkspaceDirectoryStatus) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceDirectoryItemStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncedTime != nil {
+		in, out := &in.LastSyncedTime, &out.LastSyncedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceDirectoryStatus.
+func (in *WorkspaceDirectoryStatus) DeepCopy() *WorkspaceDirectoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceDirectoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceItem) DeepCopyInto(out *WorkspaceItem) {
 	*out = *in
@@ -750,7 +2456,7 @@ func (in *WorkspaceItem) DeepCopyInto(out *WorkspaceItem) {
 	if in.Spec != nil {
 		in, out := &in.Spec, &out.Spec
 		*out = new(WorkspaceItemSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
@@ -777,6 +2483,21 @@ func (in *WorkspaceItem) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceItemExportTarget) DeepCopyInto(out *WorkspaceItemExportTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceItemExportTarget.
+func (in *WorkspaceItemExportTarget) DeepCopy() *WorkspaceItemExportTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceItemExportTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceItemList) DeepCopyInto(out *WorkspaceItemList) {
 	*out = *in
@@ -812,6 +2533,21 @@ func (in *WorkspaceItemList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceItemSpec) DeepCopyInto(out *WorkspaceItemSpec) {
 	*out = *in
+	if in.ContentFrom != nil {
+		in, out := &in.ContentFrom, &out.ContentFrom
+		*out = new(ContentFrom)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetection)
+		**out = **in
+	}
+	if in.ExportTo != nil {
+		in, out := &in.ExportTo, &out.ExportTo
+		*out = new(WorkspaceItemExportTarget)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceItemSpec.
@@ -832,6 +2568,29 @@ func (in *WorkspaceItemStatus) DeepCopyInto(out *WorkspaceItemStatus) {
 		*out = new(models.ObjectInfo)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LastExportedTime != nil {
+		in, out := &in.LastExportedTime, &out.LastExportedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastModifiedTime != nil {
+		in, out := &in.LastModifiedTime, &out.LastModifiedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastVerifiedTime != nil {
+		in, out := &in.LastVerifiedTime, &out.LastVerifiedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDriftTime != nil {
+		in, out := &in.LastDriftTime, &out.LastDriftTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceItemStatus.
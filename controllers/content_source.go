@@ -0,0 +1,161 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+var contentHTTPClient = &http.Client{Timeout: 5 * time.Minute}
+
+// getContentVersion returns a string identifying the current version of the referenced content,
+// without reading the content itself. For HTTP sources without a checksum the version only
+// changes when the URL changes.
+func getContentVersion(c client.Client, namespace string, from *databricksv1alpha1.ContentFrom) (string, error) {
+	switch {
+	case from.ConfigMapKeyRef != nil:
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: from.ConfigMapKeyRef.Name}, configMap); err != nil {
+			return "", err
+		}
+		return objectContentVersion("configmap", from.ConfigMapKeyRef, configMap.ResourceVersion), nil
+	case from.SecretKeyRef != nil:
+		secret := &corev1.Secret{}
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: from.SecretKeyRef.Name}, secret); err != nil {
+			return "", err
+		}
+		return objectContentVersion("secret", from.SecretKeyRef, secret.ResourceVersion), nil
+	case from.HTTP != nil:
+		return httpContentVersion(from.HTTP), nil
+	}
+	return "", fmt.Errorf("no content source is set")
+}
+
+// getContent reads the referenced content and returns it together with its version
+func getContent(c client.Client, namespace string, from *databricksv1alpha1.ContentFrom) ([]byte, string, error) {
+	switch {
+	case from.ConfigMapKeyRef != nil:
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: from.ConfigMapKeyRef.Name}, configMap); err != nil {
+			return nil, "", err
+		}
+		version := objectContentVersion("configmap", from.ConfigMapKeyRef, configMap.ResourceVersion)
+		if data, ok := configMap.BinaryData[from.ConfigMapKeyRef.Key]; ok {
+			return data, version, nil
+		}
+		if data, ok := configMap.Data[from.ConfigMapKeyRef.Key]; ok {
+			return []byte(data), version, nil
+		}
+		return nil, "", fmt.Errorf("key %s not found in config map %s", from.ConfigMapKeyRef.Key, from.ConfigMapKeyRef.Name)
+	case from.SecretKeyRef != nil:
+		secret := &corev1.Secret{}
+		if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: from.SecretKeyRef.Name}, secret); err != nil {
+			return nil, "", err
+		}
+		version := objectContentVersion("secret", from.SecretKeyRef, secret.ResourceVersion)
+		if data, ok := secret.Data[from.SecretKeyRef.Key]; ok {
+			return data, version, nil
+		}
+		return nil, "", fmt.Errorf("key %s not found in secret %s", from.SecretKeyRef.Key, from.SecretKeyRef.Name)
+	case from.HTTP != nil:
+		data, err := downloadContent(from.HTTP)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, httpContentVersion(from.HTTP), nil
+	}
+	return nil, "", fmt.Errorf("no content source is set")
+}
+
+func objectContentVersion(kind string, ref *databricksv1alpha1.ContentKeyRef, resourceVersion string) string {
+	return fmt.Sprintf("%s/%s/%s@%s", kind, ref.Name, ref.Key, resourceVersion)
+}
+
+func httpContentVersion(source *databricksv1alpha1.ContentHTTPSource) string {
+	return fmt.Sprintf("%s#sha256=%s", source.URL, strings.ToLower(source.SHA256))
+}
+
+// downloadContent fetches the content from an HTTP(S) URL and verifies its checksum if one is set
+func downloadContent(source *databricksv1alpha1.ContentHTTPSource) ([]byte, error) {
+	u, err := url.Parse(source.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme %q, only http and https are supported", u.Scheme)
+	}
+
+	resp, err := contentHTTPClient.Get(source.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", source.URL, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.SHA256 != "" {
+		sum := sha256.Sum256(data)
+		if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, source.SHA256) {
+			return nil, fmt.Errorf("checksum mismatch for %s: expected sha256 %s, got %s", source.URL, source.SHA256, actual)
+		}
+	}
+
+	return data, nil
+}
+
+// referencesContentObject returns true if the content source reads from the config map or secret in the event
+func referencesContentObject(from *databricksv1alpha1.ContentFrom, object handler.MapObject) bool {
+	if from == nil {
+		return false
+	}
+	switch object.Object.(type) {
+	case *corev1.ConfigMap:
+		return from.ConfigMapKeyRef != nil && from.ConfigMapKeyRef.Name == object.Meta.GetName()
+	case *corev1.Secret:
+		return from.SecretKeyRef != nil && from.SecretKeyRef.Name == object.Meta.GetName()
+	}
+	return false
+}
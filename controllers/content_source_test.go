@@ -0,0 +1,92 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Content source", func() {
+
+	var server *httptest.Server
+	content := []byte("print('hello world')")
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/content" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("Downloading content", func() {
+		It("Should verify the checksum", func() {
+			data, err := downloadContent(&databricksv1alpha1.ContentHTTPSource{
+				URL:    server.URL + "/content",
+				SHA256: checksum,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(Equal(content))
+
+			_, err = downloadContent(&databricksv1alpha1.ContentHTTPSource{
+				URL:    server.URL + "/content",
+				SHA256: "0000",
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should fail on unsuccessful responses and unsupported schemes", func() {
+			_, err := downloadContent(&databricksv1alpha1.ContentHTTPSource{URL: server.URL + "/missing"})
+			Expect(err).To(HaveOccurred())
+
+			_, err = downloadContent(&databricksv1alpha1.ContentHTTPSource{URL: "file:///etc/passwd"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should version HTTP sources by URL and checksum", func() {
+			from := &databricksv1alpha1.ContentFrom{
+				HTTP: &databricksv1alpha1.ContentHTTPSource{URL: server.URL + "/content", SHA256: checksum},
+			}
+			version, err := getContentVersion(k8sClient, "default", from)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(version).To(Equal(server.URL + "/content#sha256=" + checksum))
+		})
+	})
+})
@@ -0,0 +1,104 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (r *DatabricksRepoReconciler) submit(instance *databricksv1alpha1.DatabricksRepo) error {
+	r.Log.Info(fmt.Sprintf("Create repo %s", instance.GetName()))
+
+	execution := NewExecution("databricksrepos", "create")
+	repo, err := newReposAPI(r.APIClient).Create(instance.Spec.URL, instance.Spec.Provider, instance.Spec.Path)
+	execution.Finish(err)
+	if err != nil {
+		return err
+	}
+
+	// the repo is cloned at the head of its default branch, which is checked on the next reconcile
+	now := metav1.Now()
+	instance.Status = &databricksv1alpha1.DatabricksRepoStatus{
+		ID:                  repo.ID,
+		URL:                 instance.Spec.URL,
+		Path:                repo.Path,
+		Branch:              repo.Branch,
+		HeadCommitID:        repo.HeadCommitID,
+		ObservedUpdateToken: instance.GetUpdateToken(),
+		LastUpdatedTime:     &now,
+	}
+
+	return r.Update(context.Background(), instance)
+}
+
+// update checks out the branch or tag in the spec, pulling the latest commit of a branch
+func (r *DatabricksRepoReconciler) update(instance *databricksv1alpha1.DatabricksRepo) error {
+	branch, tag := instance.Spec.Branch, instance.Spec.Tag
+	if tag == "" && branch == "" {
+		// stay on the branch that is checked out, which is the default branch unless one was set before
+		branch = instance.Status.Branch
+		if branch == "" {
+			return fmt.Errorf("a branch must be set to leave tag %s", instance.Status.Tag)
+		}
+	}
+
+	r.Log.Info(fmt.Sprintf("Update repo %s to branch %q tag %q", instance.GetName(), branch, tag))
+	execution := NewExecution("databricksrepos", "update")
+	repo, err := newReposAPI(r.APIClient).Update(instance.Status.ID, branch, tag)
+	execution.Finish(err)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	instance.Status.Path = repo.Path
+	instance.Status.Branch = repo.Branch
+	instance.Status.Tag = tag
+	instance.Status.HeadCommitID = repo.HeadCommitID
+	instance.Status.ObservedUpdateToken = instance.GetUpdateToken()
+	instance.Status.LastUpdatedTime = &now
+
+	return r.Update(context.Background(), instance)
+}
+
+func (r *DatabricksRepoReconciler) delete(instance *databricksv1alpha1.DatabricksRepo) error {
+	r.Log.Info(fmt.Sprintf("Deleting repo %s", instance.GetName()))
+
+	if !instance.IsSubmitted() {
+		return nil
+	}
+
+	execution := NewExecution("databricksrepos", "delete")
+	err := newReposAPI(r.APIClient).Delete(instance.Status.ID)
+	execution.Finish(err)
+	if err != nil && isResourceNotFound(err) {
+		return nil
+	}
+	return err
+}
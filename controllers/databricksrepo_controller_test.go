@@ -0,0 +1,127 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("DatabricksRepo Controller", func() {
+
+	var databricks *fakeDatabricks
+	var reconciler *DatabricksRepoReconciler
+	var key types.NamespacedName
+
+	reconcile := func() *databricksv1alpha1.DatabricksRepo {
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		repo := &databricksv1alpha1.DatabricksRepo{}
+		Expect(reconciler.Get(context.Background(), key, repo)).To(Succeed())
+		return repo
+	}
+
+	BeforeEach(func() {
+		databricks = newFakeDatabricks()
+
+		key = types.NamespacedName{Name: "t-repo", Namespace: "default"}
+		instance := &databricksv1alpha1.DatabricksRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: &databricksv1alpha1.DatabricksRepoSpec{
+				URL:      "https://github.com/org/repo",
+				Provider: "gitHub",
+				Path:     "/Repos/ci/repo",
+				Branch:   "release",
+			},
+		}
+
+		reconciler = &DatabricksRepoReconciler{
+			Client:    newFakeClient(instance),
+			Log:       ctrl.Log.WithName("controllers").WithName("DatabricksRepo"),
+			Recorder:  record.NewFakeRecorder(100),
+			APIClient: databricks.client(),
+		}
+	})
+
+	AfterEach(func() {
+		databricks.Close()
+	})
+
+	Context("Repo on a branch", func() {
+		It("Should create, check out and update on annotation bump", func() {
+			reconcile() // finalizer
+
+			repo := reconcile()
+			Expect(repo.IsSubmitted()).To(BeTrue())
+			Expect(repo.Status.Branch).To(Equal("main"))
+
+			repo = reconcile()
+			Expect(repo.Status.Branch).To(Equal("release"))
+			Expect(repo.Status.HeadCommitID).To(Equal("release-1"))
+
+			By("not pulling again without a reason")
+			reconcile()
+			Expect(databricks.calls["PATCH /api/2.0/repos/1"]).To(Equal(1))
+
+			By("pulling when the annotation changes")
+			repo.SetAnnotations(map[string]string{databricksv1alpha1.DatabricksRepoUpdateAnnotation: "2"})
+			Expect(reconciler.Update(context.Background(), repo)).To(Succeed())
+			repo = reconcile()
+			Expect(repo.Status.HeadCommitID).To(Equal("release-2"))
+			Expect(repo.Status.ObservedUpdateToken).To(Equal("2"))
+
+			By("checking out a tag")
+			repo.Spec.Branch = ""
+			repo.Spec.Tag = "v1.0.0"
+			Expect(reconciler.Update(context.Background(), repo)).To(Succeed())
+			repo = reconcile()
+			Expect(repo.Status.Tag).To(Equal("v1.0.0"))
+			Expect(repo.Status.HeadCommitID).To(Equal("tag-v1.0.0"))
+		})
+
+		It("Should create the repo again when the URL changes", func() {
+			reconcile()
+			repo := reconcile()
+			Expect(repo.Status.ID).To(Equal(int64(1)))
+
+			repo.Spec.URL = "https://github.com/org/other"
+			Expect(reconciler.Update(context.Background(), repo)).To(Succeed())
+			repo = reconcile()
+			Expect(repo.IsSubmitted()).To(BeFalse())
+			Expect(databricks.repos).To(BeEmpty())
+
+			repo = reconcile()
+			Expect(repo.Status.ID).To(Equal(int64(2)))
+			Expect(repo.Status.URL).To(Equal("https://github.com/org/other"))
+		})
+	})
+})
@@ -0,0 +1,211 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	db "github.com/xinsnake/databricks-sdk-golang"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+)
+
+const (
+	// DataBricks limits AddBlock and Put to 1MB of base64 encoded data,
+	// which is 768KB of raw data
+	dbfsMaxBlockSize = 1024 * 1024 / 4 * 3
+	// dbfsMaxReadSize is the largest length DataBricks returns from a single Read
+	dbfsMaxReadSize = 1024 * 1024
+	// dbfsBlockAttempts is the number of times a single block is sent before the upload is abandoned
+	dbfsBlockAttempts = 3
+	// dbfsStatusAttempts is the number of times GetStatus is polled until the uploaded file is visible
+	dbfsStatusAttempts = 5
+)
+
+var dbfsRetryInterval = 500 * time.Millisecond
+
+// dbfsUploader uploads files to DBFS and verifies them once written
+type dbfsUploader struct {
+	apiClient  dbazure.DBClient
+	objectType string
+}
+
+func newDbfsUploader(apiClient dbazure.DBClient, objectType string) dbfsUploader {
+	return dbfsUploader{
+		apiClient:  apiClient,
+		objectType: objectType,
+	}
+}
+
+// Upload writes data to path, overwriting any existing file, and returns the file info once the
+// uploaded size and hash have been verified. Small files are sent with a single Put, larger
+// files are streamed in blocks.
+func (u dbfsUploader) Upload(path string, data []byte) (dbmodels.FileInfo, error) {
+	var err error
+	if len(data) <= dbfsMaxBlockSize {
+		execution := NewExecution(u.objectType, "put")
+		err = u.apiClient.Dbfs().Put(path, data, true)
+		execution.Finish(err)
+	} else {
+		err = u.uploadBlocks(path, data)
+	}
+	if err != nil {
+		return dbmodels.FileInfo{}, err
+	}
+
+	return u.verify(path, data)
+}
+
+// uploadBlocks streams the data through a handle. If any step fails, the handle is closed and
+// the partially written file deleted, so the next attempt starts from a clean state.
+func (u dbfsUploader) uploadBlocks(path string, data []byte) (err error) {
+	execution := NewExecution(u.objectType, "create")
+	createResponse, err := u.apiClient.Dbfs().Create(path, true)
+	execution.Finish(err)
+	if err != nil {
+		return err
+	}
+
+	handle := createResponse.Handle
+	closed := false
+	defer func() {
+		if err == nil {
+			return
+		}
+		if !closed {
+			execution := NewExecution(u.objectType, "close")
+			closeErr := u.apiClient.Dbfs().Close(handle)
+			execution.Finish(closeErr)
+		}
+		execution := NewExecution(u.objectType, "delete")
+		deleteErr := u.apiClient.Dbfs().Delete(path, false)
+		execution.Finish(deleteErr)
+	}()
+
+	for i := 0; i < len(data); i += dbfsMaxBlockSize {
+		end := i + dbfsMaxBlockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err = u.addBlock(handle, data[i:end]); err != nil {
+			return fmt.Errorf("failed to add block at offset %d: %v", i, err)
+		}
+	}
+
+	execution = NewExecution(u.objectType, "close")
+	err = u.apiClient.Dbfs().Close(handle)
+	execution.Finish(err)
+	if err != nil {
+		return err
+	}
+	closed = true
+	return nil
+}
+
+func (u dbfsUploader) addBlock(handle int64, block []byte) (err error) {
+	for attempt := 1; attempt <= dbfsBlockAttempts; attempt++ {
+		execution := NewExecution(u.objectType, "add_block")
+		err = u.apiClient.Dbfs().AddBlock(handle, block)
+		execution.Finish(err)
+		if err == nil {
+			return nil
+		}
+		time.Sleep(time.Duration(attempt) * dbfsRetryInterval)
+	}
+	return err
+}
+
+// verify waits for the file to be visible, then checks its size and reads it back to compare hashes
+func (u dbfsUploader) verify(path string, data []byte) (dbmodels.FileInfo, error) {
+	var fileInfo dbmodels.FileInfo
+	var err error
+	for attempt := 1; attempt <= dbfsStatusAttempts; attempt++ {
+		fileInfo, err = u.getStatus(path)
+		if err == nil && fileInfo.FileSize == int64(len(data)) {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * dbfsRetryInterval)
+	}
+	if err != nil {
+		return fileInfo, err
+	}
+	if fileInfo.FileSize != int64(len(data)) {
+		return fileInfo, fmt.Errorf("uploaded file %s has size %d, expected %d", path, fileInfo.FileSize, len(data))
+	}
+
+	remote, err := u.read(path, fileInfo.FileSize)
+	if err != nil {
+		return fileInfo, err
+	}
+	if expected, actual := databricksv1alpha1.HashContent(data), databricksv1alpha1.HashContent(remote); expected != actual {
+		return fileInfo, fmt.Errorf("uploaded file %s has hash %s, expected %s", path, actual, expected)
+	}
+
+	return fileInfo, nil
+}
+
+// read returns the content of the file at path
+func (u dbfsUploader) read(path string, size int64) ([]byte, error) {
+	var buffer bytes.Buffer
+	for offset := int64(0); offset < size; {
+		execution := NewExecution(u.objectType, "read")
+		readResponse, err := u.apiClient.Dbfs().Read(path, offset, dbfsMaxReadSize)
+		execution.Finish(err)
+		if err != nil {
+			return nil, err
+		}
+		if readResponse.BytesRead == 0 {
+			break
+		}
+		buffer.Write(readResponse.Data)
+		offset += readResponse.BytesRead
+	}
+	return buffer.Bytes(), nil
+}
+
+// getStatus returns the file info of the file at path. Unlike Dbfs().GetStatus, which
+// discards the error returned by the server, it reports why the request failed, so callers
+// can tell a missing file from other failures.
+func (u dbfsUploader) getStatus(path string) (dbmodels.FileInfo, error) {
+	var fileInfo dbmodels.FileInfo
+	data := struct {
+		Path string `url:"path,omitempty"`
+	}{path}
+
+	execution := NewExecution(u.objectType, "get_status")
+	resp, err := db.PerformQuery(u.apiClient.Option, http.MethodGet, "/dbfs/get-status", data, nil)
+	execution.Finish(err)
+	if err != nil {
+		return fileInfo, err
+	}
+
+	err = json.Unmarshal(resp, &fileInfo)
+	return fileInfo, err
+}
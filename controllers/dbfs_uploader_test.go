@@ -0,0 +1,97 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"crypto/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DBFS uploader", func() {
+
+	var databricks *fakeDatabricks
+	var uploader dbfsUploader
+
+	BeforeEach(func() {
+		dbfsRetryInterval = 0
+		databricks = newFakeDatabricks()
+		uploader = newDbfsUploader(databricks.client(), "dbfsblocks")
+	})
+
+	AfterEach(func() {
+		databricks.Close()
+	})
+
+	Context("Uploading a small file", func() {
+		It("Should use a single put", func() {
+			data := make([]byte, 5000)
+			_, _ = rand.Read(data)
+
+			fileInfo, err := uploader.Upload("/small", data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fileInfo.FileSize).To(Equal(int64(5000)))
+			Expect(databricks.files["/small"]).To(Equal(data))
+			Expect(databricks.calls["POST /api/2.0/dbfs/put"]).To(Equal(1))
+			Expect(databricks.calls["POST /api/2.0/dbfs/create"]).To(Equal(0))
+		})
+	})
+
+	Context("Uploading a large file", func() {
+		It("Should stream near 1MB blocks", func() {
+			data := make([]byte, 3*dbfsMaxBlockSize+10)
+			_, _ = rand.Read(data)
+
+			fileInfo, err := uploader.Upload("/large", data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fileInfo.FileSize).To(Equal(int64(len(data))))
+			Expect(databricks.files["/large"]).To(Equal(data))
+			Expect(databricks.calls["POST /api/2.0/dbfs/add-block"]).To(Equal(4))
+			Expect(databricks.calls["POST /api/2.0/dbfs/close"]).To(Equal(1))
+		})
+
+		It("Should retry a failed block", func() {
+			data := make([]byte, 2*dbfsMaxBlockSize)
+			_, _ = rand.Read(data)
+			databricks.failAddBlock = 1
+
+			_, err := uploader.Upload("/retry", data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.files["/retry"]).To(Equal(data))
+		})
+
+		It("Should close the handle and delete the file when a block keeps failing", func() {
+			data := make([]byte, 2*dbfsMaxBlockSize)
+			_, _ = rand.Read(data)
+			databricks.failAddBlock = dbfsBlockAttempts
+
+			_, err := uploader.Upload("/failed", data)
+			Expect(err).To(HaveOccurred())
+			Expect(databricks.handles).To(BeEmpty())
+			Expect(databricks.files).ToNot(HaveKey("/failed"))
+		})
+	})
+})
@@ -27,15 +27,21 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 )
@@ -89,9 +95,19 @@ func (r *DbfsBlockReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, nil
 	}
 
-	if !instance.IsSubmitted() || !instance.IsUpToDate() {
+	upToDate := instance.IsUpToDate()
+	if instance.HasDataFrom() {
+		sourceVersion, err := getContentVersion(r.Client, instance.Namespace, instance.Spec.DataFrom)
+		if err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Resolving data", fmt.Sprintf("Failed to resolve data source: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when resolving DBFS block data source: %v", err)
+		}
+		upToDate = instance.IsSourceUpToDate(sourceVersion)
+	}
+
+	if !instance.IsSubmitted() || !upToDate {
 		r.Log.Info(fmt.Sprintf("Submit for %v", req.NamespacedName))
-		if err := r.submit(instance); err != nil {
+		if err := r.submit(instance, nil); err != nil {
 			r.Recorder.Event(instance, corev1.EventTypeWarning, "Submitting object", fmt.Sprintf("Failed to submit object: %s", err))
 			return ctrl.Result{}, fmt.Errorf("error when submitting DBFS block: %v", err)
 		}
@@ -99,12 +115,79 @@ func (r *DbfsBlockReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, nil
 	}
 
+	if instance.GetDriftDetection().IsEnabled() {
+		return r.detectDrift(instance)
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// detectDrift verifies the file in DBFS when it is due and handles any drift according to the policy
+func (r *DbfsBlockReconciler) detectDrift(instance *databricksv1alpha1.DbfsBlock) (ctrl.Result, error) {
+	detection := instance.GetDriftDetection()
+	if wait := detection.NextVerification(instance.Status.LastVerifiedTime, time.Now()); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	r.Log.Info(fmt.Sprintf("Verify for %s", instance.GetName()))
+	drift, err := r.verify(instance)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Verifying object", fmt.Sprintf("Failed to verify object: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when verifying DBFS block: %v", err)
+	}
+
+	if drift != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Drifted", drift.message)
+		if detection.IsEnforced() {
+			if err := r.submit(instance, drift); err != nil {
+				r.Recorder.Event(instance, corev1.EventTypeWarning, "Submitting object", fmt.Sprintf("Failed to submit object: %s", err))
+				return ctrl.Result{}, fmt.Errorf("error when submitting DBFS block: %v", err)
+			}
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Enforced", "Object is submitted again")
+			return ctrl.Result{RequeueAfter: detection.Interval()}, nil
+		}
+	}
+
+	now := metav1.Now()
+	instance.Status.LastVerifiedTime = &now
+	if drift != nil {
+		instance.Status.LastDriftTime = &now
+	}
+	instance.Status.Conditions = databricksv1alpha1.SetCondition(instance.Status.Conditions, inSyncCondition(drift))
+	if err := r.Update(context.Background(), instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: detection.Interval()}, nil
+}
+
 // SetupWithManager adds the controller manager
 func (r *DbfsBlockReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&databricksv1alpha1.DbfsBlock{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapDataFrom),
+		}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapDataFrom),
+		}).
 		Complete(r)
 }
+
+// mapDataFrom returns the DbfsBlocks that read their data from the config map or secret in the event
+func (r *DbfsBlockReconciler) mapDataFrom(object handler.MapObject) []reconcile.Request {
+	var blocks databricksv1alpha1.DbfsBlockList
+	if err := r.List(context.Background(), &blocks, client.InNamespace(object.Meta.GetNamespace())); err != nil {
+		r.Log.Info(fmt.Sprintf("Failed to list DbfsBlocks for %s: %v", object.Meta.GetName(), err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, block := range blocks.Items {
+		if block.Spec != nil && referencesContentObject(block.Spec.DataFrom, object) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: block.GetName(), Namespace: block.GetNamespace()},
+			})
+		}
+	}
+	return requests
+}
@@ -28,72 +28,100 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"time"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func (r *DbfsBlockReconciler) submit(instance *databricksv1alpha1.DbfsBlock) error {
+// submit uploads the block. Unless it is correcting a drift, the upload is skipped when the content is unchanged.
+func (r *DbfsBlockReconciler) submit(instance *databricksv1alpha1.DbfsBlock, drift *remoteDrift) error {
 	r.Log.Info(fmt.Sprintf("Create block %s", instance.GetName()))
 
-	data, err := base64.StdEncoding.DecodeString(instance.Spec.Data)
+	data, sourceVersion, err := r.getData(instance)
 	if err != nil {
 		return err
 	}
+	hash := databricksv1alpha1.HashContent(data)
 
-	// Open handler
-	execution := NewExecution("dbfsblocks", "create")
-	createResponse, err := r.APIClient.Dbfs().Create(instance.Spec.Path, true)
-	execution.Finish(err)
+	if drift == nil && instance.IsSubmitted() && instance.Status.FileHash == hash {
+		// the source has changed but its content has not, so there is nothing to upload
+		instance.Status.SourceVersion = sourceVersion
+		return r.Update(context.Background(), instance)
+	}
 
+	fileInfo, err := newDbfsUploader(r.APIClient, "dbfsblocks").Upload(instance.Spec.Path, data)
 	if err != nil {
 		return err
 	}
 
-	// DataBricks limits the AddBlock size to be 1024KB
-	var g = 1000
-	for i := 0; i < len(data); i += g {
-		execution = NewExecution("dbfsblocks", "add_block")
-
-		if i+g <= len(data) {
-			err = r.APIClient.Dbfs().AddBlock(createResponse.Handle, data[i:i+g])
+	if instance.Status == nil {
+		instance.Status = &databricksv1alpha1.DbfsBlockStatus{}
+	}
+	instance.Status.FileInfo = &fileInfo
+	instance.Status.FileHash = hash
+	instance.Status.SourceVersion = sourceVersion
+
+	if instance.GetDriftDetection().IsEnabled() {
+		// the uploader has just verified the file
+		now := metav1.Now()
+		instance.Status.LastVerifiedTime = &now
+		if drift != nil {
+			instance.Status.LastDriftTime = &now
+			instance.Status.Conditions = databricksv1alpha1.SetCondition(instance.Status.Conditions, driftCorrectedCondition(drift))
 		} else {
-			err = r.APIClient.Dbfs().AddBlock(createResponse.Handle, data[i:])
-		}
-
-		execution.Finish(err)
-
-		if err != nil {
-			return err
+			instance.Status.Conditions = databricksv1alpha1.SetCondition(instance.Status.Conditions, inSyncCondition(nil))
 		}
 	}
 
-	// Close handler
-	execution = NewExecution("dbfsblocks", "close")
-	err = r.APIClient.Dbfs().Close(createResponse.Handle)
-	execution.Finish(err)
+	return r.Update(context.Background(), instance)
+}
 
+// verify checks that the file in DBFS still has the size, and optionally the content, last uploaded
+func (r *DbfsBlockReconciler) verify(instance *databricksv1alpha1.DbfsBlock) (*remoteDrift, error) {
+	path := instance.Status.FileInfo.Path
+
+	uploader := newDbfsUploader(r.APIClient, "dbfsblocks")
+	fileInfo, err := uploader.getStatus(path)
 	if err != nil {
-		return err
+		if isResourceNotFound(err) {
+			return &remoteDrift{
+				reason:  databricksv1alpha1.DriftReasonMissing,
+				message: fmt.Sprintf("File %s no longer exists", path),
+			}, nil
+		}
+		return nil, err
 	}
 
-	time.Sleep(1 * time.Second)
-
-	// Refresh info
-	execution = NewExecution("dbfsblocks", "get_status")
-	fileInfo, err := r.APIClient.Dbfs().GetStatus(instance.Spec.Path)
-	execution.Finish(err)
-
-	if err != nil {
-		return err
+	if fileInfo.FileSize != instance.Status.FileInfo.FileSize {
+		return &remoteDrift{
+			reason:  databricksv1alpha1.DriftReasonSizeMismatch,
+			message: fmt.Sprintf("File %s has size %d, expected %d", path, fileInfo.FileSize, instance.Status.FileInfo.FileSize),
+		}, nil
 	}
 
-	instance.Status = &databricksv1alpha1.DbfsBlockStatus{
-		FileInfo: &fileInfo,
-		FileHash: instance.GetHash(),
+	if instance.GetDriftDetection().VerifyContent {
+		data, err := uploader.read(path, fileInfo.FileSize)
+		if err != nil {
+			return nil, err
+		}
+		if hash := databricksv1alpha1.HashContent(data); hash != instance.Status.FileHash {
+			return &remoteDrift{
+				reason:  databricksv1alpha1.DriftReasonContentMismatch,
+				message: fmt.Sprintf("File %s has hash %s, expected %s", path, hash, instance.Status.FileHash),
+			}, nil
+		}
 	}
 
-	return r.Update(context.Background(), instance)
+	return nil, nil
+}
+
+// getData returns the content of the block and, for blocks using DataFrom, the version of the source
+func (r *DbfsBlockReconciler) getData(instance *databricksv1alpha1.DbfsBlock) ([]byte, string, error) {
+	if instance.HasDataFrom() {
+		return getContent(r.Client, instance.Namespace, instance.Spec.DataFrom)
+	}
+	data, err := base64.StdEncoding.DecodeString(instance.Spec.Data)
+	return data, "", err
 }
 
 func (r *DbfsBlockReconciler) delete(instance *databricksv1alpha1.DbfsBlock) error {
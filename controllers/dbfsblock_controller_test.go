@@ -33,6 +33,7 @@ import (
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -127,4 +128,92 @@ var _ = Describe("DbfsBlock Controller", func() {
 			}, timeout, interval).ShouldNot(Succeed())
 		})
 	})
+
+	Context("Block from a config map", func() {
+		It("Should upload again when the config map changes", func() {
+
+			data := make([]byte, 2000)
+			_, _ = rand.Read(data)
+			data2 := make([]byte, 2500)
+			_, _ = rand.Read(data2)
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "t-block-data" + randomStringWithCharset(10, charset),
+					Namespace: "default",
+				},
+				BinaryData: map[string][]byte{"block": data},
+			}
+			Expect(k8sClient.Create(context.Background(), configMap)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), configMap)).Should(Succeed())
+			}()
+
+			key := types.NamespacedName{
+				Name:      "t-block-from-config-map" + randomStringWithCharset(10, charset),
+				Namespace: "default",
+			}
+
+			created := &databricksv1alpha1.DbfsBlock{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: &databricksv1alpha1.DbfsBlockSpec{
+					Path: "/some-path/test-block-from-config-map",
+					DataFrom: &databricksv1alpha1.ContentFrom{
+						ConfigMapKeyRef: &databricksv1alpha1.ContentKeyRef{
+							Name: configMap.GetName(),
+							Key:  "block",
+						},
+					},
+				},
+			}
+
+			// Create
+			Expect(k8sClient.Create(context.Background(), created)).Should(Succeed())
+
+			By("Expecting size to be 2000")
+			Eventually(func() int64 {
+				f := &databricksv1alpha1.DbfsBlock{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				if !f.IsSubmitted() {
+					return 0
+				}
+				return f.Status.FileInfo.FileSize
+			}, timeout, interval).Should(Equal(int64(2000)))
+
+			By("Expecting the hash to be computed over the config map content")
+			fetched := &databricksv1alpha1.DbfsBlock{}
+			Expect(k8sClient.Get(context.Background(), key, fetched)).Should(Succeed())
+			Expect(fetched.Status.FileHash).To(Equal(databricksv1alpha1.HashContent(data)))
+
+			// Update the source
+			updated := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: configMap.GetName(), Namespace: "default"}, updated)).Should(Succeed())
+			updated.BinaryData["block"] = data2
+			Expect(k8sClient.Update(context.Background(), updated)).Should(Succeed())
+
+			By("Expecting size to be 2500")
+			Eventually(func() int64 {
+				f := &databricksv1alpha1.DbfsBlock{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				return f.Status.FileInfo.FileSize
+			}, timeout, interval).Should(Equal(int64(2500)))
+
+			// Delete
+			By("Expecting to delete successfully")
+			Eventually(func() error {
+				f := &databricksv1alpha1.DbfsBlock{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				return k8sClient.Delete(context.Background(), f)
+			}, timeout, interval).Should(Succeed())
+
+			By("Expecting to delete finish")
+			Eventually(func() error {
+				f := &databricksv1alpha1.DbfsBlock{}
+				return k8sClient.Get(context.Background(), key, f)
+			}, timeout, interval).ShouldNot(Succeed())
+		})
+	})
 })
@@ -0,0 +1,155 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+)
+
+// DbfsDirectoryReconciler reconciles a DbfsDirectory object
+type DbfsDirectoryReconciler struct {
+	client.Client
+	Log       logr.Logger
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	APIClient dbazure.DBClient
+}
+
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=dbfsdirectories,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=dbfsdirectories/status,verbs=get;update;patch
+
+// Reconcile implements the reconciliation loop for the operator
+func (r *DbfsDirectoryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = context.Background()
+	_ = r.Log.WithValues("dbfsdirectory", req.NamespacedName)
+
+	instance := &databricksv1alpha1.DbfsDirectory{}
+
+	r.Log.Info(fmt.Sprintf("Starting reconcile loop for %v", req.NamespacedName))
+	defer r.Log.Info(fmt.Sprintf("Finish reconcile loop for %v", req.NamespacedName))
+
+	if err := r.Get(context.Background(), req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if instance.IsBeingDeleted() {
+		r.Log.Info(fmt.Sprintf("HandleFinalizer for %v", req.NamespacedName))
+		if err := r.handleFinalizer(instance); err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "deleting finalizer", fmt.Sprintf("Failed to delete finalizer: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when handling finalizer: %v", err)
+		}
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Deleted", "Object finalizer is deleted")
+		return ctrl.Result{}, nil
+	}
+
+	if !instance.HasFinalizer(databricksv1alpha1.DbfsDirectoryFinalizerName) {
+		r.Log.Info(fmt.Sprintf("AddFinalizer for %v", req.NamespacedName))
+		if err := r.addFinalizer(instance); err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Adding finalizer", fmt.Sprintf("Failed to add finalizer: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when adding finalizer: %v", err)
+		}
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Added", "Object finalizer is added")
+		return ctrl.Result{}, nil
+	}
+
+	r.Log.Info(fmt.Sprintf("Sync for %v", req.NamespacedName))
+	changed, err := r.sync(instance)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Syncing object", fmt.Sprintf("Failed to sync object: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when syncing DBFS directory: %v", err)
+	}
+	if changed {
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Synced", "Object is synced")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager adds the controller manager
+func (r *DbfsDirectoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databricksv1alpha1.DbfsDirectory{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapSources),
+		}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapSources),
+		}).
+		Complete(r)
+}
+
+// mapSources returns the DbfsDirectories that read files from the config map or secret in the event
+func (r *DbfsDirectoryReconciler) mapSources(object handler.MapObject) []reconcile.Request {
+	var directories databricksv1alpha1.DbfsDirectoryList
+	if err := r.List(context.Background(), &directories, client.InNamespace(object.Meta.GetNamespace())); err != nil {
+		r.Log.Info(fmt.Sprintf("Failed to list DbfsDirectories for %s: %v", object.Meta.GetName(), err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, directory := range directories.Items {
+		if directory.Spec != nil && directoryReferencesObject(directory.Spec, object) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: directory.GetName(), Namespace: directory.GetNamespace()},
+			})
+		}
+	}
+	return requests
+}
+
+func directoryReferencesObject(spec *databricksv1alpha1.DbfsDirectorySpec, object handler.MapObject) bool {
+	if _, ok := object.Object.(*corev1.ConfigMap); ok {
+		for _, configMap := range spec.ConfigMaps {
+			if configMap.Name == object.Meta.GetName() {
+				return true
+			}
+		}
+	}
+	for _, file := range spec.Files {
+		if referencesContentObject(file.DataFrom, object) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// dbfsDirectoryFile is a file the spec asks for, resolved to its full DBFS path and source version
+type dbfsDirectoryFile struct {
+	path    string
+	version string
+	read    func() ([]byte, error)
+}
+
+// sync uploads the files whose source has changed and deletes the files no longer in the spec.
+// It returns true if anything in DBFS or the status changed.
+func (r *DbfsDirectoryReconciler) sync(instance *databricksv1alpha1.DbfsDirectory) (bool, error) {
+	desired, err := r.getDesiredFiles(instance)
+	if err != nil {
+		// without the full list of files nothing can be pruned safely, so give up on this pass
+		return false, err
+	}
+
+	uploader := newDbfsUploader(r.APIClient, "dbfsdirectories")
+	files := make([]databricksv1alpha1.DbfsDirectoryFileStatus, 0, len(desired))
+	desiredPaths := make(map[string]bool, len(desired))
+	changed := false
+
+	for _, file := range desired {
+		desiredPaths[file.path] = true
+		previous := instance.GetFileStatus(file.path)
+		if previous != nil && previous.Error == "" && previous.SourceVersion == file.version {
+			files = append(files, *previous)
+			continue
+		}
+		changed = true
+		files = append(files, r.syncFile(uploader, file, previous))
+	}
+
+	if instance.Status != nil {
+		for _, previous := range instance.Status.Files {
+			if desiredPaths[previous.Path] {
+				continue
+			}
+			changed = true
+			r.Log.Info(fmt.Sprintf("Deleting file %s", previous.Path))
+			if err := r.deleteFile(previous.Path); err != nil {
+				// keep the file in the status so that the delete is retried
+				previous.Error = fmt.Sprintf("failed to delete file: %v", err)
+				files = append(files, previous)
+			}
+		}
+	}
+
+	if !changed && instance.IsSubmitted() {
+		return false, nil
+	}
+
+	var synced, failed int32
+	for _, file := range files {
+		if file.Error != "" {
+			failed++
+		} else {
+			synced++
+		}
+	}
+	now := metav1.Now()
+	instance.Status = &databricksv1alpha1.DbfsDirectoryStatus{
+		Files:          files,
+		SyncedFiles:    synced,
+		FailedFiles:    failed,
+		LastSyncedTime: &now,
+	}
+	if err := r.Update(context.Background(), instance); err != nil {
+		return true, err
+	}
+
+	if failed > 0 {
+		return true, fmt.Errorf("%d of %d files failed to sync", failed, len(files))
+	}
+	return true, nil
+}
+
+// syncFile reads a single file and uploads it unless its content is unchanged
+func (r *DbfsDirectoryReconciler) syncFile(uploader dbfsUploader, file dbfsDirectoryFile, previous *databricksv1alpha1.DbfsDirectoryFileStatus) databricksv1alpha1.DbfsDirectoryFileStatus {
+	status := databricksv1alpha1.DbfsDirectoryFileStatus{
+		Path:          file.path,
+		SourceVersion: file.version,
+	}
+	if previous != nil {
+		status.FileHash = previous.FileHash
+		status.FileSize = previous.FileSize
+		status.LastSyncedTime = previous.LastSyncedTime
+	}
+
+	data, err := file.read()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	hash := databricksv1alpha1.HashContent(data)
+
+	if previous != nil && previous.Error == "" && previous.FileHash == hash {
+		// the source has changed but its content has not, so there is nothing to upload
+		return status
+	}
+
+	r.Log.Info(fmt.Sprintf("Uploading file %s", file.path))
+	fileInfo, err := uploader.Upload(file.path, data)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	now := metav1.Now()
+	status.FileHash = hash
+	status.FileSize = fileInfo.FileSize
+	status.LastSyncedTime = &now
+	return status
+}
+
+// getDesiredFiles resolves the config maps and files in the spec into the list of files to sync
+func (r *DbfsDirectoryReconciler) getDesiredFiles(instance *databricksv1alpha1.DbfsDirectory) ([]dbfsDirectoryFile, error) {
+	var files []dbfsDirectoryFile
+	seen := map[string]bool{}
+	add := func(relativePath string, file dbfsDirectoryFile) error {
+		fullPath, err := joinDirectoryPath(instance.Spec.Path, relativePath)
+		if err != nil {
+			return err
+		}
+		if seen[fullPath] {
+			return fmt.Errorf("file %s is specified more than once", fullPath)
+		}
+		seen[fullPath] = true
+		file.path = fullPath
+		files = append(files, file)
+		return nil
+	}
+
+	for _, source := range instance.Spec.ConfigMaps {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(context.Background(), types.NamespacedName{Namespace: instance.Namespace, Name: source.Name}, configMap); err != nil {
+			return nil, fmt.Errorf("error when reading config map %s: %v", source.Name, err)
+		}
+		for key, value := range configMap.Data {
+			data := []byte(value)
+			version := objectContentVersion("configmap", &databricksv1alpha1.ContentKeyRef{Name: source.Name, Key: key}, configMap.ResourceVersion)
+			if err := add(source.Prefix+key, dbfsDirectoryFile{version: version, read: func() ([]byte, error) { return data, nil }}); err != nil {
+				return nil, err
+			}
+		}
+		for key, value := range configMap.BinaryData {
+			data := value
+			version := objectContentVersion("configmap", &databricksv1alpha1.ContentKeyRef{Name: source.Name, Key: key}, configMap.ResourceVersion)
+			if err := add(source.Prefix+key, dbfsDirectoryFile{version: version, read: func() ([]byte, error) { return data, nil }}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, file := range instance.Spec.Files {
+		var desired dbfsDirectoryFile
+		if file.DataFrom != nil {
+			from := file.DataFrom
+			version, err := getContentVersion(r.Client, instance.Namespace, from)
+			if err != nil {
+				return nil, fmt.Errorf("error when reading source of %s: %v", file.Path, err)
+			}
+			desired = dbfsDirectoryFile{version: version, read: func() ([]byte, error) {
+				data, _, err := getContent(r.Client, instance.Namespace, from)
+				return data, err
+			}}
+		} else {
+			data, err := base64.StdEncoding.DecodeString(file.Data)
+			if err != nil {
+				return nil, fmt.Errorf("error when decoding data of %s: %v", file.Path, err)
+			}
+			desired = dbfsDirectoryFile{version: "sha1:" + databricksv1alpha1.HashContent(data), read: func() ([]byte, error) { return data, nil }}
+		}
+		if err := add(file.Path, desired); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// joinDirectoryPath joins a relative path onto a DBFS or workspace directory, refusing paths that escape it
+func joinDirectoryPath(directory, relativePath string) (string, error) {
+	directory = path.Clean("/" + directory)
+	fullPath := path.Join(directory, relativePath)
+	if relativePath == "" || path.IsAbs(relativePath) || !strings.HasPrefix(fullPath, strings.TrimSuffix(directory, "/")+"/") {
+		return "", fmt.Errorf("file path %q must be relative to and inside %s", relativePath, directory)
+	}
+	return fullPath, nil
+}
+
+func (r *DbfsDirectoryReconciler) deleteFile(filePath string) error {
+	execution := NewExecution("dbfsdirectories", "delete")
+	err := r.APIClient.Dbfs().Delete(filePath, false)
+	execution.Finish(err)
+	if err != nil && strings.Contains(err.Error(), "RESOURCE_DOES_NOT_EXIST") {
+		return nil
+	}
+	return err
+}
+
+func (r *DbfsDirectoryReconciler) delete(instance *databricksv1alpha1.DbfsDirectory) error {
+	r.Log.Info(fmt.Sprintf("Deleting directory %s", instance.GetName()))
+
+	if instance.Status == nil {
+		return nil
+	}
+
+	for _, file := range instance.Status.Files {
+		if err := r.deleteFile(file.Path); err != nil {
+			return fmt.Errorf("error when deleting file %s: %v", file.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("DbfsDirectory Controller", func() {
+
+	const timeout = time.Second * 30
+	const interval = time.Second * 1
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	Context("File paths", func() {
+		It("Should only allow paths inside the directory", func() {
+			p, err := joinDirectoryPath("/some-path/dir", "lib/utils.py")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p).To(Equal("/some-path/dir/lib/utils.py"))
+
+			p, err = joinDirectoryPath("some-path/dir/", "main.py")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p).To(Equal("/some-path/dir/main.py"))
+
+			_, err = joinDirectoryPath("/some-path/dir", "../escape.py")
+			Expect(err).To(HaveOccurred())
+
+			_, err = joinDirectoryPath("/some-path/dir", "/etc/passwd")
+			Expect(err).To(HaveOccurred())
+
+			_, err = joinDirectoryPath("/some-path/dir", "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Directory from a config map and inline files", func() {
+		It("Should sync and prune files", func() {
+
+			data := make([]byte, 1500)
+			_, _ = rand.Read(data)
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "t-directory-data" + randomStringWithCharset(10, charset),
+					Namespace: "default",
+				},
+				Data: map[string]string{
+					"main.py":  "print('main')",
+					"utils.py": "print('utils')",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), configMap)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), configMap)).Should(Succeed())
+			}()
+
+			key := types.NamespacedName{
+				Name:      "t-directory" + randomStringWithCharset(10, charset),
+				Namespace: "default",
+			}
+
+			created := &databricksv1alpha1.DbfsDirectory{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: &databricksv1alpha1.DbfsDirectorySpec{
+					Path: "/some-path/test-directory-" + key.Name,
+					ConfigMaps: []databricksv1alpha1.DbfsDirectoryConfigMapSource{
+						{Name: configMap.GetName(), Prefix: "src/"},
+					},
+					Files: []databricksv1alpha1.DbfsDirectoryFile{
+						{Path: "data/blob.bin", Data: base64.StdEncoding.EncodeToString(data)},
+					},
+				},
+			}
+
+			// Create
+			Expect(k8sClient.Create(context.Background(), created)).Should(Succeed())
+
+			By("Expecting three files to be synced")
+			Eventually(func() int32 {
+				f := &databricksv1alpha1.DbfsDirectory{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				if !f.IsSubmitted() {
+					return 0
+				}
+				return f.Status.SyncedFiles
+			}, timeout, interval).Should(Equal(int32(3)))
+
+			fetched := &databricksv1alpha1.DbfsDirectory{}
+			Expect(k8sClient.Get(context.Background(), key, fetched)).Should(Succeed())
+			Expect(fetched.GetFileStatus(created.Spec.Path + "/data/blob.bin").FileSize).To(Equal(int64(1500)))
+			Expect(fetched.GetFileStatus(created.Spec.Path + "/src/main.py")).ToNot(BeNil())
+
+			// Remove a key from the source
+			updated := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: configMap.GetName(), Namespace: "default"}, updated)).Should(Succeed())
+			delete(updated.Data, "utils.py")
+			Expect(k8sClient.Update(context.Background(), updated)).Should(Succeed())
+
+			By("Expecting the removed file to be pruned")
+			Eventually(func() bool {
+				f := &databricksv1alpha1.DbfsDirectory{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				return f.GetFileStatus(created.Spec.Path+"/src/utils.py") == nil
+			}, timeout, interval).Should(BeTrue())
+
+			// Delete
+			By("Expecting to delete successfully")
+			Eventually(func() error {
+				f := &databricksv1alpha1.DbfsDirectory{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				return k8sClient.Delete(context.Background(), f)
+			}, timeout, interval).Should(Succeed())
+
+			By("Expecting to delete finish")
+			Eventually(func() error {
+				f := &databricksv1alpha1.DbfsDirectory{}
+				return k8sClient.Get(context.Background(), key, f)
+			}, timeout, interval).ShouldNot(Succeed())
+		})
+	})
+})
@@ -33,10 +33,13 @@ import (
 	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 )
@@ -52,6 +55,7 @@ type DjobReconciler struct {
 
 // +kubebuilder:rbac:groups=databricks.microsoft.com,resources=djobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=databricks.microsoft.com,resources=djobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runs,verbs=get;list;watch;create
 
 // Reconcile implements the reconciliation loop for the operator
 func (r *DjobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -106,11 +110,80 @@ func (r *DjobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			return ctrl.Result{}, fmt.Errorf("error when refreshing job: %v", err)
 		}
 		r.Recorder.Event(instance, corev1.EventTypeNormal, "Refreshed", "Object is refreshed")
+
+		triggered, err := r.triggerRun(instance)
+		if err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Triggering run", fmt.Sprintf("Failed to trigger run: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when triggering run: %v", err)
+		}
+		if triggered {
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Triggered", fmt.Sprintf("Run %s is created", instance.Status.LastTriggeredRun.RunName))
+		}
+
+		mirrored, err := r.mirrorRuns(instance)
+		if err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Mirroring runs", fmt.Sprintf("Failed to mirror runs: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when mirroring runs: %v", err)
+		}
+		if mirrored > 0 {
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Mirrored", fmt.Sprintf("%d runs are mirrored", mirrored))
+		}
 	}
 
 	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 }
 
+// triggerRun creates a Run of the job when spec.run_now.trigger was bumped, and records the
+// phase of the last triggered run. The Run controller submits the run through RunNow.
+func (r *DjobReconciler) triggerRun(instance *databricksv1alpha1.Djob) (bool, error) {
+	if instance.IsRunNowRequested() {
+		trigger := instance.Spec.RunNow.Trigger
+		run := &databricksv1alpha1.Run{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instance.GetTriggeredRunName(trigger),
+				Namespace: instance.GetNamespace(),
+				Labels:    map[string]string{databricksv1alpha1.DjobLabel: instance.GetName()},
+			},
+			Spec: &databricksv1alpha1.RunSpec{JobName: instance.GetName()},
+		}
+		if parameters := instance.Spec.RunNow.Parameters; parameters != nil {
+			runParameters := *parameters
+			run.Spec.RunParameters = &runParameters
+		}
+		if err := controllerutil.SetControllerReference(instance, run, r.Scheme); err != nil {
+			return false, err
+		}
+		if err := r.Create(context.Background(), run); err != nil && !errors.IsAlreadyExists(err) {
+			return false, err
+		}
+
+		instance.Status.LastTriggeredRun = &databricksv1alpha1.DjobTriggeredRun{
+			Trigger:     trigger,
+			RunName:     run.GetName(),
+			TriggerTime: metav1.Now(),
+		}
+		return true, r.Update(context.Background(), instance)
+	}
+
+	last := instance.Status.LastTriggeredRun
+	if last == nil {
+		return false, nil
+	}
+	run := &databricksv1alpha1.Run{}
+	if err := r.Get(context.Background(), types.NamespacedName{Namespace: instance.GetNamespace(), Name: last.RunName}, run); err != nil {
+		// the run may have been deleted after its TTL
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if run.Status == nil || run.Status.Phase == last.Phase {
+		return false, nil
+	}
+	last.Phase = run.Status.Phase
+	return false, r.Update(context.Background(), instance)
+}
+
 // SetupWithManager adds the controller manager
 func (r *DjobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -29,17 +29,34 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// djobRunsPageSize is the number of runs requested per runs/list call when mirroring runs
+	djobRunsPageSize = 25
+	// djobMirrorGracePeriod leaves recent runs for the next refresh, so that a run started
+	// by a Run is recorded in its status before it could be mirrored
+	djobMirrorGracePeriod = time.Minute
 )
 
 func (r *DjobReconciler) submit(instance *databricksv1alpha1.Djob) error {
 	r.Log.Info(fmt.Sprintf("Submitting job %s", instance.GetName()))
 	instance.Spec.Name = instance.GetName()
+	if err := instance.Spec.ValidateTasks(); err != nil {
+		return err
+	}
+	if instance.Spec.IsMultiTask() {
+		return r.submitMultiTask(instance)
+	}
 	//Get exisiting dbricks cluster by cluster name and set ExistingClusterID or
 	//Get exisiting dbricks cluster by cluster id
 	var ownerInstance databricksv1alpha1.Dcluster
@@ -92,8 +109,68 @@ func (r *DjobReconciler) submit(instance *databricksv1alpha1.Djob) error {
 	return r.Update(context.Background(), instance)
 }
 
+// submitMultiTask creates a multi-task job through Jobs API 2.1.
+// Tasks referencing a Dcluster by name are pointed at its cluster ID and the
+// referenced Dclusters become owners of the job.
+func (r *DjobReconciler) submitMultiTask(instance *databricksv1alpha1.Djob) error {
+	settings := multiTaskJobSettings{
+		Name:               instance.GetName(),
+		JobClusters:        instance.Spec.JobClusters,
+		EmailNotifications: instance.Spec.EmailNotifications,
+		TimeoutSeconds:     instance.Spec.TimeoutSeconds,
+		Schedule:           instance.Spec.Schedule,
+		MaxConcurrentRuns:  instance.Spec.MaxConcurrentRuns,
+		Format:             "MULTI_TASK",
+	}
+
+	var references []metav1.OwnerReference
+	owners := map[string]bool{}
+	for _, task := range instance.Spec.Tasks {
+		if len(task.ExistingClusterName) > 0 {
+			var dcluster databricksv1alpha1.Dcluster
+			dClusterNamespacedName := types.NamespacedName{Name: task.ExistingClusterName, Namespace: instance.Namespace}
+			if err := r.Get(context.Background(), dClusterNamespacedName, &dcluster); err != nil {
+				return err
+			}
+			if !dcluster.IsSubmitted() {
+				return fmt.Errorf("failed to get ClusterID of %v for task %s", task.ExistingClusterName, task.TaskKey)
+			}
+			task.ExistingClusterID = dcluster.Status.ClusterInfo.ClusterID
+			task.ExistingClusterName = ""
+			if !owners[dcluster.GetName()] && len(dcluster.APIVersion) > 0 && len(dcluster.Kind) > 0 {
+				owners[dcluster.GetName()] = true
+				references = append(references, metav1.OwnerReference{
+					APIVersion: dcluster.APIVersion,
+					Kind:       dcluster.Kind,
+					Name:       dcluster.GetName(),
+					UID:        dcluster.GetUID(),
+				})
+			}
+		}
+		settings.Tasks = append(settings.Tasks, task)
+	}
+	if len(references) > 0 {
+		instance.ObjectMeta.SetOwnerReferences(references)
+	}
+
+	execution := NewExecution("djobs", "create")
+	jobID, err := newJobsAPI(r.APIClient).Create(settings)
+	execution.Finish(err)
+	if err != nil {
+		return err
+	}
+
+	instance.Status = &databricksv1alpha1.DjobStatus{
+		JobStatus: &dbmodels.Job{JobID: jobID},
+	}
+	return r.Update(context.Background(), instance)
+}
+
 func (r *DjobReconciler) refresh(instance *databricksv1alpha1.Djob) error {
 	r.Log.Info(fmt.Sprintf("Refreshing job %s", instance.GetName()))
+	if instance.Spec.IsMultiTask() {
+		return r.refreshMultiTask(instance)
+	}
 
 	jobID := instance.Status.JobStatus.JobID
 
@@ -122,13 +199,182 @@ func (r *DjobReconciler) refresh(instance *databricksv1alpha1.Djob) error {
 		return nil
 	}
 
-	instance.Status = &databricksv1alpha1.DjobStatus{
-		JobStatus:  &job,
-		Last10Runs: jobRunListResponse.Runs,
+	instance.Status.JobStatus = &job
+	instance.Status.Last10Runs = jobRunListResponse.Runs
+	return r.Update(context.Background(), instance)
+}
+
+// refreshMultiTask records the job, with its tasks and job clusters, and its latest runs,
+// including the state of each task in the most recent run
+func (r *DjobReconciler) refreshMultiTask(instance *databricksv1alpha1.Djob) error {
+	jobID := instance.Status.JobStatus.JobID
+	api := newJobsAPI(r.APIClient)
+
+	execution := NewExecution("djobs", "get")
+	job, err := api.Get(jobID)
+	execution.Finish(err)
+	if err != nil {
+		return err
+	}
+
+	execution = NewExecution("djobs", "runs_list")
+	runs, err := api.RunsList(jobID, 10)
+	execution.Finish(err)
+	if err != nil {
+		return err
+	}
+
+	status := &databricksv1alpha1.DjobStatus{
+		JobStatus: &dbmodels.Job{
+			JobID:           job.JobID,
+			CreatorUserName: job.CreatorUserName,
+			CreatedTime:     job.CreatedTime,
+			Settings: &dbmodels.JobSettings{
+				Name:               job.Settings.Name,
+				EmailNotifications: job.Settings.EmailNotifications,
+				TimeoutSeconds:     job.Settings.TimeoutSeconds,
+				Schedule:           job.Settings.Schedule,
+				MaxConcurrentRuns:  job.Settings.MaxConcurrentRuns,
+			},
+		},
+		Tasks:       job.Settings.Tasks,
+		JobClusters: job.Settings.JobClusters,
+	}
+	for i, run := range runs {
+		status.Last10Runs = append(status.Last10Runs, run.Run)
+		if i > 0 {
+			continue
+		}
+		for _, task := range run.Tasks {
+			status.TaskRuns = append(status.TaskRuns, databricksv1alpha1.DjobTaskRun{
+				TaskKey:   task.TaskKey,
+				RunID:     task.RunID,
+				State:     task.State,
+				StartTime: task.StartTime,
+				EndTime:   task.EndTime,
+			})
+		}
 	}
+
+	err = r.Get(context.Background(), types.NamespacedName{
+		Name:      instance.GetName(),
+		Namespace: instance.GetNamespace(),
+	}, instance)
+	if err != nil {
+		return err
+	}
+
+	status.LastTriggeredRun = instance.Status.LastTriggeredRun
+	status.LastMirroredRunID = instance.Status.LastMirroredRunID
+	if reflect.DeepEqual(instance.Status, status) {
+		return nil
+	}
+
+	instance.Status = status
 	return r.Update(context.Background(), instance)
 }
 
+// mirrorRuns creates a read-only Run, owned by the job, for each run of the job started after
+// status.last_mirrored_run_id that is not tracked by a Run yet. Runs are listed newest first,
+// a page at a time, until the last mirrored run is reached. When nothing was mirrored yet, only
+// the first page is, so that turning mirroring on does not backfill the whole history of the job.
+func (r *DjobReconciler) mirrorRuns(instance *databricksv1alpha1.Djob) (int, error) {
+	if !instance.Spec.MirrorRuns || instance.Spec.IsMultiTask() || !instance.IsSubmitted() {
+		return 0, nil
+	}
+
+	tracked, err := r.getTrackedRunIDs(instance)
+	if err != nil {
+		return 0, err
+	}
+
+	jobID := instance.Status.JobStatus.JobID
+	lastMirrored := instance.Status.LastMirroredRunID
+	startedBefore := time.Now().Add(-djobMirrorGracePeriod).UnixNano() / int64(time.Millisecond)
+	highest := lastMirrored
+	var discovered []int64
+	for offset := int32(0); ; offset += djobRunsPageSize {
+		execution := NewExecution("djobs", "runs_list")
+		page, err := r.APIClient.Jobs().RunsList(false, false, jobID, offset, djobRunsPageSize)
+		execution.Finish(err)
+		if err != nil {
+			return 0, err
+		}
+
+		done := !page.HasMore || len(page.Runs) == 0 || lastMirrored == 0
+		for _, run := range page.Runs {
+			if run.RunID <= lastMirrored {
+				done = true
+				break
+			}
+			if run.StartTime > startedBefore {
+				continue
+			}
+			if run.RunID > highest {
+				highest = run.RunID
+			}
+			if !tracked[run.RunID] {
+				discovered = append(discovered, run.RunID)
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	for _, runID := range discovered {
+		run := &databricksv1alpha1.Run{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instance.GetMirroredRunName(runID),
+				Namespace: instance.GetNamespace(),
+				Labels:    map[string]string{databricksv1alpha1.DjobLabel: instance.GetName()},
+			},
+			Spec: &databricksv1alpha1.RunSpec{JobName: instance.GetName(), MirrorRunID: runID},
+		}
+		if err := controllerutil.SetControllerReference(instance, run, r.Scheme); err != nil {
+			return 0, err
+		}
+		if err := r.Create(context.Background(), run); err != nil && !errors.IsAlreadyExists(err) {
+			return 0, err
+		}
+	}
+
+	if highest == lastMirrored {
+		return 0, nil
+	}
+	instance.Status.LastMirroredRunID = highest
+	return len(discovered), r.Update(context.Background(), instance)
+}
+
+// getTrackedRunIDs returns the IDs of the DataBricks runs of the job that Runs in its namespace
+// already track, including their previous attempts
+func (r *DjobReconciler) getTrackedRunIDs(instance *databricksv1alpha1.Djob) (map[int64]bool, error) {
+	runs := &databricksv1alpha1.RunList{}
+	if err := r.List(context.Background(), runs, client.InNamespace(instance.GetNamespace())); err != nil {
+		return nil, err
+	}
+
+	tracked := map[int64]bool{}
+	for i := range runs.Items {
+		run := &runs.Items[i]
+		spec := run.GetEffectiveSpec()
+		if spec == nil || spec.JobName != instance.GetName() {
+			continue
+		}
+		if run.IsMirrored() {
+			tracked[run.Spec.MirrorRunID] = true
+		}
+		if run.Status == nil {
+			continue
+		}
+		tracked[run.Status.Metadata.RunID] = true
+		for _, attempt := range run.Status.Attempts {
+			tracked[attempt.RunID] = true
+		}
+	}
+	return tracked, nil
+}
+
 func (r *DjobReconciler) delete(instance *databricksv1alpha1.Djob) error {
 	r.Log.Info(fmt.Sprintf("Deleting job %s", instance.GetName()))
 
@@ -139,7 +385,13 @@ func (r *DjobReconciler) delete(instance *databricksv1alpha1.Djob) error {
 	jobID := instance.Status.JobStatus.JobID
 
 	// Check if the job exists before trying to delete it
-	if _, err := r.getJob(jobID); err != nil {
+	var err error
+	if instance.Spec.IsMultiTask() {
+		_, err = newJobsAPI(r.APIClient).Get(jobID)
+	} else {
+		_, err = r.getJob(jobID)
+	}
+	if err != nil {
 		if strings.Contains(err.Error(), "does not exist") {
 			return nil
 		}
@@ -147,7 +399,7 @@ func (r *DjobReconciler) delete(instance *databricksv1alpha1.Djob) error {
 	}
 
 	execution := NewExecution("djobs", "delete")
-	err := r.APIClient.Jobs().Delete(jobID)
+	err = r.APIClient.Jobs().Delete(jobID)
 	execution.Finish(err)
 	return err
 }
@@ -28,6 +28,7 @@ import (
 	"context"
 	"time"
 
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
@@ -35,6 +36,8 @@ import (
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 var _ = Describe("Djob Controller", func() {
@@ -53,6 +56,224 @@ var _ = Describe("Djob Controller", func() {
 	// your API definition.
 	// Avoid adding tests for vanilla CRUD operations because they would
 	// test Kubernetes API server, which isn't the goal here.
+	Context("Multi-task job", func() {
+		It("Should create the job with tasks and report task run states", func() {
+			databricks := newFakeDatabricks()
+			defer databricks.Close()
+
+			dcluster := &databricksv1alpha1.Dcluster{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "databricks.microsoft.com/v1alpha1", Kind: "Dcluster"},
+				ObjectMeta: metav1.ObjectMeta{Name: "t-shared-cluster", Namespace: "default"},
+				Status: &databricksv1alpha1.DclusterStatus{
+					ClusterInfo: &databricksv1alpha1.DclusterInfo{ClusterID: "0101-abc"},
+				},
+			}
+			key := types.NamespacedName{Name: "t-multi-task-job", Namespace: "default"}
+			instance := &databricksv1alpha1.Djob{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: &databricksv1alpha1.JobSettings{
+					Schedule:          &dbmodels.CronSchedule{QuartzCronExpression: "0 0 1 * * ?", TimezoneID: "UTC"},
+					MaxConcurrentRuns: 2,
+					JobClusters: []databricksv1alpha1.JobCluster{
+						{JobClusterKey: "etl", NewCluster: &dbmodels.NewCluster{SparkVersion: "7.3.x-scala2.12", NodeTypeID: "Standard_D3_v2", NumWorkers: 2}},
+					},
+					Tasks: []databricksv1alpha1.JobTaskSettings{
+						{
+							TaskKey:       "ingest",
+							JobClusterKey: "etl",
+							NotebookTask:  &dbmodels.NotebookTask{NotebookPath: "/ingest"},
+						},
+						{
+							TaskKey:             "train",
+							DependsOn:           []databricksv1alpha1.TaskDependency{{TaskKey: "ingest"}},
+							ExistingClusterName: dcluster.GetName(),
+							NotebookTask:        &dbmodels.NotebookTask{NotebookPath: "/train"},
+							Libraries:           []dbmodels.Library{{Pypi: &dbmodels.PythonPyPiLibrary{Package: "scikit-learn"}}},
+						},
+					},
+				},
+			}
+
+			reconciler := &DjobReconciler{
+				Client:    newFakeClient(dcluster, instance),
+				Log:       ctrl.Log.WithName("controllers").WithName("Djob"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key}) // finalizer
+			Expect(err).ToNot(HaveOccurred())
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(databricks.createdJob).ToNot(BeNil())
+			Expect(databricks.createdJob.Format).To(Equal("MULTI_TASK"))
+			Expect(databricks.createdJob.Tasks).To(HaveLen(2))
+			Expect(databricks.createdJob.Tasks[1].ExistingClusterID).To(Equal("0101-abc"))
+			Expect(databricks.createdJob.Tasks[1].ExistingClusterName).To(BeEmpty())
+			Expect(databricks.createdJob.Tasks[1].DependsOn).To(Equal([]databricksv1alpha1.TaskDependency{{TaskKey: "ingest"}}))
+
+			fetched := &databricksv1alpha1.Djob{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.IsSubmitted()).To(BeTrue())
+			Expect(fetched.Status.JobStatus.JobID).To(Equal(int64(42)))
+			Expect(fetched.Status.JobStatus.CreatorUserName).To(Equal("someone"))
+			Expect(fetched.Status.JobStatus.Settings.Schedule.QuartzCronExpression).To(Equal("0 0 1 * * ?"))
+			Expect(fetched.Status.JobStatus.Settings.MaxConcurrentRuns).To(Equal(int32(2)))
+			Expect(fetched.Status.JobClusters).To(HaveLen(1))
+			Expect(fetched.Status.Tasks).To(HaveLen(2))
+			Expect(fetched.Status.Tasks[1].ExistingClusterID).To(Equal("0101-abc"))
+			Expect(fetched.Spec.Tasks[1].ExistingClusterName).To(Equal(dcluster.GetName()))
+			Expect(fetched.GetOwnerReferences()).To(HaveLen(1))
+			Expect(fetched.GetOwnerReferences()[0].Name).To(Equal(dcluster.GetName()))
+			Expect(fetched.Status.Last10Runs).To(HaveLen(1))
+			Expect(fetched.Status.TaskRuns).To(HaveLen(2))
+			Expect(fetched.Status.TaskRuns[0].TaskKey).To(Equal("ingest"))
+			Expect(fetched.Status.TaskRuns[0].State.ResultState).ToNot(BeNil())
+			Expect(string(*fetched.Status.TaskRuns[0].State.ResultState)).To(Equal("SUCCESS"))
+			Expect(fetched.Status.TaskRuns[1].RunID).To(Equal(int64(9)))
+		})
+
+		It("Should reject tasks mixed with a job level task", func() {
+			reconciler := &DjobReconciler{Log: ctrl.Log.WithName("controllers").WithName("Djob")}
+			instance := &databricksv1alpha1.Djob{
+				ObjectMeta: metav1.ObjectMeta{Name: "t-invalid-multi-task-job", Namespace: "default"},
+				Spec: &databricksv1alpha1.JobSettings{
+					NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/ingest"},
+					Tasks:        []databricksv1alpha1.JobTaskSettings{{TaskKey: "ingest"}},
+				},
+			}
+			Expect(reconciler.submit(instance)).ToNot(Succeed())
+		})
+	})
+
+	Context("Job run now trigger", func() {
+		It("Should create a run for each trigger and record its phase", func() {
+			key := types.NamespacedName{Name: "t-job-run-now", Namespace: "default"}
+			instance := &databricksv1alpha1.Djob{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: &databricksv1alpha1.JobSettings{
+					NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/report"},
+					RunNow: &databricksv1alpha1.JobRunNow{
+						Trigger:    1,
+						Parameters: &dbmodels.RunParameters{NotebookParams: map[string]string{"date": "2020-01-01"}},
+					},
+				},
+				Status: &databricksv1alpha1.DjobStatus{JobStatus: &dbmodels.Job{JobID: 20}},
+			}
+			reconciler := &DjobReconciler{
+				Client: newFakeClient(instance),
+				Log:    ctrl.Log.WithName("controllers").WithName("Djob"),
+				Scheme: fakeScheme,
+			}
+
+			triggered, err := reconciler.triggerRun(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(triggered).To(BeTrue())
+			run := &databricksv1alpha1.Run{}
+			runKey := types.NamespacedName{Name: "t-job-run-now-run-1", Namespace: "default"}
+			Expect(reconciler.Get(context.Background(), runKey, run)).To(Succeed())
+			Expect(run.Spec.JobName).To(Equal(key.Name))
+			Expect(run.Spec.NotebookParams).To(Equal(map[string]string{"date": "2020-01-01"}))
+			Expect(run.GetLabels()[databricksv1alpha1.DjobLabel]).To(Equal(key.Name))
+			Expect(metav1.IsControlledBy(run, instance)).To(BeTrue())
+
+			By("Not triggering it again")
+			triggered, err = reconciler.triggerRun(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(triggered).To(BeFalse())
+
+			By("Recording the phase of the run")
+			run.Status = &databricksv1alpha1.RunStatus{Phase: databricksv1alpha1.RunPhaseRunning}
+			Expect(reconciler.Update(context.Background(), run)).To(Succeed())
+			_, err = reconciler.triggerRun(instance)
+			Expect(err).ToNot(HaveOccurred())
+			fetched := &databricksv1alpha1.Djob{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.LastTriggeredRun.RunName).To(Equal(runKey.Name))
+			Expect(fetched.Status.LastTriggeredRun.Phase).To(Equal(databricksv1alpha1.RunPhaseRunning))
+
+			By("Triggering a new run when the trigger is bumped")
+			fetched.Spec.RunNow.Trigger = 2
+			triggered, err = reconciler.triggerRun(fetched)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(triggered).To(BeTrue())
+			Expect(fetched.Status.LastTriggeredRun.RunName).To(Equal("t-job-run-now-run-2"))
+		})
+	})
+
+	Context("Job mirroring its runs", func() {
+		It("Should create a Run for each run that is not tracked yet", func() {
+			started := time.Now().Add(-time.Hour).UnixNano() / int64(time.Millisecond)
+			databricks := newFakeDatabricks()
+			defer databricks.Close()
+			databricks.jobRuns = []dbmodels.Run{{JobID: 30, RunID: 31, StartTime: time.Now().UnixNano() / int64(time.Millisecond)}}
+			for runID := int64(30); runID > 0; runID-- {
+				databricks.jobRuns = append(databricks.jobRuns, dbmodels.Run{JobID: 30, RunID: runID, StartTime: started})
+			}
+
+			key := types.NamespacedName{Name: "t-job-mirror-runs", Namespace: "default"}
+			instance := &databricksv1alpha1.Djob{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: &databricksv1alpha1.JobSettings{
+					NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/report"},
+					MirrorRuns:   true,
+				},
+				Status: &databricksv1alpha1.DjobStatus{JobStatus: &dbmodels.Job{JobID: 30}},
+			}
+			submitted := &databricksv1alpha1.Run{
+				ObjectMeta: metav1.ObjectMeta{Name: "t-job-mirror-runs-submitted", Namespace: key.Namespace},
+				Spec:       &databricksv1alpha1.RunSpec{JobName: key.Name},
+				Status: &databricksv1alpha1.RunStatus{
+					JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{Metadata: dbmodels.Run{JobID: 30, RunID: 29}},
+				},
+			}
+			reconciler := &DjobReconciler{
+				Client:    newFakeClient(instance, submitted),
+				Log:       ctrl.Log.WithName("controllers").WithName("Djob"),
+				Scheme:    fakeScheme,
+				APIClient: databricks.client(),
+			}
+
+			mirrored, err := reconciler.mirrorRuns(instance)
+			Expect(err).ToNot(HaveOccurred())
+			// only the first page of 25 runs is mirrored, without the recent and the tracked run
+			Expect(mirrored).To(Equal(23))
+			Expect(instance.Status.LastMirroredRunID).To(Equal(int64(30)))
+			Expect(databricks.calls["GET /api/2.0/jobs/runs/list"]).To(Equal(1))
+			run := &databricksv1alpha1.Run{}
+			runKey := types.NamespacedName{Name: "t-job-mirror-runs-7", Namespace: key.Namespace}
+			Expect(reconciler.Get(context.Background(), runKey, run)).To(Succeed())
+			Expect(run.Spec.JobName).To(Equal(key.Name))
+			Expect(run.Spec.MirrorRunID).To(Equal(int64(7)))
+			Expect(run.IsMirrored()).To(BeTrue())
+			Expect(run.GetLabels()[databricksv1alpha1.DjobLabel]).To(Equal(key.Name))
+			Expect(metav1.IsControlledBy(run, instance)).To(BeTrue())
+			notFound := reconciler.Get(context.Background(), types.NamespacedName{Name: "t-job-mirror-runs-6", Namespace: key.Namespace}, run)
+			Expect(notFound).To(HaveOccurred())
+			notFound = reconciler.Get(context.Background(), types.NamespacedName{Name: "t-job-mirror-runs-29", Namespace: key.Namespace}, run)
+			Expect(notFound).To(HaveOccurred())
+			notFound = reconciler.Get(context.Background(), types.NamespacedName{Name: "t-job-mirror-runs-31", Namespace: key.Namespace}, run)
+			Expect(notFound).To(HaveOccurred())
+
+			By("Not mirroring runs again once their Run is deleted")
+			run = &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), runKey, run)).To(Succeed())
+			Expect(reconciler.Delete(context.Background(), run)).To(Succeed())
+			mirrored, err = reconciler.mirrorRuns(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mirrored).To(Equal(0))
+			Expect(reconciler.Get(context.Background(), runKey, run)).ToNot(Succeed())
+
+			By("Mirroring the recent run after the grace period")
+			databricks.jobRuns[0].StartTime = started
+			mirrored, err = reconciler.mirrorRuns(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mirrored).To(Equal(1))
+			Expect(instance.Status.LastMirroredRunID).To(Equal(int64(31)))
+		})
+	})
+
 	Context("Job with schedule on New Cluster", func() {
 		It("Should create successfully", func() {
 
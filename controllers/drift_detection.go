@@ -0,0 +1,73 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// remoteDrift describes how a remote object differs from what the operator uploaded
+type remoteDrift struct {
+	reason  string
+	message string
+}
+
+// inSyncCondition returns the InSync condition for the result of a verification,
+// where a nil drift means the remote object matched
+func inSyncCondition(drift *remoteDrift) databricksv1alpha1.Condition {
+	if drift == nil {
+		return databricksv1alpha1.Condition{
+			Type:    databricksv1alpha1.ConditionTypeInSync,
+			Status:  corev1.ConditionTrue,
+			Reason:  databricksv1alpha1.DriftReasonVerified,
+			Message: "Remote object matches the last upload",
+		}
+	}
+	return databricksv1alpha1.Condition{
+		Type:    databricksv1alpha1.ConditionTypeInSync,
+		Status:  corev1.ConditionFalse,
+		Reason:  drift.reason,
+		Message: drift.message,
+	}
+}
+
+// driftCorrectedCondition returns the InSync condition after a drift was corrected by uploading again
+func driftCorrectedCondition(drift *remoteDrift) databricksv1alpha1.Condition {
+	return databricksv1alpha1.Condition{
+		Type:    databricksv1alpha1.ConditionTypeInSync,
+		Status:  corev1.ConditionTrue,
+		Reason:  databricksv1alpha1.DriftReasonCorrected,
+		Message: fmt.Sprintf("Uploaded again after drift: %s", drift.message),
+	}
+}
+
+// isResourceNotFound returns true if the DataBricks API error says the path does not exist
+func isResourceNotFound(err error) bool {
+	return strings.Contains(err.Error(), "RESOURCE_DOES_NOT_EXIST") || strings.Contains(err.Error(), "does not exist")
+}
@@ -0,0 +1,140 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("Drift detection", func() {
+
+	var databricks *fakeDatabricks
+	var reconciler *DbfsBlockReconciler
+	var block *databricksv1alpha1.DbfsBlock
+
+	BeforeEach(func() {
+		databricks = newFakeDatabricks()
+		reconciler = &DbfsBlockReconciler{
+			Log:       ctrl.Log.WithName("controllers").WithName("DbfsBlock"),
+			APIClient: databricks.client(),
+		}
+
+		data := []byte("drift detection")
+		databricks.files["/drift"] = data
+		block = &databricksv1alpha1.DbfsBlock{
+			Spec: &databricksv1alpha1.DbfsBlockSpec{
+				Path:           "/drift",
+				DriftDetection: &databricksv1alpha1.DriftDetection{IntervalSeconds: 60},
+			},
+			Status: &databricksv1alpha1.DbfsBlockStatus{
+				FileInfo: &dbmodels.FileInfo{Path: "/drift", FileSize: int64(len(data))},
+				FileHash: databricksv1alpha1.HashContent(data),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		databricks.Close()
+	})
+
+	Context("Verifying a DBFS block", func() {
+		It("Should find no drift when the file is unchanged", func() {
+			block.Spec.DriftDetection.VerifyContent = true
+			drift, err := reconciler.verify(block)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(BeNil())
+		})
+
+		It("Should detect a deleted file", func() {
+			delete(databricks.files, "/drift")
+			drift, err := reconciler.verify(block)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift.reason).To(Equal(databricksv1alpha1.DriftReasonMissing))
+		})
+
+		It("Should detect a resized file", func() {
+			databricks.files["/drift"] = []byte("changed")
+			drift, err := reconciler.verify(block)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift.reason).To(Equal(databricksv1alpha1.DriftReasonSizeMismatch))
+		})
+
+		It("Should only detect changed content when asked to", func() {
+			databricks.files["/drift"] = []byte("drift DETECTION")
+			drift, err := reconciler.verify(block)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift).To(BeNil())
+			Expect(databricks.calls["GET /api/2.0/dbfs/read"]).To(Equal(0))
+
+			block.Spec.DriftDetection.VerifyContent = true
+			drift, err = reconciler.verify(block)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drift.reason).To(Equal(databricksv1alpha1.DriftReasonContentMismatch))
+		})
+	})
+
+	Context("Reporting drift", func() {
+		It("Should set the InSync condition", func() {
+			condition := inSyncCondition(nil)
+			Expect(condition.Type).To(Equal(databricksv1alpha1.ConditionTypeInSync))
+			Expect(string(condition.Status)).To(Equal("True"))
+
+			condition = inSyncCondition(&remoteDrift{reason: databricksv1alpha1.DriftReasonMissing, message: "gone"})
+			Expect(string(condition.Status)).To(Equal("False"))
+			Expect(condition.Reason).To(Equal(databricksv1alpha1.DriftReasonMissing))
+			Expect(condition.Message).To(Equal("gone"))
+		})
+
+		It("Should record a drift corrected by the enforce policy", func() {
+			block.ObjectMeta = metav1.ObjectMeta{Name: "drift", Namespace: "default"}
+			block.Spec.Data = base64.StdEncoding.EncodeToString(databricks.files["/drift"])
+			block.Spec.DriftDetection.Policy = databricksv1alpha1.DriftPolicyEnforce
+			delete(databricks.files, "/drift")
+			reconciler.Client = newFakeClient(block)
+			reconciler.Recorder = record.NewFakeRecorder(100)
+
+			_, err := reconciler.detectDrift(block)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.files["/drift"]).To(Equal([]byte("drift detection")))
+
+			fetched := &databricksv1alpha1.DbfsBlock{}
+			Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "drift", Namespace: "default"}, fetched)).To(Succeed())
+			Expect(fetched.Status.LastDriftTime).ToNot(BeNil())
+			condition := databricksv1alpha1.GetCondition(fetched.Status.Conditions, databricksv1alpha1.ConditionTypeInSync)
+			Expect(string(condition.Status)).To(Equal("True"))
+			Expect(condition.Reason).To(Equal(databricksv1alpha1.DriftReasonCorrected))
+		})
+	})
+})
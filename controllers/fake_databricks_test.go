@@ -0,0 +1,378 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	"github.com/microsoft/azure-databricks-operator/controllers/jobsapi"
+	db "github.com/xinsnake/databricks-sdk-golang"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeScheme has the operator and core types registered for the fake clients
+var fakeScheme = runtime.NewScheme()
+
+func init() {
+	_ = corev1.AddToScheme(fakeScheme)
+	_ = databricksv1alpha1.AddToScheme(fakeScheme)
+}
+
+// newFakeClient returns a fake client holding the given objects
+func newFakeClient(objects ...runtime.Object) client.Client {
+	return fake.NewFakeClientWithScheme(fakeScheme, objects...)
+}
+
+// fakeDatabricks is an in-memory DataBricks workspace serving the REST API calls made by the controllers.
+// Requests are counted in calls, keyed by method and path, e.g. "POST /api/2.0/dbfs/put".
+type fakeDatabricks struct {
+	*httptest.Server
+	sync.Mutex
+	calls map[string]int
+
+	// Jobs API: every run shares the state below, a submitted run gets the next run id
+	lifeCycleState dbmodels.RunLifeCycleState
+	resultState    *dbmodels.RunResultState
+	result         string
+	notebookTask   *dbmodels.NotebookTask
+	// jobRuns are listed by runs/list of the 2.0 API, newest first
+	jobRuns []dbmodels.Run
+	// createdJob is the last multi-task job created through the 2.1 API
+	createdJob *multiTaskJobSettings
+
+	// Repos API
+	repos      map[int64]*repoInfo
+	nextRepoID int64
+
+	// Workspace API: a single notebook
+	notebookPath    string
+	notebookContent []byte
+
+	// DBFS API
+	files        map[string][]byte
+	handles      map[int64]string
+	nextHandle   int64
+	failAddBlock int
+
+	// Secrets API: the values of the secrets by scope
+	secretScopes  map[string]map[string]string
+	failPutSecret int
+}
+
+// newFakeDatabricks starts an empty fake workspace. Close it once the spec is done.
+func newFakeDatabricks() *fakeDatabricks {
+	f := &fakeDatabricks{
+		calls:   map[string]int{},
+		repos:   map[int64]*repoInfo{},
+		files:   map[string][]byte{},
+		handles: map[int64]string{},
+
+		secretScopes: map[string]map[string]string{},
+	}
+	f.Server = httptest.NewServer(f)
+	return f
+}
+
+// client returns an API client for the fake workspace
+func (f *fakeDatabricks) client() dbazure.DBClient {
+	var apiClient dbazure.DBClient
+	apiClient.Init(db.DBClientOption{Host: f.URL, Token: "fake"})
+	return apiClient
+}
+
+func (f *fakeDatabricks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	f.calls[r.Method+" "+r.URL.Path]++
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/2.0/jobs/"):
+		f.serveJobs(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/2.1/jobs/"):
+		f.serveMultiTaskJobs(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/2.0/repos"):
+		f.serveRepos(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/2.0/workspace/"):
+		f.serveWorkspace(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/2.0/dbfs/"):
+		f.serveDbfs(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/2.0/secrets/"):
+		f.serveSecrets(w, r)
+	default:
+		notFound(w)
+	}
+}
+
+func notFound(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = w.Write([]byte(`{"error_code":"RESOURCE_DOES_NOT_EXIST"}`))
+}
+
+func (f *fakeDatabricks) runID() int64 {
+	return int64(2 + f.calls["POST /api/2.0/jobs/runs/submit"])
+}
+
+func (f *fakeDatabricks) serveJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/2.0/jobs/runs/submit":
+		var submit dbmodels.JobTask
+		_ = json.NewDecoder(r.Body).Decode(&submit)
+		f.notebookTask = submit.NotebookTask
+		_ = json.NewEncoder(w).Encode(dbmodels.Run{RunID: f.runID()})
+	case "/api/2.0/jobs/runs/get-output":
+		_ = json.NewEncoder(w).Encode(dbazure.JobsRunsGetOutputResponse{
+			NotebookOutput: dbmodels.NotebookOutput{Result: f.result},
+			Metadata: dbmodels.Run{
+				JobID: 1,
+				RunID: f.runID(),
+				State: &dbmodels.RunState{LifeCycleState: &f.lifeCycleState, ResultState: f.resultState},
+			},
+		})
+	case "/api/2.0/jobs/runs/cancel":
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/jobs/runs/list":
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end := offset + limit
+		if end > len(f.jobRuns) {
+			end = len(f.jobRuns)
+		}
+		_ = json.NewEncoder(w).Encode(dbazure.JobsRunsListResponse{Runs: f.jobRuns[offset:end], HasMore: end < len(f.jobRuns)})
+	default:
+		notFound(w)
+	}
+}
+
+func (f *fakeDatabricks) serveMultiTaskJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/2.1/jobs/create":
+		f.createdJob = &multiTaskJobSettings{}
+		_ = json.NewDecoder(r.Body).Decode(f.createdJob)
+		_, _ = w.Write([]byte(`{"job_id":42}`))
+	case "/api/2.1/jobs/get":
+		_ = json.NewEncoder(w).Encode(multiTaskJob{JobID: 42, CreatorUserName: "someone", Settings: *f.createdJob})
+	case "/api/2.1/jobs/runs/list":
+		_, _ = w.Write([]byte(`{"runs":[{"job_id":42,"run_id":7,"state":{"life_cycle_state":"RUNNING"},"tasks":[
+			{"task_key":"ingest","run_id":8,"state":{"life_cycle_state":"TERMINATED","result_state":"SUCCESS"}},
+			{"task_key":"train","run_id":9,"state":{"life_cycle_state":"RUNNING"}}]}]}`))
+	case "/api/2.1/jobs/runs/get-output":
+		_ = json.NewEncoder(w).Encode(jobsapi.RunOutput{
+			JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				NotebookOutput: dbmodels.NotebookOutput{Result: f.result},
+				Error:          "notebook failed",
+			},
+			ErrorTrace: "Traceback",
+		})
+	default:
+		notFound(w)
+	}
+}
+
+func (f *fakeDatabricks) serveRepos(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		URL      string `json:"url"`
+		Provider string `json:"provider"`
+		Path     string `json:"path"`
+		Branch   string `json:"branch"`
+		Tag      string `json:"tag"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&request)
+
+	id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/2.0/repos/"), 10, 64)
+	switch {
+	case r.Method == http.MethodPost:
+		f.nextRepoID++
+		f.repos[f.nextRepoID] = &repoInfo{ID: f.nextRepoID, URL: request.URL, Provider: request.Provider, Path: request.Path, Branch: "main", HeadCommitID: "main-1"}
+		_ = json.NewEncoder(w).Encode(f.repos[f.nextRepoID])
+	case f.repos[id] == nil:
+		notFound(w)
+	case r.Method == http.MethodPatch:
+		repo := f.repos[id]
+		if request.Tag != "" {
+			repo.Branch = ""
+			repo.HeadCommitID = "tag-" + request.Tag
+		} else {
+			repo.Branch = request.Branch
+			repo.HeadCommitID = request.Branch + "-" + strconv.Itoa(f.calls[r.Method+" "+r.URL.Path])
+		}
+		_ = json.NewEncoder(w).Encode(repo)
+	case r.Method == http.MethodDelete:
+		delete(f.repos, id)
+		_, _ = w.Write([]byte("{}"))
+	default:
+		_ = json.NewEncoder(w).Encode(f.repos[id])
+	}
+}
+
+func (f *fakeDatabricks) serveWorkspace(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("path") != f.notebookPath {
+		notFound(w)
+		return
+	}
+	switch r.URL.Path {
+	case "/api/2.0/workspace/get-status":
+		_ = json.NewEncoder(w).Encode(dbmodels.ObjectInfo{Path: f.notebookPath})
+	case "/api/2.0/workspace/export":
+		_ = json.NewEncoder(w).Encode(map[string]string{"content": base64.StdEncoding.EncodeToString(f.notebookContent)})
+	default:
+		notFound(w)
+	}
+}
+
+func (f *fakeDatabricks) serveDbfs(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Path     string `json:"path"`
+		Handle   int64  `json:"handle"`
+		Data     string `json:"data"`
+		Contents string `json:"contents"`
+	}
+	if r.Method == http.MethodPost {
+		_ = json.NewDecoder(r.Body).Decode(&request)
+	} else {
+		request.Path = r.URL.Query().Get("path")
+	}
+
+	switch r.URL.Path {
+	case "/api/2.0/dbfs/put":
+		data, _ := base64.StdEncoding.DecodeString(request.Contents)
+		f.files[request.Path] = data
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/dbfs/create":
+		f.nextHandle++
+		f.handles[f.nextHandle] = request.Path
+		f.files[request.Path] = []byte{}
+		_ = json.NewEncoder(w).Encode(map[string]int64{"handle": f.nextHandle})
+	case "/api/2.0/dbfs/add-block":
+		if f.failAddBlock > 0 {
+			f.failAddBlock--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		path := f.handles[request.Handle]
+		data, _ := base64.StdEncoding.DecodeString(request.Data)
+		f.files[path] = append(f.files[path], data...)
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/dbfs/close":
+		delete(f.handles, request.Handle)
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/dbfs/delete":
+		delete(f.files, request.Path)
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/dbfs/get-status":
+		data, ok := f.files[request.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":"RESOURCE_DOES_NOT_EXIST","message":"No file or directory exists on path"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(dbmodels.FileInfo{Path: request.Path, FileSize: int64(len(data))})
+	case "/api/2.0/dbfs/read":
+		data := f.files[request.Path]
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		length, _ := strconv.Atoi(r.URL.Query().Get("length"))
+		end := offset + length
+		if end > len(data) {
+			end = len(data)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"bytes_read": end - offset,
+			"data":       base64.StdEncoding.EncodeToString(data[offset:end]),
+		})
+	default:
+		notFound(w)
+	}
+}
+
+func (f *fakeDatabricks) serveSecrets(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Scope       string `json:"scope"`
+		Key         string `json:"key"`
+		StringValue string `json:"string_value"`
+		BytesValue  string `json:"bytes_value"`
+	}
+	if r.Method == http.MethodPost {
+		_ = json.NewDecoder(r.Body).Decode(&request)
+	} else {
+		request.Scope = r.URL.Query().Get("scope")
+	}
+
+	secrets, ok := f.secretScopes[request.Scope]
+	if !ok && !strings.HasPrefix(r.URL.Path, "/api/2.0/secrets/scopes/") {
+		notFound(w)
+		return
+	}
+	switch r.URL.Path {
+	case "/api/2.0/secrets/scopes/create":
+		if ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error_code":"RESOURCE_ALREADY_EXISTS","message":"Scope ` + request.Scope + ` already exists!"}`))
+			return
+		}
+		f.secretScopes[request.Scope] = map[string]string{}
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/secrets/scopes/list":
+		scopes := []dbmodels.SecretScope{}
+		for name := range f.secretScopes {
+			scopes = append(scopes, dbmodels.SecretScope{Name: name})
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]dbmodels.SecretScope{"scopes": scopes})
+	case "/api/2.0/secrets/scopes/delete":
+		delete(f.secretScopes, request.Scope)
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/secrets/list":
+		metadata := []dbmodels.SecretMetadata{}
+		for key := range secrets {
+			metadata = append(metadata, dbmodels.SecretMetadata{Key: key})
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]dbmodels.SecretMetadata{"secrets": metadata})
+	case "/api/2.0/secrets/put":
+		if f.failPutSecret > 0 {
+			f.failPutSecret--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		secrets[request.Key] = request.StringValue + request.BytesValue
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/secrets/delete":
+		delete(secrets, request.Key)
+		_, _ = w.Write([]byte("{}"))
+	case "/api/2.0/secrets/acls/list":
+		_, _ = w.Write([]byte(`{"acls":[]}`))
+	default:
+		notFound(w)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	"github.com/microsoft/azure-databricks-operator/controllers/jobsapi"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+)
+
+// multiTaskJobSettings is the Jobs API 2.1 representation of a multi-task job
+type multiTaskJobSettings struct {
+	Name               string                               `json:"name,omitempty"`
+	Tasks              []databricksv1alpha1.JobTaskSettings `json:"tasks"`
+	JobClusters        []databricksv1alpha1.JobCluster      `json:"job_clusters,omitempty"`
+	EmailNotifications *dbmodels.JobEmailNotifications      `json:"email_notifications,omitempty"`
+	TimeoutSeconds     int32                                `json:"timeout_seconds,omitempty"`
+	Schedule           *dbmodels.CronSchedule               `json:"schedule,omitempty"`
+	MaxConcurrentRuns  int32                                `json:"max_concurrent_runs,omitempty"`
+	Format             string                               `json:"format,omitempty"`
+}
+
+// multiTaskJob is a job as returned by Jobs API 2.1
+type multiTaskJob struct {
+	JobID           int64                `json:"job_id"`
+	CreatorUserName string               `json:"creator_user_name,omitempty"`
+	CreatedTime     int64                `json:"created_time,omitempty"`
+	Settings        multiTaskJobSettings `json:"settings"`
+}
+
+// multiTaskRun is a run as returned by Jobs API 2.1, including the runs of its tasks
+type multiTaskRun struct {
+	dbmodels.Run
+	Tasks []struct {
+		TaskKey   string             `json:"task_key"`
+		RunID     int64              `json:"run_id"`
+		State     *dbmodels.RunState `json:"state"`
+		StartTime int64              `json:"start_time"`
+		EndTime   int64              `json:"end_time"`
+	} `json:"tasks,omitempty"`
+}
+
+// jobsAPI calls Jobs API 2.1 with the options of the DataBricks client, for the jobs the SDK does not cover
+type jobsAPI struct {
+	client *jobsapi.Client
+}
+
+func newJobsAPI(apiClient dbazure.DBClient) jobsAPI {
+	return jobsAPI{client: jobsapi.NewClient(apiClient.Option)}
+}
+
+// Create creates a multi-task job and returns its ID
+func (a jobsAPI) Create(settings multiTaskJobSettings) (int64, error) {
+	var resp struct {
+		JobID int64 `json:"job_id"`
+	}
+	err := a.client.Query(http.MethodPost, "/jobs/create", settings, &resp)
+	return resp.JobID, err
+}
+
+// Get returns the job with the specified ID
+func (a jobsAPI) Get(jobID int64) (multiTaskJob, error) {
+	var job multiTaskJob
+	err := a.client.Query(http.MethodGet, "/jobs/get", url.Values{"job_id": {strconv.FormatInt(jobID, 10)}}, &job)
+	return job, err
+}
+
+// RunsList returns the latest runs of the job with the state of their tasks
+func (a jobsAPI) RunsList(jobID int64, limit int) ([]multiTaskRun, error) {
+	var resp struct {
+		Runs []multiTaskRun `json:"runs"`
+	}
+	err := a.client.Query(http.MethodGet, "/jobs/runs/list", url.Values{
+		"job_id":       {strconv.FormatInt(jobID, 10)},
+		"limit":        {strconv.Itoa(limit)},
+		"expand_tasks": {"true"},
+	}, &resp)
+	return resp.Runs, err
+}
+
+// RunsGetOutput returns the output of the run with the specified ID
+func (a jobsAPI) RunsGetOutput(runID int64) (jobsapi.RunOutput, error) {
+	return a.client.RunsGetOutput(runID)
+}
@@ -0,0 +1,175 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package jobsapi calls Jobs API 2.1 of DataBricks, which the DataBricks SDK does not cover yet.
+// The SDK always prefixes paths with /api/2.0, so requests are built here from the options of the SDK.
+package jobsapi
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	db "github.com/xinsnake/databricks-sdk-golang"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+)
+
+// Version is the version of the Jobs API
+const Version = "2.1"
+
+// RunOutput is the output of a run as returned by Jobs API 2.1, which includes the error trace
+type RunOutput struct {
+	dbazure.JobsRunsGetOutputResponse
+	ErrorTrace string `json:"error_trace,omitempty"`
+}
+
+// Client sends requests to Jobs API 2.1 with the host, credentials and headers of the SDK option
+type Client struct {
+	option     db.DBClientOption
+	httpClient *http.Client
+}
+
+// httpClientKey identifies the settings an HTTP client is created with
+type httpClientKey struct {
+	insecureSkipVerify bool
+	timeoutSeconds     int
+}
+
+var (
+	httpClientsLock sync.Mutex
+	// httpClients are shared by the clients with the same settings, so that connections are reused
+	httpClients = map[httpClientKey]*http.Client{}
+)
+
+// NewClient returns a client for the option, it reuses the connections of clients with the same settings
+func NewClient(option db.DBClientOption) *Client {
+	key := httpClientKey{insecureSkipVerify: option.InsecureSkipVerify, timeoutSeconds: option.TimeoutSeconds}
+	if key.timeoutSeconds == 0 {
+		key.timeoutSeconds = 10
+	}
+
+	httpClientsLock.Lock()
+	defer httpClientsLock.Unlock()
+	httpClient, ok := httpClients[key]
+	if !ok {
+		httpClient = &http.Client{
+			Timeout: time.Duration(key.timeoutSeconds) * time.Second,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: key.insecureSkipVerify},
+			},
+		}
+		httpClients[key] = httpClient
+	}
+	return &Client{option: option, httpClient: httpClient}
+}
+
+// RunsGetOutput returns the output of the run with the specified ID
+func (c *Client) RunsGetOutput(runID int64) (RunOutput, error) {
+	var output RunOutput
+	err := c.Query(http.MethodGet, "/jobs/runs/get-output", url.Values{"run_id": {strconv.FormatInt(runID, 10)}}, &output)
+	return output, err
+}
+
+// Query sends a request to the path of Jobs API 2.1 and decodes the response into out.
+// url.Values are sent as the query string, any other data as the JSON body.
+func (c *Client) Query(method, path string, data interface{}, out interface{}) error {
+	requestURL, err := RequestURL(c.option.Host, path)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if params, ok := data.(url.Values); ok {
+		requestURL += "?" + params.Encode()
+	} else if data != nil {
+		if body, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+
+	request, err := http.NewRequest(method, requestURL, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range Headers(c.option) {
+		request.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Response from server (%d) %s", resp.StatusCode, string(respBody))
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// RequestURL returns the URL of the path of Jobs API 2.1 on the host, keeping the path the host may have
+func RequestURL(host, path string) (string, error) {
+	hostURL, err := url.Parse(host)
+	if err != nil {
+		return "", err
+	}
+	if hostURL.Scheme == "" || hostURL.Host == "" {
+		return "", fmt.Errorf("host %s should be an absolute URL", host)
+	}
+	hostURL.Path = strings.TrimSuffix(hostURL.Path, "/") + "/api/" + Version + path
+	hostURL.RawQuery = ""
+	hostURL.Fragment = ""
+	return hostURL.String(), nil
+}
+
+// Headers returns the headers the SDK sends with the option: the credentials,
+// the default headers of the option and the user agent of the SDK
+func Headers(option db.DBClientOption) map[string]string {
+	headers := map[string]string{}
+	if option.User != "" && option.Password != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(option.User+":"+option.Password))
+	} else if option.Token != "" {
+		headers["Authorization"] = "Bearer " + option.Token
+	}
+	headers["Content-Type"] = "application/json"
+	for k, v := range option.DefaultHeaders {
+		headers[k] = v
+	}
+	headers["User-Agent"] = fmt.Sprintf("databricks-sdk-golang-%s", db.SdkVersion)
+	return headers
+}
@@ -0,0 +1,105 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package jobsapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	db "github.com/xinsnake/databricks-sdk-golang"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+)
+
+func TestRequestURL_KeepsHostPath(t *testing.T) {
+	// Act
+	requestURL, err := RequestURL("https://gateway.example.com/databricks/", "/jobs/get")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "https://gateway.example.com/databricks/api/2.1/jobs/get", requestURL)
+}
+
+func TestRequestURL_RejectsRelativeHost(t *testing.T) {
+	// Act
+	_, err := RequestURL("westeurope.azuredatabricks.net", "/jobs/get")
+
+	// Assert
+	assert.NotNil(t, err)
+}
+
+func TestNewClient_SharesHTTPClients(t *testing.T) {
+	// Act
+	first := NewClient(db.DBClientOption{Host: "https://one", Token: "a"})
+	second := NewClient(db.DBClientOption{Host: "https://two", Token: "b", TimeoutSeconds: 10})
+	insecure := NewClient(db.DBClientOption{Host: "https://two", InsecureSkipVerify: true})
+
+	// Assert
+	assert.True(t, first.httpClient == second.httpClient)
+	assert.False(t, first.httpClient == insecure.httpClient)
+}
+
+func TestClient_RunsGetOutput(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/prefix/api/2.1/jobs/runs/get-output", r.URL.Path)
+		assert.Equal(t, "7", r.URL.Query().Get("run_id"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "yes", r.Header.Get("X-Custom"))
+		_ = json.NewEncoder(w).Encode(RunOutput{
+			JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{NotebookOutput: dbmodels.NotebookOutput{Result: "done"}},
+			ErrorTrace:                "Traceback",
+		})
+	}))
+	defer server.Close()
+	client := NewClient(db.DBClientOption{Host: server.URL + "/prefix", Token: "token", DefaultHeaders: map[string]string{"X-Custom": "yes"}})
+
+	// Act
+	output, err := client.RunsGetOutput(7)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "done", output.NotebookOutput.Result)
+	assert.Equal(t, "Traceback", output.ErrorTrace)
+}
+
+func TestClient_Query_ReturnsServerErrors(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error_code":"RESOURCE_DOES_NOT_EXIST"}`))
+	}))
+	defer server.Close()
+
+	// Act
+	var out struct{}
+	err := NewClient(db.DBClientOption{Host: server.URL}).Query(http.MethodGet, "/jobs/get", nil, &out)
+
+	// Assert
+	assert.EqualError(t, err, `Response from server (404) {"error_code":"RESOURCE_DOES_NOT_EXIST"}`)
+}
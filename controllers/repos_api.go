@@ -0,0 +1,93 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	db "github.com/xinsnake/databricks-sdk-golang"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+)
+
+// repoInfo is a repo as returned by the Repos API
+type repoInfo struct {
+	ID           int64  `json:"id,omitempty"`
+	Path         string `json:"path,omitempty"`
+	URL          string `json:"url,omitempty"`
+	Provider     string `json:"provider,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+	HeadCommitID string `json:"head_commit_id,omitempty"`
+}
+
+// reposAPI calls the Repos API, which the DataBricks SDK does not cover yet
+type reposAPI struct {
+	option db.DBClientOption
+}
+
+func newReposAPI(apiClient dbazure.DBClient) reposAPI {
+	return reposAPI{option: apiClient.Option}
+}
+
+// Create clones the git repository into the workspace
+func (a reposAPI) Create(url, provider, path string) (repoInfo, error) {
+	data := struct {
+		URL      string `json:"url,omitempty"`
+		Provider string `json:"provider,omitempty"`
+		Path     string `json:"path,omitempty"`
+	}{url, provider, path}
+	return a.query(http.MethodPost, "/repos", data)
+}
+
+// Get returns the repo with the specified ID
+func (a reposAPI) Get(id int64) (repoInfo, error) {
+	return a.query(http.MethodGet, fmt.Sprintf("/repos/%d", id), struct{}{})
+}
+
+// Update checks out the branch or tag. Checking out a branch pulls its latest commit.
+func (a reposAPI) Update(id int64, branch, tag string) (repoInfo, error) {
+	data := struct {
+		Branch string `json:"branch,omitempty"`
+		Tag    string `json:"tag,omitempty"`
+	}{branch, tag}
+	return a.query(http.MethodPatch, fmt.Sprintf("/repos/%d", id), data)
+}
+
+// Delete removes the repo from the workspace
+func (a reposAPI) Delete(id int64) error {
+	_, err := db.PerformQuery(a.option, http.MethodDelete, fmt.Sprintf("/repos/%d", id), struct{}{}, nil)
+	return err
+}
+
+func (a reposAPI) query(method, path string, data interface{}) (repoInfo, error) {
+	var info repoInfo
+	resp, err := db.PerformQuery(a.option, method, path, data, nil)
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(resp, &info)
+	return info, err
+}
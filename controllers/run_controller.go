@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -46,6 +47,22 @@ import (
 
 const maxConcurrentReconcilesEnvName = "MAX_CONCURRENT_RUN_RECONCILES"
 
+// runQueuePollInterval is how often a queued run checks whether its queue has capacity
+const runQueuePollInterval = 10 * time.Second
+
+const runTTLSecondsAfterFinishedEnvName = "RUN_TTL_SECONDS_AFTER_FINISHED"
+
+const (
+	runMaxResultBytesEnvName = "RUN_MAX_RESULT_BYTES"
+	defaultRunMaxResultBytes = 4096
+)
+
+const (
+	runRetryMaxRetriesEnvName     = "RUN_RETRY_MAX_RETRIES"
+	runRetryBackoffSecondsEnvName = "RUN_RETRY_BACKOFF_SECONDS"
+	runRetryOnEnvName             = "RUN_RETRY_ON"
+)
+
 // RunReconciler reconciles a Run object
 type RunReconciler struct {
 	client.Client
@@ -53,10 +70,17 @@ type RunReconciler struct {
 	Scheme    *runtime.Scheme
 	Recorder  record.EventRecorder
 	APIClient dbazure.DBClient
+	// APIReader reads the runs and queues for admission from the API server rather than the cache,
+	// which can lag behind runs submitted moments ago. The client is used when it is not set.
+	APIReader client.Reader
+
+	admission admissionLocks
 }
 
 // +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runtemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runqueues,verbs=get;list;watch
 
 // Reconcile implements the reconciliation loop for the operator
 func (r *RunReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -95,8 +119,43 @@ func (r *RunReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, nil
 	}
 
+	if !instance.IsSubmitted() && instance.IsCancelRequested() {
+		if instance.Status != nil && instance.Status.Phase == databricksv1alpha1.RunPhaseCanceled && instance.Status.ObservedGeneration == instance.GetGeneration() {
+			return ctrl.Result{}, nil
+		}
+		if instance.Status != nil {
+			instance.Status.QueuePosition = 0
+		}
+		if err := r.updateStatus(instance); err != nil {
+			return ctrl.Result{}, fmt.Errorf("error when canceling run: %v", err)
+		}
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Canceled", "Object is canceled before it was submitted")
+		return ctrl.Result{}, nil
+	}
+
+	if !instance.IsSubmitted() && instance.IsMirrored() {
+		if err := r.mirror(instance); err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Mirroring object", fmt.Sprintf("Failed to mirror object: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when mirroring run: %v", err)
+		}
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Mirrored", fmt.Sprintf("Object mirrors run %d", instance.Spec.MirrorRunID))
+	}
+
 	if !instance.IsSubmitted() {
-		if requeue, err := r.submit(instance); err != nil {
+		unlock := r.admission.lock(instance)
+		admitted, err := r.admit(instance)
+		if err != nil {
+			unlock()
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Queueing object", fmt.Sprintf("Failed to admit object: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when admitting run: %v", err)
+		}
+		if !admitted {
+			unlock()
+			return ctrl.Result{RequeueAfter: runQueuePollInterval}, nil
+		}
+		requeue, err := r.submit(instance)
+		unlock()
+		if err != nil {
 			r.Recorder.Event(instance, corev1.EventTypeWarning, "Submitting object", fmt.Sprintf("Failed to submit object: %s", err))
 			if requeue {
 				return ctrl.Result{RequeueAfter: 30 * time.Second}, fmt.Errorf("error when submitting run: %v", err)
@@ -106,6 +165,7 @@ func (r *RunReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		r.Recorder.Event(instance, corev1.EventTypeNormal, "Submitted", "Object is submitted")
 	}
 
+	wasTerminated := instance.IsTerminated()
 	if instance.IsSubmitted() {
 		if err := r.refresh(instance); err != nil {
 			r.Recorder.Event(instance, corev1.EventTypeWarning, "Refreshing object", fmt.Sprintf("Failed to refresh object: %s", err))
@@ -114,11 +174,123 @@ func (r *RunReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		r.Recorder.Event(instance, corev1.EventTypeNormal, "Refreshed", "Object is refreshed")
 	}
 	if instance.IsTerminated() {
-		return ctrl.Result{}, nil
+		if !wasTerminated && instance.IsCancelRequested() {
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Canceled", "Object is canceled")
+		}
+		exported, err := r.exportOutput(instance)
+		if err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Exporting output", fmt.Sprintf("Failed to export output: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when exporting run output: %v", err)
+		}
+		if exported {
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Exported", "Object output is written to its output target")
+		}
+		if instance.IsRerunRequested() {
+			submitted, err := r.rerun(instance, false)
+			if err != nil {
+				r.Recorder.Event(instance, corev1.EventTypeWarning, "Rerunning object", fmt.Sprintf("Failed to rerun object: %s", err))
+				return ctrl.Result{}, fmt.Errorf("error when rerunning run: %v", err)
+			}
+			if !submitted {
+				return ctrl.Result{RequeueAfter: runQueuePollInterval}, nil
+			}
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Rerun", fmt.Sprintf("Object is submitted for attempt %d", instance.Spec.Attempt))
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		if delay, retry := instance.NextRetry(instance.GetRetryPolicy(getDefaultRetryPolicy()), time.Now()); retry {
+			if delay > 0 {
+				return ctrl.Result{RequeueAfter: delay}, nil
+			}
+			submitted, err := r.rerun(instance, true)
+			if err != nil {
+				r.Recorder.Event(instance, corev1.EventTypeWarning, "Retrying object", fmt.Sprintf("Failed to retry object: %s", err))
+				return ctrl.Result{}, fmt.Errorf("error when retrying run: %v", err)
+			}
+			if !submitted {
+				return ctrl.Result{RequeueAfter: runQueuePollInterval}, nil
+			}
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Retried", fmt.Sprintf("Object is submitted for retry %d", instance.Status.Retries))
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		return r.expire(instance)
+	}
+	if instance.IsCancelRequested() {
+		if err := r.cancel(instance); err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Canceling object", fmt.Sprintf("Failed to cancel object: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when canceling run: %v", err)
+		}
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Canceling", "Object is being canceled")
+		// poll more often than usual until the run reaches its terminal state
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 	return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 }
 
+// admit returns true if the queue of the run has capacity to submit it, and records
+// the position of the run in its queue otherwise. The caller holds the admission lock
+// until the admitted run is submitted.
+func (r *RunReconciler) admit(instance *databricksv1alpha1.Run) (bool, error) {
+	var reader client.Reader = r.Client
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+	position, err := getQueuePosition(reader, instance)
+	if err != nil {
+		return false, err
+	}
+	if position == 0 {
+		return true, nil
+	}
+
+	if instance.Status == nil {
+		instance.Status = &databricksv1alpha1.RunStatus{}
+	}
+	if instance.Status.QueuePosition == position && instance.Status.ObservedGeneration == instance.GetGeneration() {
+		return false, nil
+	}
+	if instance.Status.QueuePosition == 0 {
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Queued", fmt.Sprintf("Object is queued in %s at position %d", instance.GetQueueName(), position))
+	}
+	instance.Status.QueuePosition = position
+	return false, r.updateStatus(instance)
+}
+
+// updateStatus derives the phase and conditions of the run and writes its status, which is a subresource of the run
+func (r *RunReconciler) updateStatus(instance *databricksv1alpha1.Run) error {
+	instance.UpdateStatus(getMaxResultBytes(), time.Now())
+	return r.Status().Update(context.Background(), instance)
+}
+
+// expire deletes a terminated run once its TTL has expired, or requeues for when it expires
+func (r *RunReconciler) expire(instance *databricksv1alpha1.Run) (ctrl.Result, error) {
+	expiry, hasTTL := instance.GetTTLExpiry(getDefaultRunTTLSeconds())
+	if !hasTTL {
+		return ctrl.Result{}, nil
+	}
+	if remaining := time.Until(expiry); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	r.Log.Info(fmt.Sprintf("TTL of run %s expired", instance.GetName()))
+	if !instance.Spec.DeleteRemoteAfterTTL && !instance.IsRemoteKept() {
+		annotations := instance.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[databricksv1alpha1.RunKeepRemoteAnnotation] = "true"
+		instance.SetAnnotations(annotations)
+		if err := r.Update(context.Background(), instance); err != nil {
+			return ctrl.Result{}, fmt.Errorf("error when keeping remote run: %v", err)
+		}
+	}
+	if err := r.Delete(context.Background(), instance); err != nil && !errors.IsNotFound(err) {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Expiring object", fmt.Sprintf("Failed to delete expired object: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when deleting expired run: %v", err)
+	}
+	r.Recorder.Event(instance, corev1.EventTypeNormal, "Expired", "Object is deleted after its TTL expired")
+	return ctrl.Result{}, nil
+}
+
 // SetupWithManager adds the controller manager
 func (r *RunReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -138,3 +310,99 @@ func getMaxConcurrentReconciles() int {
 
 	return concurrentReconciles
 }
+
+// getDefaultRunTTLSeconds returns the TTL of runs that do not set one, or nil if runs are kept
+func getDefaultRunTTLSeconds() *int32 {
+	ttlSeconds, err := strconv.ParseInt(os.Getenv(runTTLSecondsAfterFinishedEnvName), 10, 32)
+	if err != nil || ttlSeconds < 0 {
+		return nil
+	}
+	ttl := int32(ttlSeconds)
+	return &ttl
+}
+
+// getMaxResultBytes returns the size the notebook result and the error of runs are capped at, 0 keeps them whole
+func getMaxResultBytes() int {
+	maxResultBytes, err := strconv.Atoi(os.Getenv(runMaxResultBytesEnvName))
+	if err != nil || maxResultBytes < 0 {
+		return defaultRunMaxResultBytes
+	}
+	return maxResultBytes
+}
+
+// resolveParameters returns a copy of the spec with the parameters that are set from config maps,
+// secrets or fields of the Run. The copy is only submitted, so secret values are not stored in the Run.
+func (r *RunReconciler) resolveParameters(instance *databricksv1alpha1.Run) (*databricksv1alpha1.RunSpec, error) {
+	spec := instance.GetEffectiveSpec().DeepCopy()
+	if !spec.HasParametersFrom() {
+		return spec, nil
+	}
+
+	for _, parameter := range spec.NotebookParamsFrom {
+		value, err := r.resolveParameter(instance, parameter)
+		if err != nil {
+			return nil, fmt.Errorf("error when resolving notebook parameter %s: %v", parameter.Name, err)
+		}
+		if err := spec.SetNotebookParameter(parameter.Name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	positional := []struct {
+		parameters []databricksv1alpha1.RunParameter
+		append     func(values ...string) error
+	}{
+		{spec.JarParamsFrom, spec.AppendJarParameters},
+		{spec.PythonParamsFrom, spec.AppendPythonParameters},
+		{spec.SparkSubmitParamsFrom, spec.AppendSparkSubmitParameters},
+	}
+	for _, p := range positional {
+		var values []string
+		for i, parameter := range p.parameters {
+			value, err := r.resolveParameter(instance, parameter)
+			if err != nil {
+				return nil, fmt.Errorf("error when resolving parameter %d: %v", i, err)
+			}
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if err := p.append(values...); err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
+func (r *RunReconciler) resolveParameter(instance *databricksv1alpha1.Run, parameter databricksv1alpha1.RunParameter) (string, error) {
+	switch {
+	case parameter.ConfigMapKeyRef != nil:
+		data, _, err := getContent(r.Client, instance.GetNamespace(), &databricksv1alpha1.ContentFrom{ConfigMapKeyRef: parameter.ConfigMapKeyRef})
+		return string(data), err
+	case parameter.SecretKeyRef != nil:
+		data, _, err := getContent(r.Client, instance.GetNamespace(), &databricksv1alpha1.ContentFrom{SecretKeyRef: parameter.SecretKeyRef})
+		return string(data), err
+	case parameter.FieldPath != "":
+		return instance.GetFieldValue(parameter.FieldPath)
+	}
+	return instance.ExpandFieldValues(parameter.Value)
+}
+
+// getDefaultRetryPolicy returns the retry policy of runs that do not set one, or nil if they are not retried
+func getDefaultRetryPolicy() *databricksv1alpha1.RetryPolicy {
+	maxRetries, err := strconv.ParseInt(os.Getenv(runRetryMaxRetriesEnvName), 10, 32)
+	if err != nil || maxRetries < 1 {
+		return nil
+	}
+	policy := &databricksv1alpha1.RetryPolicy{MaxRetries: int32(maxRetries)}
+	if backoffSeconds, err := strconv.ParseInt(os.Getenv(runRetryBackoffSecondsEnvName), 10, 32); err == nil && backoffSeconds > 0 {
+		policy.BackoffSeconds = int32(backoffSeconds)
+	}
+	for _, state := range strings.Split(os.Getenv(runRetryOnEnvName), ",") {
+		if state = strings.TrimSpace(state); state != "" {
+			policy.RetryOn = append(policy.RetryOn, strings.ToUpper(state))
+		}
+	}
+	return policy
+}
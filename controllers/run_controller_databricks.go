@@ -26,16 +26,22 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
-	"reflect"
 	"strings"
+	"time"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 	"github.com/xinsnake/databricks-sdk-golang/azure"
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func (r *RunReconciler) submit(instance *databricksv1alpha1.Run) (bool, error) {
@@ -47,10 +53,16 @@ func (r *RunReconciler) submit(instance *databricksv1alpha1.Run) (bool, error) {
 
 	instance.Spec.RunName = instance.GetName()
 
+	if instance.Spec.TemplateRef != nil && (instance.Status == nil || instance.Status.EffectiveSpec == nil) {
+		if err := r.renderTemplate(instance); err != nil {
+			return false, err
+		}
+	}
+
 	// If the run is not linked to a job, submit using RunsSubmit,
 	// otherwise submit it as RunNow under the job, and make the
 	// job the owner of the run
-	if instance.Spec.JobName != "" {
+	if instance.GetEffectiveSpec().JobName != "" {
 		run, requeue, err = r.runUsingRunNow(instance)
 		if requeue {
 			return true, err
@@ -68,14 +80,23 @@ func (r *RunReconciler) submit(instance *databricksv1alpha1.Run) (bool, error) {
 	run.State = &dbmodels.RunState{
 		LifeCycleState: &pendingState,
 	}
-	instance.Status = &azure.JobsRunsGetOutputResponse{
+	instance.SetOutput(azure.JobsRunsGetOutputResponse{
 		Metadata: *run,
-	}
+	})
+	instance.Status.ObservedAttempt = instance.Spec.Attempt
+	instance.Status.QueuePosition = 0
 
+	// the run name and owners are part of the object while the status is written separately,
+	// updating the object returns the status that is stored
+	status := instance.Status
 	err = r.Update(context.Background(), instance)
 	if err != nil {
 		return false, err
 	}
+	instance.Status = status
+	if err := r.updateStatus(instance); err != nil {
+		return false, err
+	}
 
 	runOutput, err := r.getRunOutput(run.RunID)
 
@@ -83,8 +104,24 @@ func (r *RunReconciler) submit(instance *databricksv1alpha1.Run) (bool, error) {
 		return false, err
 	}
 
-	instance.Status = &runOutput
-	return false, r.Update(context.Background(), instance)
+	instance.SetOutput(runOutput)
+	return false, r.updateStatus(instance)
+}
+
+// mirror links the run to the existing DataBricks run in spec.mirror_run_id instead of submitting one.
+// The output is read right away, so the run goes straight to the phase of the DataBricks run.
+func (r *RunReconciler) mirror(instance *databricksv1alpha1.Run) error {
+	r.Log.Info(fmt.Sprintf("Mirroring run %s", instance.GetName()))
+
+	runOutput, err := r.getRunOutput(instance.Spec.MirrorRunID)
+	if err != nil {
+		return err
+	}
+
+	instance.SetOutput(runOutput)
+	instance.Status.ObservedAttempt = instance.Spec.Attempt
+	instance.Status.QueuePosition = 0
+	return r.updateStatus(instance)
 }
 
 func (r *RunReconciler) refresh(instance *databricksv1alpha1.Run) error {
@@ -93,6 +130,7 @@ func (r *RunReconciler) refresh(instance *databricksv1alpha1.Run) error {
 	runID := instance.Status.Metadata.RunID
 
 	runOutput, err := r.getRunOutput(runID)
+	deleted := false
 	if err != nil {
 		if strings.Contains(err.Error(), "Response from server (500)") {
 			// Databricks API 2.0/jobs/runs/get-output returns error 500 if the Run has already been
@@ -101,9 +139,7 @@ func (r *RunReconciler) refresh(instance *databricksv1alpha1.Run) error {
 			if err != nil && strings.Contains(err.Error(), "does not exist") {
 				// So the Run has been deleted from Databricks. Then set k8s Run to a terminal state.
 				r.Log.Info(fmt.Sprintf("Run %s couldn't be found in Databricks", instance.GetName()))
-				runOutput = *instance.Status
-				*runOutput.Metadata.State.LifeCycleState = dbmodels.RunLifeCycleStateInternalError
-				runOutput.Error = "Run couldn't be found in Databricks"
+				deleted = true
 			} else {
 				return err
 			}
@@ -120,19 +156,76 @@ func (r *RunReconciler) refresh(instance *databricksv1alpha1.Run) error {
 		return err
 	}
 
-	if reflect.DeepEqual(instance.Status, &runOutput) {
+	status := instance.Status.DeepCopy()
+	if deleted {
+		// the last output is kept, along with whether its result was capped
+		internalError := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateInternalError)
+		if instance.Status.Metadata.State == nil {
+			instance.Status.Metadata.State = &dbmodels.RunState{}
+		}
+		instance.Status.Metadata.State.LifeCycleState = &internalError
+		instance.Status.Error = "Run couldn't be found in Databricks"
+	} else {
+		instance.SetOutput(runOutput)
+	}
+	// the retry is recorded along with the terminal state, so that owners do not see the run as finished
+	instance.SetNextRetryTime(instance.GetRetryPolicy(getDefaultRetryPolicy()))
+	instance.UpdateStatus(getMaxResultBytes(), time.Now())
+	if equality.Semantic.DeepEqual(status, instance.Status) {
 		return nil
 	}
+	return r.Status().Update(context.Background(), instance)
+}
 
-	instance.Status = &runOutput
-	return r.Update(context.Background(), instance)
+// rerun records the terminated attempt and submits the run again once its queue admits it.
+// Retries count towards the retry policy, while a rerun requested through spec.attempt starts over.
+func (r *RunReconciler) rerun(instance *databricksv1alpha1.Run, retry bool) (bool, error) {
+	unlock := r.admission.lock(instance)
+	defer unlock()
+	if admitted, err := r.admit(instance); err != nil || !admitted {
+		return false, err
+	}
+	r.Log.Info(fmt.Sprintf("Rerunning run %s", instance.GetName()))
+
+	metadata := instance.Status.Metadata
+	attempt := databricksv1alpha1.RunAttempt{
+		RunID:     metadata.RunID,
+		Error:     instance.Status.Error,
+		StartTime: metadata.StartTime,
+		Retry:     retry,
+	}
+	if metadata.State != nil {
+		attempt.LifeCycleState = metadata.State.LifeCycleState
+		attempt.ResultState = metadata.State.ResultState
+	}
+	instance.Status.Attempts = append(instance.Status.Attempts, attempt)
+	if retry {
+		instance.Status.Retries++
+	} else {
+		instance.Status.Retries = 0
+	}
+	instance.SetOutput(azure.JobsRunsGetOutputResponse{})
+	instance.Status.NextRetryTime = nil
+
+	_, err := r.submit(instance)
+	return err == nil, err
+}
+
+// cancel asks DataBricks to cancel the run, which terminates it asynchronously
+func (r *RunReconciler) cancel(instance *databricksv1alpha1.Run) error {
+	r.Log.Info(fmt.Sprintf("Canceling run %s", instance.GetName()))
+
+	execution := NewExecution("runs", "cancel")
+	err := r.APIClient.Jobs().RunsCancel(instance.Status.Metadata.RunID)
+	execution.Finish(err)
+	return err
 }
 
 // delete attempts to cancel and delete a run. Returns bool indicating if complete (safe to retry if not and no error) and an error
 func (r *RunReconciler) delete(instance *databricksv1alpha1.Run) (bool, error) {
 	r.Log.Info(fmt.Sprintf("Deleting run %s", instance.GetName()))
 
-	if instance.Status == nil {
+	if instance.Status == nil || instance.IsRemoteKept() {
 		return true, nil
 	}
 
@@ -168,27 +261,27 @@ func (r *RunReconciler) delete(instance *databricksv1alpha1.Run) (bool, error) {
 
 func (r *RunReconciler) runUsingRunNow(instance *databricksv1alpha1.Run) (*dbmodels.Run, bool, error) {
 
-	runParameters := dbmodels.RunParameters{
-		JarParams:         instance.Spec.JarParams,
-		NotebookParams:    instance.Spec.NotebookParams,
-		PythonParams:      instance.Spec.PythonParams,
-		SparkSubmitParams: instance.Spec.SparkSubmitParams,
+	spec, err := r.resolveParameters(instance)
+	if err != nil {
+		return nil, false, err
+	}
+	var runParameters dbmodels.RunParameters
+	if spec.RunParameters != nil {
+		runParameters = *spec.RunParameters
 	}
 
 	// Here we set the owner attribute
-	k8sJobNamespacedName := types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.Spec.JobName}
+	k8sJobNamespacedName := types.NamespacedName{Namespace: instance.GetNamespace(), Name: spec.JobName}
 	var k8sJob databricksv1alpha1.Djob
 	if err := r.Client.Get(context.Background(), k8sJobNamespacedName, &k8sJob); err != nil {
 		return nil, false, err
 	}
 
-	instance.ObjectMeta.SetOwnerReferences([]metav1.OwnerReference{
-		{
-			APIVersion: "v1alpha1", // TODO should this be a referenced value?
-			Kind:       "Djob",     // TODO should this be a referenced value?
-			Name:       k8sJob.GetName(),
-			UID:        k8sJob.GetUID(),
-		},
+	addOwnerReference(instance, metav1.OwnerReference{
+		APIVersion: "v1alpha1", // TODO should this be a referenced value?
+		Kind:       "Djob",     // TODO should this be a referenced value?
+		Name:       k8sJob.GetName(),
+		UID:        k8sJob.GetUID(),
 	})
 
 	if !k8sJob.IsSubmitted() {
@@ -202,61 +295,62 @@ func (r *RunReconciler) runUsingRunNow(instance *databricksv1alpha1.Run) (*dbmod
 }
 
 func (r *RunReconciler) runUsingRunsSubmit(instance *databricksv1alpha1.Run) (*dbmodels.Run, error) {
+	spec, err := r.resolveParameters(instance)
+	if err != nil {
+		return nil, err
+	}
 
 	//Check if dbricks run is set to run on exisiting dbricks cluster
 	//Get exisiting dbricks cluster by cluster name and set ExistingClusterID or
 	//Get exisiting dbricks cluster by cluster id
 	var ownerInstance databricksv1alpha1.Dcluster
-	if len(instance.Spec.ClusterSpec.ExistingClusterName) > 0 {
-		dClusterNamespacedName := types.NamespacedName{Name: instance.Spec.ClusterSpec.ExistingClusterName, Namespace: instance.Namespace}
+	if len(spec.ClusterSpec.ExistingClusterName) > 0 {
+		dClusterNamespacedName := types.NamespacedName{Name: spec.ClusterSpec.ExistingClusterName, Namespace: instance.Namespace}
 		err := r.Get(context.Background(), dClusterNamespacedName, &ownerInstance)
 		if err != nil {
 			return nil, err
 		}
 		if (ownerInstance.Status != nil) && (ownerInstance.Status.ClusterInfo != nil) && len(ownerInstance.Status.ClusterInfo.ClusterID) > 0 {
-			instance.Spec.ClusterSpec.ExistingClusterID = ownerInstance.Status.ClusterInfo.ClusterID
+			spec.ClusterSpec.ExistingClusterID = ownerInstance.Status.ClusterInfo.ClusterID
 		} else {
-			return nil, fmt.Errorf("failed to get ClusterID of %v", instance.Spec.ExistingClusterName)
+			return nil, fmt.Errorf("failed to get ClusterID of %v", spec.ExistingClusterName)
 		}
-	} else if len(instance.Spec.ClusterSpec.ExistingClusterID) > 0 {
+	} else if len(spec.ClusterSpec.ExistingClusterID) > 0 {
 		var dclusters databricksv1alpha1.DclusterList
-		err := r.List(context.Background(), &dclusters, client.InNamespace(instance.Namespace), client.MatchingFields{dclusterIndexKey: instance.Spec.ClusterSpec.ExistingClusterID})
+		err := r.List(context.Background(), &dclusters, client.InNamespace(instance.Namespace), client.MatchingFields{dclusterIndexKey: spec.ClusterSpec.ExistingClusterID})
 		if err != nil {
 			return nil, err
 		}
 		if len(dclusters.Items) == 1 {
 			ownerInstance = dclusters.Items[0]
 		} else {
-			return nil, fmt.Errorf("failed to get ClusterID of %v", instance.Spec.ExistingClusterID)
+			return nil, fmt.Errorf("failed to get ClusterID of %v", spec.ExistingClusterID)
 		}
 	}
 	//Set Exisiting cluster as Owner of Run
 	if &ownerInstance != nil && len(ownerInstance.APIVersion) > 0 && len(ownerInstance.Kind) > 0 && len(ownerInstance.GetName()) > 0 {
-		references := []metav1.OwnerReference{
-			{
-				APIVersion: ownerInstance.APIVersion,
-				Kind:       ownerInstance.Kind,
-				Name:       ownerInstance.GetName(),
-				UID:        ownerInstance.GetUID(),
-			},
-		}
-		instance.ObjectMeta.SetOwnerReferences(references)
+		addOwnerReference(instance, metav1.OwnerReference{
+			APIVersion: ownerInstance.APIVersion,
+			Kind:       ownerInstance.Kind,
+			Name:       ownerInstance.GetName(),
+			UID:        ownerInstance.GetUID(),
+		})
 	}
 
 	clusterSpec := dbmodels.ClusterSpec{
-		NewCluster:        instance.Spec.NewCluster,
-		ExistingClusterID: instance.Spec.ExistingClusterID,
-		Libraries:         instance.Spec.Libraries,
+		NewCluster:        spec.NewCluster,
+		ExistingClusterID: spec.ExistingClusterID,
+		Libraries:         spec.Libraries,
 	}
 	jobTask := dbmodels.JobTask{
-		NotebookTask:    instance.Spec.NotebookTask,
-		SparkJarTask:    instance.Spec.SparkJarTask,
-		SparkPythonTask: instance.Spec.SparkPythonTask,
-		SparkSubmitTask: instance.Spec.SparkSubmitTask,
+		NotebookTask:    spec.NotebookTask,
+		SparkJarTask:    spec.SparkJarTask,
+		SparkPythonTask: spec.SparkPythonTask,
+		SparkSubmitTask: spec.SparkSubmitTask,
 	}
 
 	execution := NewExecution("runs", "run_submit")
-	run, err := r.APIClient.Jobs().RunsSubmit(instance.Spec.RunName, clusterSpec, jobTask, instance.Spec.TimeoutSeconds)
+	run, err := r.APIClient.Jobs().RunsSubmit(instance.Spec.RunName, clusterSpec, jobTask, spec.TimeoutSeconds)
 	execution.Finish(err)
 	return &run, err
 }
@@ -269,6 +363,71 @@ func (r *RunReconciler) getRun(runID int64) (dbmodels.Run, error) {
 	return runOutput, err
 }
 
+// exportOutput writes the output of the terminated run to its output target, once per attempt.
+// The output is read again through Jobs API 2.1, which returns the whole result and the error trace.
+func (r *RunReconciler) exportOutput(instance *databricksv1alpha1.Run) (bool, error) {
+	target := instance.GetOutputTarget()
+	if target == nil || !instance.IsTerminated() || instance.IsOutputExported() {
+		return false, nil
+	}
+	r.Log.Info(fmt.Sprintf("Exporting output of run %s", instance.GetName()))
+
+	runID := instance.Status.Metadata.RunID
+	execution := NewExecution("runs", "run_get_output")
+	output, err := newJobsAPI(r.APIClient).RunsGetOutput(runID)
+	execution.Finish(err)
+	if err != nil {
+		return false, err
+	}
+
+	data := map[string]string{
+		databricksv1alpha1.RunOutputResultKey:      output.NotebookOutput.Result,
+		databricksv1alpha1.RunOutputResultStateKey: instance.GetTerminalState(),
+		databricksv1alpha1.RunOutputErrorKey:       output.Error,
+		databricksv1alpha1.RunOutputErrorTraceKey:  output.ErrorTrace,
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+	sha := fmt.Sprintf("%x", sha256.Sum256(payload))
+
+	var object runtime.Object
+	meta := metav1.ObjectMeta{Namespace: instance.GetNamespace()}
+	if target.ConfigMapName != "" {
+		meta.Name = target.ConfigMapName
+		object = &corev1.ConfigMap{ObjectMeta: meta}
+	} else {
+		meta.Name = target.SecretName
+		object = &corev1.Secret{ObjectMeta: meta}
+	}
+	_, err = controllerutil.CreateOrUpdate(context.Background(), r.Client, object, func() error {
+		accessor := object.(metav1.Object)
+		annotations := accessor.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[databricksv1alpha1.RunOutputSHA256Annotation] = sha
+		accessor.SetAnnotations(annotations)
+		switch o := object.(type) {
+		case *corev1.ConfigMap:
+			o.Data = data
+		case *corev1.Secret:
+			o.Data = map[string][]byte{}
+			for k, v := range data {
+				o.Data[k] = []byte(v)
+			}
+		}
+		return controllerutil.SetControllerReference(instance, accessor, r.Scheme)
+	})
+	if err != nil {
+		return false, fmt.Errorf("error when writing output to %s: %v", meta.Name, err)
+	}
+
+	instance.Status.ExportedOutput = &databricksv1alpha1.RunOutputStatus{RunID: runID, SHA256: sha}
+	return true, r.updateStatus(instance)
+}
+
 func (r *RunReconciler) getRunOutput(runID int64) (azure.JobsRunsGetOutputResponse, error) {
 	execution := NewExecution("runs", "run_get_output")
 	runOutput, err := r.APIClient.Jobs().RunsGetOutput(runID)
@@ -276,3 +435,39 @@ func (r *RunReconciler) getRunOutput(runID int64) (azure.JobsRunsGetOutputRespon
 
 	return runOutput, err
 }
+
+// addOwnerReference adds the owner unless it is already set, keeping other
+// owners such as the RunWorkflow that created the run
+func addOwnerReference(object metav1.Object, reference metav1.OwnerReference) {
+	references := object.GetOwnerReferences()
+	for _, existing := range references {
+		if existing.UID == reference.UID && existing.Name == reference.Name {
+			return
+		}
+	}
+	object.SetOwnerReferences(append(references, reference))
+}
+
+// renderTemplate renders the effective spec of the run from its RunTemplate. The effective spec is kept
+// in the status, so that changes to the template only affect runs that are not submitted yet.
+func (r *RunReconciler) renderTemplate(instance *databricksv1alpha1.Run) error {
+	var template databricksv1alpha1.RunTemplate
+	templateNamespacedName := types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.Spec.TemplateRef.Name}
+	if err := r.Get(context.Background(), templateNamespacedName, &template); err != nil {
+		return fmt.Errorf("error when getting run template %s: %v", instance.Spec.TemplateRef.Name, err)
+	}
+	if template.Spec == nil {
+		return fmt.Errorf("run template %s has no spec", template.GetName())
+	}
+
+	effectiveSpec, err := template.Render(instance.Spec)
+	if err != nil {
+		return fmt.Errorf("error when rendering run template %s: %v", template.GetName(), err)
+	}
+	if instance.Status == nil {
+		instance.Status = &databricksv1alpha1.RunStatus{}
+	}
+	instance.Status.EffectiveSpec = effectiveSpec
+	instance.Status.TemplateGeneration = template.GetGeneration()
+	return nil
+}
@@ -26,14 +26,20 @@ package controllers
 
 import (
 	"context"
+	"os"
+	"strings"
 	"time"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 var _ = Describe("Run Controller", func() {
@@ -49,6 +55,558 @@ var _ = Describe("Run Controller", func() {
 		// Add any teardown steps that needs to be executed after each test
 	})
 
+	Context("Run with TTL after finished", func() {
+		var (
+			reconciler *RunReconciler
+			instance   *databricksv1alpha1.Run
+			key        types.NamespacedName
+		)
+
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "t-run-ttl", Namespace: "default"}
+			lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+			resultState := dbmodels.RunResultState(dbmodels.RunResultStateSuccess)
+			instance = &databricksv1alpha1.Run{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec:       &databricksv1alpha1.RunSpec{},
+				Status: &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+					Metadata: dbmodels.Run{
+						JobID:     1,
+						RunID:     2,
+						StartTime: time.Now().Add(-time.Hour).UnixNano() / int64(time.Millisecond),
+						State:     &dbmodels.RunState{LifeCycleState: &lifeCycleState, ResultState: &resultState},
+					},
+				}},
+			}
+		})
+
+		setup := func() {
+			reconciler = &RunReconciler{
+				Client:   newFakeClient(instance),
+				Log:      ctrl.Log.WithName("controllers").WithName("Run"),
+				Recorder: record.NewFakeRecorder(100),
+			}
+		}
+
+		It("Should keep runs without a TTL", func() {
+			setup()
+			result, err := reconciler.expire(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(reconciler.Get(context.Background(), key, &databricksv1alpha1.Run{})).To(Succeed())
+		})
+
+		It("Should requeue at the expiry time", func() {
+			ttl := int32(7200)
+			instance.Spec.TTLSecondsAfterFinished = &ttl
+			setup()
+			result, err := reconciler.expire(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			Expect(reconciler.Get(context.Background(), key, &databricksv1alpha1.Run{})).To(Succeed())
+		})
+
+		It("Should delete the run and keep the remote run once expired", func() {
+			ttl := int32(60)
+			instance.Spec.TTLSecondsAfterFinished = &ttl
+			setup()
+			_, err := reconciler.expire(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instance.IsRemoteKept()).To(BeTrue())
+			Expect(reconciler.Get(context.Background(), key, &databricksv1alpha1.Run{})).ToNot(Succeed())
+
+			By("Not deleting the remote run")
+			completed, err := reconciler.delete(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(completed).To(BeTrue())
+		})
+
+		It("Should delete the remote run when requested", func() {
+			ttl := int32(60)
+			instance.Spec.TTLSecondsAfterFinished = &ttl
+			instance.Spec.DeleteRemoteAfterTTL = true
+			setup()
+			_, err := reconciler.expire(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instance.IsRemoteKept()).To(BeFalse())
+			Expect(reconciler.Get(context.Background(), key, &databricksv1alpha1.Run{})).ToNot(Succeed())
+		})
+	})
+
+	Context("Canceled run", func() {
+		var (
+			reconciler *RunReconciler
+			instance   *databricksv1alpha1.Run
+			databricks *fakeDatabricks
+			key        types.NamespacedName
+		)
+
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "t-run-cancel", Namespace: "default"}
+			databricks = newFakeDatabricks()
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateRunning
+			instance = &databricksv1alpha1.Run{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Finalizers: []string{databricksv1alpha1.RunFinalizerName},
+				},
+				Spec: &databricksv1alpha1.RunSpec{Cancel: true},
+			}
+		})
+
+		AfterEach(func() {
+			databricks.Close()
+		})
+
+		setup := func() {
+			reconciler = &RunReconciler{
+				Client:    newFakeClient(instance),
+				Log:       ctrl.Log.WithName("controllers").WithName("Run"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+		}
+
+		It("Should cancel the remote run and keep the object", func() {
+			lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateRunning)
+			instance.Status = &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				Metadata: dbmodels.Run{JobID: 1, RunID: 2, State: &dbmodels.RunState{LifeCycleState: &lifeCycleState}},
+			}}
+			setup()
+
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/cancel"]).To(Equal(1))
+			Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+
+			By("Waiting for the terminal state")
+			canceled := dbmodels.RunResultState(dbmodels.RunResultStateCanceled)
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateTerminated
+			databricks.resultState = &canceled
+			result, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/cancel"]).To(Equal(1))
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.IsTerminated()).To(BeTrue())
+			Expect(*fetched.Status.Metadata.State.ResultState).To(Equal(canceled))
+			Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunPhaseCanceled))
+			Expect(databricksv1alpha1.GetCondition(fetched.Status.Conditions, databricksv1alpha1.ConditionTypeCanceled).Status).To(Equal(corev1.ConditionTrue))
+		})
+
+		It("Should not submit a run that is canceled", func() {
+			setup()
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.IsSubmitted()).To(BeFalse())
+			Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunPhaseCanceled))
+			Expect(fetched.Status.EndTime).ToNot(BeNil())
+		})
+	})
+
+	Context("Mirrored run", func() {
+		It("Should follow the existing run without submitting, canceling or rerunning it", func() {
+			failed := dbmodels.RunResultState(dbmodels.RunResultStateFailed)
+			databricks := newFakeDatabricks()
+			defer databricks.Close()
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateTerminated
+			databricks.resultState = &failed
+
+			key := types.NamespacedName{Name: "t-job-2", Namespace: "default"}
+			instance := &databricksv1alpha1.Run{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Finalizers: []string{databricksv1alpha1.RunFinalizerName},
+				},
+				Spec: &databricksv1alpha1.RunSpec{JobName: "t-job", MirrorRunID: 2, Cancel: true},
+			}
+			reconciler := &RunReconciler{
+				Client:    newFakeClient(instance),
+				Log:       ctrl.Log.WithName("controllers").WithName("Run"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(0))
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/cancel"]).To(Equal(0))
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.IsSubmitted()).To(BeTrue())
+			Expect(fetched.Status.Metadata.RunID).To(Equal(int64(2)))
+			Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunPhaseFailed))
+			Expect(fetched.IsRemoteKept()).To(BeTrue())
+
+			By("Ignoring a bumped attempt")
+			fetched.Spec.Attempt = 1
+			Expect(reconciler.Update(context.Background(), fetched)).To(Succeed())
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(0))
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.Attempts).To(BeEmpty())
+		})
+	})
+
+	Context("Rerun and retried run", func() {
+		var (
+			reconciler *RunReconciler
+			instance   *databricksv1alpha1.Run
+			databricks *fakeDatabricks
+			key        types.NamespacedName
+		)
+
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "t-run-retry", Namespace: "default"}
+			databricks = newFakeDatabricks()
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateTerminated
+			instance = &databricksv1alpha1.Run{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Finalizers: []string{databricksv1alpha1.RunFinalizerName},
+				},
+				Spec: &databricksv1alpha1.RunSpec{
+					JobTask: &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/test"}},
+				},
+				Status: &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+					Metadata: dbmodels.Run{JobID: 1, RunID: 2},
+				}},
+			}
+		})
+
+		AfterEach(func() {
+			databricks.Close()
+		})
+
+		setup := func() {
+			reconciler = &RunReconciler{
+				Client:    newFakeClient(instance),
+				Log:       ctrl.Log.WithName("controllers").WithName("Run"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+		}
+
+		It("Should resubmit the run when the attempt is bumped", func() {
+			success := dbmodels.RunResultState(dbmodels.RunResultStateSuccess)
+			databricks.resultState = &success
+			instance.Spec.Attempt = 1
+			setup()
+
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(1))
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.ObservedAttempt).To(Equal(int32(1)))
+			Expect(fetched.Status.Metadata.RunID).To(Equal(int64(3)))
+			Expect(fetched.Status.Attempts).To(HaveLen(1))
+			Expect(fetched.Status.Attempts[0].RunID).To(Equal(int64(2)))
+			Expect(fetched.Status.Attempts[0].Retry).To(BeFalse())
+
+			By("Not resubmitting it again")
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(1))
+		})
+
+		It("Should retry the run until the retries are exhausted", func() {
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateInternalError
+			instance.Spec.RetryPolicy = &databricksv1alpha1.RetryPolicy{MaxRetries: 2}
+			setup()
+
+			for i := 1; i <= 3; i++ {
+				_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(2))
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.Retries).To(Equal(int32(2)))
+			Expect(fetched.Status.Attempts).To(HaveLen(2))
+			Expect(fetched.Status.Attempts[1].Retry).To(BeTrue())
+			Expect(*fetched.Status.Attempts[1].LifeCycleState).To(Equal(dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateInternalError)))
+			Expect(fetched.Status.NextRetryTime).To(BeNil())
+			Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunPhaseFailed))
+		})
+
+		It("Should report the retrying phase while the retry waits out its backoff", func() {
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateInternalError
+			instance.Spec.RetryPolicy = &databricksv1alpha1.RetryPolicy{MaxRetries: 1, BackoffSeconds: 3600}
+			instance.CreationTimestamp = metav1.Now()
+			setup()
+
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", time.Minute))
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(0))
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunPhaseRetrying))
+			Expect(fetched.Status.NextRetryTime).ToNot(BeNil())
+			Expect(fetched.IsFinal()).To(BeFalse())
+		})
+
+		It("Should not retry a run that succeeded", func() {
+			success := dbmodels.RunResultState(dbmodels.RunResultStateSuccess)
+			databricks.resultState = &success
+			instance.Spec.RetryPolicy = &databricksv1alpha1.RetryPolicy{MaxRetries: 2, RetryOn: []string{"FAILED"}}
+			setup()
+
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(BeZero())
+		})
+
+		It("Should report the phase and conditions and cap the result", func() {
+			success := dbmodels.RunResultState(dbmodels.RunResultStateSuccess)
+			databricks.resultState = &success
+			databricks.result = strings.Repeat("a", 20)
+			Expect(os.Setenv(runMaxResultBytesEnvName, "8")).To(Succeed())
+			defer os.Unsetenv(runMaxResultBytesEnvName)
+			setup()
+
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunPhaseSucceeded))
+			Expect(fetched.Status.NotebookOutput.Result).To(Equal("aaaaaaaa"))
+			Expect(fetched.Status.ResultTruncated).To(BeTrue())
+			Expect(fetched.Status.EndTime).ToNot(BeNil())
+			Expect(databricksv1alpha1.GetCondition(fetched.Status.Conditions, databricksv1alpha1.ConditionTypeSucceeded).Status).To(Equal(corev1.ConditionTrue))
+			Expect(databricksv1alpha1.GetCondition(fetched.Status.Conditions, databricksv1alpha1.ConditionTypeReconciling).Status).To(Equal(corev1.ConditionFalse))
+		})
+	})
+
+	Context("Run from a template", func() {
+		var (
+			reconciler *RunReconciler
+			instance   *databricksv1alpha1.Run
+			template   *databricksv1alpha1.RunTemplate
+			databricks *fakeDatabricks
+			key        types.NamespacedName
+		)
+
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "t-run-template", Namespace: "default"}
+			databricks = newFakeDatabricks()
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateRunning
+			template = &databricksv1alpha1.RunTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default", Generation: 1},
+				Spec: &databricksv1alpha1.RunTemplateSpec{
+					Parameters: []databricksv1alpha1.RunTemplateParameter{{Name: "env"}},
+					Template: databricksv1alpha1.RunSpec{
+						JobTask: &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/reports/$(params.env)"}},
+					},
+				},
+			}
+			instance = &databricksv1alpha1.Run{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Finalizers: []string{databricksv1alpha1.RunFinalizerName},
+				},
+				Spec: &databricksv1alpha1.RunSpec{
+					TemplateRef:    &databricksv1alpha1.RunTemplateRef{Name: "report"},
+					TemplateParams: map[string]string{"env": "prod"},
+				},
+			}
+			reconciler = &RunReconciler{
+				Client:    newFakeClient(instance, template),
+				Log:       ctrl.Log.WithName("controllers").WithName("Run"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+		})
+
+		AfterEach(func() {
+			databricks.Close()
+		})
+
+		It("Should submit the effective spec and keep it for reruns", func() {
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(1))
+			Expect(databricks.notebookTask.NotebookPath).To(Equal("/reports/prod"))
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.TemplateGeneration).To(Equal(int64(1)))
+			Expect(fetched.Status.EffectiveSpec.NotebookTask.NotebookPath).To(Equal("/reports/prod"))
+			Expect(fetched.Spec.JobTask).To(BeNil())
+
+			By("Not applying template changes to the rerun")
+			template.Spec.Template.NotebookTask.NotebookPath = "/reports/v2/$(params.env)"
+			Expect(reconciler.Update(context.Background(), template)).To(Succeed())
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateTerminated
+			fetched.Spec.Attempt = 1
+			Expect(reconciler.Update(context.Background(), fetched)).To(Succeed())
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(2))
+			Expect(databricks.notebookTask.NotebookPath).To(Equal("/reports/prod"))
+		})
+
+		It("Should not submit the run when the template is missing", func() {
+			instance.Spec.TemplateRef.Name = "missing"
+			Expect(reconciler.Update(context.Background(), instance)).To(Succeed())
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(BeZero())
+		})
+	})
+
+	Context("Run with an output target", func() {
+		var (
+			reconciler *RunReconciler
+			instance   *databricksv1alpha1.Run
+			databricks *fakeDatabricks
+			key        types.NamespacedName
+		)
+
+		BeforeEach(func() {
+			key = types.NamespacedName{Name: "t-run-output", Namespace: "default"}
+			failed := dbmodels.RunResultState(dbmodels.RunResultStateFailed)
+			databricks = newFakeDatabricks()
+			databricks.lifeCycleState = dbmodels.RunLifeCycleStateTerminated
+			databricks.resultState = &failed
+			databricks.result = "done"
+			instance = &databricksv1alpha1.Run{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Finalizers: []string{databricksv1alpha1.RunFinalizerName},
+				},
+				Spec: &databricksv1alpha1.RunSpec{
+					OutputTo: &databricksv1alpha1.RunOutputTarget{ConfigMapName: "t-run-output-result"},
+				},
+				Status: &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+					Metadata: dbmodels.Run{JobID: 1, RunID: 2},
+				}},
+			}
+		})
+
+		AfterEach(func() {
+			databricks.Close()
+		})
+
+		setup := func() {
+			reconciler = &RunReconciler{
+				Client:    newFakeClient(instance),
+				Log:       ctrl.Log.WithName("controllers").WithName("Run"),
+				Scheme:    fakeScheme,
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+		}
+
+		It("Should write the output to a config map once", func() {
+			setup()
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["GET /api/2.1/jobs/runs/get-output"]).To(Equal(1))
+
+			configMap := &corev1.ConfigMap{}
+			Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "t-run-output-result", Namespace: "default"}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(Equal(map[string]string{
+				databricksv1alpha1.RunOutputResultKey:      "done",
+				databricksv1alpha1.RunOutputResultStateKey: "FAILED",
+				databricksv1alpha1.RunOutputErrorKey:       "notebook failed",
+				databricksv1alpha1.RunOutputErrorTraceKey:  "Traceback",
+			}))
+			Expect(configMap.GetOwnerReferences()).To(HaveLen(1))
+			Expect(configMap.GetOwnerReferences()[0].Name).To(Equal(key.Name))
+
+			fetched := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.ExportedOutput.RunID).To(Equal(int64(2)))
+			Expect(fetched.Status.ExportedOutput.SHA256).To(Equal(configMap.GetAnnotations()[databricksv1alpha1.RunOutputSHA256Annotation]))
+
+			By("Not writing it again")
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["GET /api/2.1/jobs/runs/get-output"]).To(Equal(1))
+		})
+
+		It("Should write the output to a secret", func() {
+			instance.Spec.OutputTo = &databricksv1alpha1.RunOutputTarget{SecretName: "t-run-output-result"}
+			setup()
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "t-run-output-result", Namespace: "default"}, secret)).To(Succeed())
+			Expect(string(secret.Data[databricksv1alpha1.RunOutputResultKey])).To(Equal("done"))
+		})
+	})
+
+	Context("Run with parameters from references", func() {
+		var (
+			reconciler *RunReconciler
+			instance   *databricksv1alpha1.Run
+		)
+
+		BeforeEach(func() {
+			instance = &databricksv1alpha1.Run{
+				ObjectMeta: metav1.ObjectMeta{Name: "t-run-params", Namespace: "default"},
+				Spec: &databricksv1alpha1.RunSpec{
+					JobTask: &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{
+						NotebookPath:   "/test",
+						BaseParameters: map[string]string{"literal": "value"},
+					}},
+					NotebookParamsFrom: []databricksv1alpha1.RunParameter{
+						{Name: "env", ConfigMapKeyRef: &databricksv1alpha1.ContentKeyRef{Name: "settings", Key: "env"}},
+						{Name: "password", SecretKeyRef: &databricksv1alpha1.ContentKeyRef{Name: "credentials", Key: "password"}},
+						{Name: "namespace", FieldPath: "metadata.namespace"},
+						{Name: "output", Value: "/output/$(metadata.name)"},
+					},
+				},
+			}
+			reconciler = &RunReconciler{
+				Client: newFakeClient(
+					&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"}, Data: map[string]string{"env": "staging"}},
+					&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"}, Data: map[string][]byte{"password": []byte("secret")}},
+				),
+				Log: ctrl.Log.WithName("controllers").WithName("Run"),
+			}
+		})
+
+		It("Should resolve the parameters without changing the run", func() {
+			spec, err := reconciler.resolveParameters(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec.NotebookTask.BaseParameters).To(Equal(map[string]string{
+				"literal":   "value",
+				"env":       "staging",
+				"password":  "secret",
+				"namespace": "default",
+				"output":    "/output/t-run-params",
+			}))
+			Expect(instance.Spec.NotebookTask.BaseParameters).To(HaveLen(1))
+		})
+
+		It("Should fail when a reference is missing", func() {
+			instance.Spec.NotebookParamsFrom[0].ConfigMapKeyRef.Key = "missing"
+			_, err := reconciler.resolveParameters(instance)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Context("Run existing job", func() {
 		It("Should create successfully", func() {
 			By("Create job for run")
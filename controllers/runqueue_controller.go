@@ -0,0 +1,291 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+)
+
+const runMaxActiveRunsEnvName = "RUN_MAX_ACTIVE_RUNS"
+
+// RunQueueReconciler reconciles a RunQueue object
+type RunQueueReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runqueues,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runqueues/status,verbs=get;update;patch
+
+// Reconcile implements the reconciliation loop for the operator
+func (r *RunQueueReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = context.Background()
+	_ = r.Log.WithValues("runqueue", req.NamespacedName)
+
+	instance := &databricksv1alpha1.RunQueue{}
+	if err := r.Get(context.Background(), req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var runs databricksv1alpha1.RunList
+	if err := r.List(context.Background(), &runs, client.InNamespace(instance.GetNamespace())); err != nil {
+		return ctrl.Result{}, fmt.Errorf("error when listing runs of queue: %v", err)
+	}
+
+	status := &databricksv1alpha1.RunQueueStatus{}
+	for _, run := range runs.Items {
+		if run.GetQueueName() != instance.GetName() {
+			continue
+		}
+		if run.IsActive() {
+			status.Active++
+		} else if run.IsQueued() {
+			status.Queued++
+		}
+	}
+
+	if reflect.DeepEqual(instance.Status, status) {
+		return ctrl.Result{}, nil
+	}
+	instance.Status = status
+	return ctrl.Result{}, r.Update(context.Background(), instance)
+}
+
+// SetupWithManager adds the controller manager
+func (r *RunQueueReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databricksv1alpha1.RunQueue{}).
+		Watches(&source.Kind{Type: &databricksv1alpha1.Run{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(mapRunToQueue),
+		}).
+		Complete(r)
+}
+
+// mapRunToQueue returns the RunQueue of the Run in the event
+func mapRunToQueue(object handler.MapObject) []reconcile.Request {
+	run, ok := object.Object.(*databricksv1alpha1.Run)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: run.GetNamespace(), Name: run.GetQueueName()}},
+	}
+}
+
+// runQueueGroup holds the runs of a namespace that are admitted through the same queue
+type runQueueGroup struct {
+	queue   *databricksv1alpha1.RunQueue
+	active  int32
+	waiting []*databricksv1alpha1.Run
+}
+
+// admitted returns the waiting runs that the capacity of the queue admits, which is all of them without a queue
+func (g *runQueueGroup) admitted() []*databricksv1alpha1.Run {
+	if g.queue == nil {
+		return g.waiting
+	}
+	capacity := g.queue.Spec.MaxActiveRuns - g.active
+	if capacity <= 0 {
+		return nil
+	}
+	if capacity > int32(len(g.waiting)) {
+		capacity = int32(len(g.waiting))
+	}
+	return g.waiting[:capacity]
+}
+
+// admissionLocks serializes the admission of runs that compete for the same capacity, so that a run
+// is submitted before the next one counts the active runs
+type admissionLocks struct {
+	sync.Mutex
+	locks map[types.NamespacedName]*sync.Mutex
+}
+
+// lock locks the capacity the run competes for, which is its RunQueue or, when RUN_MAX_ACTIVE_RUNS
+// is set, the workspace, and returns the function that unlocks it
+func (l *admissionLocks) lock(instance *databricksv1alpha1.Run) func() {
+	key := types.NamespacedName{}
+	if getMaxActiveRuns() == nil {
+		key = types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetQueueName()}
+	}
+
+	l.Lock()
+	if l.locks == nil {
+		l.locks = map[types.NamespacedName]*sync.Mutex{}
+	}
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[key] = lock
+	}
+	l.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// getQueuePosition returns 0 if the run can be submitted, or its position among the runs that wait for
+// capacity in its RunQueue or, when RUN_MAX_ACTIVE_RUNS is set, in the workspace
+func getQueuePosition(c client.Reader, instance *databricksv1alpha1.Run) (int32, error) {
+	maxActiveRuns := getMaxActiveRuns()
+	listOptions := []client.ListOption{}
+	if maxActiveRuns == nil {
+		listOptions = append(listOptions, client.InNamespace(instance.GetNamespace()))
+	}
+
+	var queues databricksv1alpha1.RunQueueList
+	if err := c.List(context.Background(), &queues, listOptions...); err != nil {
+		return 0, err
+	}
+	groups := map[types.NamespacedName]*runQueueGroup{}
+	for i := range queues.Items {
+		if queues.Items[i].Spec != nil {
+			groups[types.NamespacedName{Namespace: queues.Items[i].GetNamespace(), Name: queues.Items[i].GetName()}] = &runQueueGroup{queue: &queues.Items[i]}
+		}
+	}
+
+	instanceKey := types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetQueueName()}
+	instanceGroup, hasQueue := groups[instanceKey]
+	if !hasQueue {
+		if instance.Spec != nil && instance.Spec.QueueName != "" {
+			return 0, fmt.Errorf("run queue %s not found", instance.Spec.QueueName)
+		}
+		if maxActiveRuns == nil {
+			return 0, nil
+		}
+	}
+
+	var runs databricksv1alpha1.RunList
+	if err := c.List(context.Background(), &runs, listOptions...); err != nil {
+		return 0, err
+	}
+	// the run being reconciled replaces its copy from the list, it waits even when it is terminated and to be rerun
+	items := []*databricksv1alpha1.Run{instance}
+	for i := range runs.Items {
+		if runs.Items[i].GetNamespace() != instance.GetNamespace() || runs.Items[i].GetName() != instance.GetName() {
+			items = append(items, &runs.Items[i])
+		}
+	}
+	var active int32
+	for _, run := range items {
+		key := types.NamespacedName{Namespace: run.GetNamespace(), Name: run.GetQueueName()}
+		group, ok := groups[key]
+		if !ok {
+			group = &runQueueGroup{}
+			groups[key] = group
+		}
+		if run.IsActive() && run != instance {
+			group.active++
+			active++
+		} else if run == instance || (run.IsQueued() && run.Spec != nil) {
+			group.waiting = append(group.waiting, run)
+		}
+	}
+
+	priority := func(run *databricksv1alpha1.Run) int32 {
+		if run.Spec == nil {
+			return 0
+		}
+		return groups[types.NamespacedName{Namespace: run.GetNamespace(), Name: run.GetQueueName()}].queue.GetPriority(run.Spec.PriorityClassName)
+	}
+	for _, group := range groups {
+		sortQueuedRuns(group.waiting, priority)
+	}
+
+	if hasQueue {
+		if position := queuePosition(instance, instanceGroup.waiting, instanceGroup.active, instanceGroup.queue.Spec.MaxActiveRuns); position > 0 || maxActiveRuns == nil {
+			return position, nil
+		}
+	}
+
+	// only the runs that their own queue admits compete for the capacity of the workspace
+	var candidates []*databricksv1alpha1.Run
+	for _, group := range groups {
+		candidates = append(candidates, group.admitted()...)
+	}
+	sortQueuedRuns(candidates, priority)
+	return queuePosition(instance, candidates, active, *maxActiveRuns), nil
+}
+
+// sortQueuedRuns orders the runs by priority and then by creation time
+func sortQueuedRuns(runs []*databricksv1alpha1.Run, priority func(*databricksv1alpha1.Run) int32) {
+	sort.SliceStable(runs, func(i, j int) bool {
+		if pi, pj := priority(runs[i]), priority(runs[j]); pi != pj {
+			return pi > pj
+		}
+		if ti, tj := runs[i].GetCreationTimestamp(), runs[j].GetCreationTimestamp(); !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		if runs[i].GetNamespace() != runs[j].GetNamespace() {
+			return runs[i].GetNamespace() < runs[j].GetNamespace()
+		}
+		return runs[i].GetName() < runs[j].GetName()
+	})
+}
+
+// queuePosition returns 0 if the capacity left by the active runs admits the instance, or its position among the waiting runs otherwise
+func queuePosition(instance *databricksv1alpha1.Run, waiting []*databricksv1alpha1.Run, active, maxActiveRuns int32) int32 {
+	for i, run := range waiting {
+		if run == instance {
+			if position := active + int32(i) - maxActiveRuns + 1; position > 0 {
+				return position
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// getMaxActiveRuns returns the number of runs that can be active in the workspace, or nil if it is not limited
+func getMaxActiveRuns() *int32 {
+	maxActiveRuns, err := strconv.ParseInt(os.Getenv(runMaxActiveRunsEnvName), 10, 32)
+	if err != nil || maxActiveRuns < 1 {
+		return nil
+	}
+	max := int32(maxActiveRuns)
+	return &max
+}
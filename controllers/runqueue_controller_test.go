@@ -0,0 +1,223 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("RunQueue Controller", func() {
+
+	var (
+		c       client.Client
+		queue   *databricksv1alpha1.RunQueue
+		objects []runtime.Object
+	)
+
+	// newRun returns a Run of the default queue created some minutes ago, which is active when it has a life cycle state
+	newRun := func(namespace, name string, minutesAgo int, priorityClassName string, lifeCycleState dbmodels.RunLifeCycleState) *databricksv1alpha1.Run {
+		run := &databricksv1alpha1.Run{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				CreationTimestamp: metav1.Time{Time: time.Now().Add(-time.Duration(minutesAgo) * time.Minute)},
+			},
+			Spec: &databricksv1alpha1.RunSpec{
+				PriorityClassName: priorityClassName,
+				JobTask:           &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/test"}},
+			},
+		}
+		if lifeCycleState != "" {
+			run.Status = &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				Metadata: dbmodels.Run{JobID: 1, RunID: 2, State: &dbmodels.RunState{LifeCycleState: &lifeCycleState}},
+			}}
+		}
+		return run
+	}
+
+	setup := func() {
+		c = newFakeClient(append(objects, queue)...)
+	}
+
+	position := func(namespace, name string) int32 {
+		run := &databricksv1alpha1.Run{}
+		Expect(c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, run)).To(Succeed())
+		position, err := getQueuePosition(c, run)
+		Expect(err).ToNot(HaveOccurred())
+		return position
+	}
+
+	BeforeEach(func() {
+		queue = &databricksv1alpha1.RunQueue{
+			ObjectMeta: metav1.ObjectMeta{Name: databricksv1alpha1.DefaultRunQueueName, Namespace: "default"},
+			Spec: &databricksv1alpha1.RunQueueSpec{
+				MaxActiveRuns:   1,
+				PriorityClasses: []databricksv1alpha1.RunPriorityClass{{Name: "high", Value: 100}},
+			},
+		}
+		objects = []runtime.Object{
+			newRun("default", "active", 10, "", dbmodels.RunLifeCycleStateRunning),
+			newRun("default", "old", 5, "", ""),
+			newRun("default", "urgent", 1, "high", ""),
+			newRun("other", "unqueued", 1, "", ""),
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(runMaxActiveRunsEnvName)).To(Succeed())
+	})
+
+	It("Should order queued runs by priority and creation time", func() {
+		setup()
+		Expect(position("default", "urgent")).To(Equal(int32(1)))
+		Expect(position("default", "old")).To(Equal(int32(2)))
+		Expect(position("other", "unqueued")).To(BeZero())
+
+		By("Admitting the first run once the active run terminated")
+		objects[0] = newRun("default", "active", 10, "", dbmodels.RunLifeCycleStateTerminated)
+		setup()
+		Expect(position("default", "urgent")).To(BeZero())
+		Expect(position("default", "old")).To(Equal(int32(1)))
+	})
+
+	It("Should limit the active runs of the workspace", func() {
+		queue.Spec.MaxActiveRuns = 5
+		Expect(os.Setenv(runMaxActiveRunsEnvName, "2")).To(Succeed())
+		setup()
+		Expect(position("default", "urgent")).To(BeZero())
+		Expect(position("default", "old")).To(Equal(int32(1)))
+		Expect(position("other", "unqueued")).To(Equal(int32(2)))
+	})
+
+	It("Should not let runs held by their queue take the capacity of the workspace", func() {
+		queue.Spec.MaxActiveRuns = 1
+		Expect(os.Setenv(runMaxActiveRunsEnvName, "2")).To(Succeed())
+		setup()
+		Expect(position("other", "unqueued")).To(BeZero())
+	})
+
+	It("Should fail when the queue of the run does not exist", func() {
+		setup()
+		run := newRun("default", "missing", 1, "", "")
+		run.Spec.QueueName = "missing"
+		_, err := getQueuePosition(c, run)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should count the active and queued runs of the queue", func() {
+		setup()
+		reconciler := &RunQueueReconciler{
+			Client:   c,
+			Log:      ctrl.Log.WithName("controllers").WithName("RunQueue"),
+			Recorder: record.NewFakeRecorder(100),
+		}
+		key := types.NamespacedName{Namespace: "default", Name: databricksv1alpha1.DefaultRunQueueName}
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+
+		fetched := &databricksv1alpha1.RunQueue{}
+		Expect(c.Get(context.Background(), key, fetched)).To(Succeed())
+		Expect(*fetched.Status).To(Equal(databricksv1alpha1.RunQueueStatus{Active: 1, Queued: 2}))
+	})
+
+	It("Should keep a queued run in Kubernetes until it is admitted", func() {
+		databricks := newFakeDatabricks()
+		defer databricks.Close()
+		databricks.lifeCycleState = dbmodels.RunLifeCycleStatePending
+		for _, object := range objects {
+			object.(*databricksv1alpha1.Run).Finalizers = []string{databricksv1alpha1.RunFinalizerName}
+		}
+		setup()
+		reconciler := &RunReconciler{
+			Client:    c,
+			Log:       ctrl.Log.WithName("controllers").WithName("Run"),
+			Recorder:  record.NewFakeRecorder(100),
+			APIClient: databricks.client(),
+		}
+		key := types.NamespacedName{Namespace: "default", Name: "old"}
+
+		result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runQueuePollInterval))
+		Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(BeZero())
+		fetched := &databricksv1alpha1.Run{}
+		Expect(c.Get(context.Background(), key, fetched)).To(Succeed())
+		Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunPhaseQueued))
+		Expect(fetched.Status.QueuePosition).To(Equal(int32(2)))
+
+		By("Submitting it once the queue has capacity")
+		Expect(c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: queue.GetName()}, queue)).To(Succeed())
+		queue.Spec.MaxActiveRuns = 3
+		Expect(c.Update(context.Background(), queue)).To(Succeed())
+		_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(Equal(1))
+		fetched = &databricksv1alpha1.Run{}
+		Expect(c.Get(context.Background(), key, fetched)).To(Succeed())
+		Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunPhasePending))
+		Expect(fetched.Status.QueuePosition).To(BeZero())
+	})
+
+	It("Should count the active runs from the API server rather than the cache", func() {
+		databricks := newFakeDatabricks()
+		defer databricks.Close()
+		for _, object := range objects {
+			object.(*databricksv1alpha1.Run).Finalizers = []string{databricksv1alpha1.RunFinalizerName}
+		}
+		setup()
+		// the cache has not seen the active run yet
+		cache := newFakeClient(objects[1], objects[2], objects[3], queue)
+		reconciler := &RunReconciler{
+			Client:    cache,
+			APIReader: c,
+			Log:       ctrl.Log.WithName("controllers").WithName("Run"),
+			Recorder:  record.NewFakeRecorder(100),
+			APIClient: databricks.client(),
+		}
+		key := types.NamespacedName{Namespace: "default", Name: "urgent"}
+
+		result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runQueuePollInterval))
+		Expect(databricks.calls["POST /api/2.0/jobs/runs/submit"]).To(BeZero())
+		fetched := &databricksv1alpha1.Run{}
+		Expect(cache.Get(context.Background(), key, fetched)).To(Succeed())
+		Expect(fetched.Status.QueuePosition).To(Equal(int32(1)))
+	})
+})
@@ -0,0 +1,281 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+)
+
+const runSetCreateBatchSizeEnvName = "RUNSET_CREATE_BATCH_SIZE"
+
+const defaultRunSetCreateBatchSize = 10
+
+// runSetCreateInterval is how long to wait before creating the next batch of Runs
+const runSetCreateInterval = 5 * time.Second
+
+// RunSetReconciler reconciles a RunSet object
+type RunSetReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runsets/status,verbs=get;update;patch
+
+// Reconcile implements the reconciliation loop for the operator
+func (r *RunSetReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = context.Background()
+	_ = r.Log.WithValues("runset", req.NamespacedName)
+
+	instance := &databricksv1alpha1.RunSet{}
+
+	r.Log.Info(fmt.Sprintf("Starting reconcile loop for %v", req.NamespacedName))
+	defer r.Log.Info(fmt.Sprintf("Finish reconcile loop for %v", req.NamespacedName))
+
+	if err := r.Get(context.Background(), req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// the Runs are owned by the RunSet and garbage collected with it
+	if !instance.ObjectMeta.DeletionTimestamp.IsZero() || instance.Spec == nil || instance.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	status := &databricksv1alpha1.RunSetStatus{}
+	if instance.Status != nil {
+		status = instance.Status.DeepCopy()
+	}
+	if status.StartTime == nil {
+		now := metav1.Now()
+		status.StartTime = &now
+	}
+
+	parameterSets := instance.GetParameterSets()
+	status.Total = int32(len(parameterSets))
+
+	created, err := r.syncRuns(instance, status)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Syncing runs", fmt.Sprintf("Failed to sync runs: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when syncing runs of run set: %v", err)
+	}
+
+	// indexes that have neither finished nor have a Run yet, in order
+	var pending []int32
+	for index := int32(0); index < status.Total; index++ {
+		if !created[index] && !containsIndex(status.SucceededIndexes, index) && !containsIndex(status.FailedIndexes, index) {
+			pending = append(pending, index)
+		}
+	}
+
+	completions := instance.GetCompletions(status.Total)
+	switch {
+	case status.Succeeded >= completions:
+		r.finish(instance, status, databricksv1alpha1.RunSetPhaseSucceeded)
+		return ctrl.Result{}, r.updateStatus(instance, status)
+	case status.Succeeded+status.Active+int32(len(pending)) < completions:
+		r.finish(instance, status, databricksv1alpha1.RunSetPhaseFailed)
+		return ctrl.Result{}, r.updateStatus(instance, status)
+	}
+	status.Phase = databricksv1alpha1.RunSetPhaseRunning
+
+	// create Runs up to the parallelism, a batch at a time to spread the submissions
+	toCreate := instance.GetParallelism() - status.Active
+	if remaining := completions - status.Succeeded - status.Active; remaining < toCreate {
+		toCreate = remaining
+	}
+	if toCreate > int32(len(pending)) {
+		toCreate = int32(len(pending))
+	}
+	if toCreate <= 0 {
+		return ctrl.Result{}, r.updateStatus(instance, status)
+	}
+	if status.LastCreationTime != nil {
+		if wait := status.LastCreationTime.Add(runSetCreateInterval).Sub(time.Now()); wait > 0 {
+			return ctrl.Result{RequeueAfter: wait}, r.updateStatus(instance, status)
+		}
+	}
+	if batchSize := getRunSetCreateBatchSize(); toCreate > batchSize {
+		toCreate = batchSize
+	}
+
+	for _, index := range pending[:toCreate] {
+		run, err := newRunSetRun(instance, index, parameterSets[index])
+		if err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Creating run", fmt.Sprintf("Failed to create run: %s", err))
+			r.finish(instance, status, databricksv1alpha1.RunSetPhaseFailed)
+			return ctrl.Result{}, r.updateStatus(instance, status)
+		}
+		if err := controllerutil.SetControllerReference(instance, run, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(context.Background(), run); err != nil && !errors.IsAlreadyExists(err) {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Creating run", fmt.Sprintf("Failed to create run: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when creating run %s: %v", run.GetName(), err)
+		}
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Created", fmt.Sprintf("Created run %s", run.GetName()))
+		status.Active++
+	}
+	now := metav1.Now()
+	status.LastCreationTime = &now
+
+	// requeue in case the parallelism allows more Runs than the batch
+	return ctrl.Result{RequeueAfter: runSetCreateInterval}, r.updateStatus(instance, status)
+}
+
+// SetupWithManager adds the controller manager
+func (r *RunSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databricksv1alpha1.RunSet{}).
+		Owns(&databricksv1alpha1.Run{}).
+		Complete(r)
+}
+
+// syncRuns records the Runs of the set that finished and counts the active ones.
+// It returns the indexes that have a Run.
+func (r *RunSetReconciler) syncRuns(instance *databricksv1alpha1.RunSet, status *databricksv1alpha1.RunSetStatus) (map[int32]bool, error) {
+	var runs databricksv1alpha1.RunList
+	err := r.List(context.Background(), &runs, client.InNamespace(instance.GetNamespace()), client.MatchingLabels{
+		databricksv1alpha1.RunSetLabel: instance.GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created := map[int32]bool{}
+	status.Active = 0
+	for _, run := range runs.Items {
+		if !metav1.IsControlledBy(&run, instance) {
+			continue
+		}
+		index64, err := strconv.ParseInt(run.GetLabels()[databricksv1alpha1.RunSetIndexLabel], 10, 32)
+		if err != nil {
+			continue
+		}
+		index := int32(index64)
+		created[index] = true
+		switch {
+		case !run.IsFinal():
+			status.Active++
+		case run.IsSucceeded():
+			if !containsIndex(status.SucceededIndexes, index) {
+				status.SucceededIndexes = append(status.SucceededIndexes, index)
+			}
+		default:
+			if !containsIndex(status.FailedIndexes, index) {
+				status.FailedIndexes = append(status.FailedIndexes, index)
+			}
+		}
+	}
+	sort.Slice(status.SucceededIndexes, func(i, j int) bool { return status.SucceededIndexes[i] < status.SucceededIndexes[j] })
+	sort.Slice(status.FailedIndexes, func(i, j int) bool { return status.FailedIndexes[i] < status.FailedIndexes[j] })
+	status.Succeeded = int32(len(status.SucceededIndexes))
+	status.Failed = int32(len(status.FailedIndexes))
+	return created, nil
+}
+
+func (r *RunSetReconciler) finish(instance *databricksv1alpha1.RunSet, status *databricksv1alpha1.RunSetStatus, phase databricksv1alpha1.RunSetPhase) {
+	now := metav1.Now()
+	status.Phase = phase
+	status.CompletionTime = &now
+	r.Recorder.Event(instance, corev1.EventTypeNormal, string(phase), fmt.Sprintf("%d of %d runs succeeded", status.Succeeded, status.Total))
+}
+
+func (r *RunSetReconciler) updateStatus(instance *databricksv1alpha1.RunSet, status *databricksv1alpha1.RunSetStatus) error {
+	if reflect.DeepEqual(instance.Status, status) {
+		return nil
+	}
+	instance.Status = status
+	return r.Update(context.Background(), instance)
+}
+
+// newRunSetRun creates the Run for a parameter set from the template of the RunSet
+func newRunSetRun(instance *databricksv1alpha1.RunSet, index int32, parameters map[string]string) (*databricksv1alpha1.Run, error) {
+	spec := instance.Spec.RunTemplate.DeepCopy()
+	for name, value := range parameters {
+		if spec.TemplateRef != nil {
+			if spec.TemplateParams == nil {
+				spec.TemplateParams = map[string]string{}
+			}
+			spec.TemplateParams[name] = value
+			continue
+		}
+		if err := spec.SetNotebookParameter(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return &databricksv1alpha1.Run{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.GetRunName(index),
+			Namespace: instance.GetNamespace(),
+			Labels: map[string]string{
+				databricksv1alpha1.RunSetLabel:      instance.GetName(),
+				databricksv1alpha1.RunSetIndexLabel: strconv.Itoa(int(index)),
+			},
+		},
+		Spec: spec,
+	}, nil
+}
+
+// getRunSetCreateBatchSize returns the maximum number of Runs created by a single reconcile
+func getRunSetCreateBatchSize() int32 {
+	batchSize, err := strconv.ParseInt(os.Getenv(runSetCreateBatchSizeEnvName), 10, 32)
+	if err != nil || batchSize < 1 {
+		return defaultRunSetCreateBatchSize
+	}
+	return int32(batchSize)
+}
+
+func containsIndex(indexes []int32, index int32) bool {
+	for _, i := range indexes {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
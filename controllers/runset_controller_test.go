@@ -0,0 +1,216 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("RunSet Controller", func() {
+
+	var (
+		reconciler *RunSetReconciler
+		instance   *databricksv1alpha1.RunSet
+		key        types.NamespacedName
+	)
+
+	setup := func() {
+		reconciler = &RunSetReconciler{
+			Client:   newFakeClient(instance),
+			Log:      ctrl.Log.WithName("controllers").WithName("RunSet"),
+			Scheme:   fakeScheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+	}
+
+	// reconcile runs the reconcile loop as if the previous batch of runs was created long ago
+	reconcile := func() (ctrl.Result, *databricksv1alpha1.RunSet) {
+		fetched := &databricksv1alpha1.RunSet{}
+		Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+		if fetched.Status != nil && fetched.Status.LastCreationTime != nil {
+			fetched.Status.LastCreationTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			Expect(reconciler.Update(context.Background(), fetched)).To(Succeed())
+		}
+		result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+		return result, fetched
+	}
+
+	listRuns := func() map[string]*databricksv1alpha1.Run {
+		var runs databricksv1alpha1.RunList
+		Expect(reconciler.List(context.Background(), &runs, client.InNamespace(key.Namespace))).To(Succeed())
+		byName := map[string]*databricksv1alpha1.Run{}
+		for i := range runs.Items {
+			byName[runs.Items[i].GetName()] = &runs.Items[i]
+		}
+		return byName
+	}
+
+	finishRun := func(index int32, result dbmodels.RunResultState) {
+		run := &databricksv1alpha1.Run{}
+		Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: instance.GetRunName(index), Namespace: key.Namespace}, run)).To(Succeed())
+		lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+		run.Status = &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+			Metadata: dbmodels.Run{
+				JobID: 1,
+				State: &dbmodels.RunState{LifeCycleState: &lifeCycleState, ResultState: &result},
+			},
+		}}
+		Expect(reconciler.Update(context.Background(), run)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		key = types.NamespacedName{Name: "t-runset", Namespace: "default"}
+		parallelism := int32(2)
+		instance = &databricksv1alpha1.RunSet{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "t-runset-uid"},
+			Spec: &databricksv1alpha1.RunSetSpec{
+				Items: []map[string]string{
+					{"date": "2020-01-01"},
+					{"date": "2020-01-02"},
+					{"date": "2020-01-03"},
+				},
+				Parallelism: &parallelism,
+				RunTemplate: databricksv1alpha1.RunSpec{
+					JobTask: &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{NotebookPath: "/backfill"}},
+				},
+			},
+		}
+	})
+
+	It("Should create runs up to the parallelism and succeed once all runs succeeded", func() {
+		setup()
+		_, fetched := reconcile()
+		runs := listRuns()
+		Expect(runs).To(HaveLen(2))
+		Expect(runs["t-runset-0"].Spec.NotebookTask.BaseParameters).To(Equal(map[string]string{"date": "2020-01-01"}))
+		Expect(runs["t-runset-1"].GetLabels()[databricksv1alpha1.RunSetIndexLabel]).To(Equal("1"))
+		Expect(metav1.IsControlledBy(runs["t-runset-1"], fetched)).To(BeTrue())
+		Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunSetPhaseRunning))
+		Expect(fetched.Status.Total).To(Equal(int32(3)))
+		Expect(fetched.Status.Active).To(Equal(int32(2)))
+
+		By("Waiting for a run to finish")
+		_, _ = reconcile()
+		Expect(listRuns()).To(HaveLen(2))
+
+		finishRun(0, dbmodels.RunResultStateSuccess)
+		_, fetched = reconcile()
+		Expect(listRuns()).To(HaveLen(3))
+		Expect(fetched.Status.Succeeded).To(Equal(int32(1)))
+		Expect(fetched.Status.Active).To(Equal(int32(2)))
+
+		finishRun(1, dbmodels.RunResultStateSuccess)
+		finishRun(2, dbmodels.RunResultStateSuccess)
+		_, fetched = reconcile()
+		Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunSetPhaseSucceeded))
+		Expect(fetched.Status.SucceededIndexes).To(Equal([]int32{0, 1, 2}))
+		Expect(fetched.Status.CompletionTime).ToNot(BeNil())
+	})
+
+	It("Should wait between batches of runs", func() {
+		setup()
+		result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(runSetCreateInterval))
+
+		finishRun(0, dbmodels.RunResultStateSuccess)
+		result, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(listRuns()).To(HaveLen(2))
+	})
+
+	It("Should not create runs again once they finished and were deleted", func() {
+		setup()
+		_, _ = reconcile()
+		finishRun(0, dbmodels.RunResultStateFailed)
+		_, fetched := reconcile()
+		Expect(fetched.Status.FailedIndexes).To(Equal([]int32{0}))
+
+		Expect(reconciler.Delete(context.Background(), listRuns()["t-runset-0"])).To(Succeed())
+		_, fetched = reconcile()
+		Expect(listRuns()).ToNot(HaveKey("t-runset-0"))
+		Expect(fetched.Status.Failed).To(Equal(int32(1)))
+		// three completions are no longer possible with one failed run
+		Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunSetPhaseFailed))
+	})
+
+	It("Should keep a failed run that is retried active", func() {
+		setup()
+		_, _ = reconcile()
+		finishRun(0, dbmodels.RunResultStateFailed)
+		run := listRuns()["t-runset-0"]
+		run.Status.NextRetryTime = &metav1.Time{Time: time.Now()}
+		Expect(reconciler.Update(context.Background(), run)).To(Succeed())
+		_, fetched := reconcile()
+		Expect(fetched.Status.FailedIndexes).To(BeEmpty())
+		Expect(fetched.Status.Active).To(Equal(int32(2)))
+
+		finishRun(0, dbmodels.RunResultStateSuccess)
+		_, fetched = reconcile()
+		Expect(fetched.Status.SucceededIndexes).To(Equal([]int32{0}))
+		Expect(fetched.Status.FailedIndexes).To(BeEmpty())
+	})
+
+	It("Should succeed after the number of completions", func() {
+		completions := int32(1)
+		instance.Spec.Completions = &completions
+		instance.Spec.Items = nil
+		instance.Spec.Matrix = map[string][]string{"date": {"2020-01-01", "2020-01-02"}, "region": {"eu", "us"}}
+		setup()
+		_, fetched := reconcile()
+		Expect(fetched.Status.Total).To(Equal(int32(4)))
+		// only one more run is needed to reach the completions
+		Expect(listRuns()).To(HaveLen(1))
+		Expect(listRuns()["t-runset-0"].Spec.NotebookTask.BaseParameters).To(Equal(map[string]string{"date": "2020-01-01", "region": "eu"}))
+
+		finishRun(0, dbmodels.RunResultStateSuccess)
+		_, fetched = reconcile()
+		Expect(fetched.Status.Phase).To(Equal(databricksv1alpha1.RunSetPhaseSucceeded))
+		Expect(listRuns()).To(HaveLen(1))
+	})
+
+	It("Should set template parameters for runs from a RunTemplate", func() {
+		instance.Spec.RunTemplate = databricksv1alpha1.RunSpec{TemplateRef: &databricksv1alpha1.RunTemplateRef{Name: "backfill"}}
+		setup()
+		_, _ = reconcile()
+		Expect(listRuns()).ToNot(HaveKey("t-runset-2"))
+		Expect(listRuns()["t-runset-1"].Spec.TemplateParams).To(Equal(map[string]string{"date": "2020-01-02"}))
+	})
+})
@@ -0,0 +1,338 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+)
+
+// RunWorkflowReconciler reconciles a RunWorkflow object
+type RunWorkflowReconciler struct {
+	client.Client
+	Log       logr.Logger
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	APIClient dbazure.DBClient
+}
+
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runworkflows,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=runworkflows/status,verbs=get;update;patch
+
+// Reconcile implements the reconciliation loop for the operator
+func (r *RunWorkflowReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = context.Background()
+	_ = r.Log.WithValues("runworkflow", req.NamespacedName)
+
+	instance := &databricksv1alpha1.RunWorkflow{}
+
+	r.Log.Info(fmt.Sprintf("Starting reconcile loop for %v", req.NamespacedName))
+	defer r.Log.Info(fmt.Sprintf("Finish reconcile loop for %v", req.NamespacedName))
+
+	if err := r.Get(context.Background(), req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if instance.IsBeingDeleted() {
+		r.Log.Info(fmt.Sprintf("HandleFinalizer for %v", req.NamespacedName))
+		if err := r.handleFinalizer(instance); err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "deleting finalizer", fmt.Sprintf("Failed to delete finalizer: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when handling finalizer: %v", err)
+		}
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Deleted", "Object finalizer is deleted")
+		return ctrl.Result{}, nil
+	}
+
+	if !instance.HasFinalizer(databricksv1alpha1.RunWorkflowFinalizerName) {
+		r.Log.Info(fmt.Sprintf("AddFinalizer for %v", req.NamespacedName))
+		if err := r.addFinalizer(instance); err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Adding finalizer", fmt.Sprintf("Failed to add finalizer: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when adding finalizer: %v", err)
+		}
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Added", "Object finalizer is added")
+		return ctrl.Result{}, nil
+	}
+
+	if instance.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	steps, err := instance.SortedSteps()
+	if err != nil {
+		// the spec has to change before the workflow can make progress, so do not requeue
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Invalid", fmt.Sprintf("Invalid workflow: %s", err))
+		return ctrl.Result{}, r.updateStatus(instance, &databricksv1alpha1.RunWorkflowStatus{
+			Phase:   databricksv1alpha1.RunWorkflowPhaseFailed,
+			Message: err.Error(),
+		})
+	}
+
+	r.Log.Info(fmt.Sprintf("Sync for %v", req.NamespacedName))
+	if err := r.sync(instance, steps); err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Syncing object", fmt.Sprintf("Failed to sync object: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when syncing run workflow: %v", err)
+	}
+	if instance.IsFinished() {
+		r.Recorder.Event(instance, corev1.EventTypeNormal, string(instance.Status.Phase), "Workflow has finished")
+	}
+
+	// progress is driven by the Runs the workflow owns
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager adds the controller manager
+func (r *RunWorkflowReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databricksv1alpha1.RunWorkflow{}).
+		Owns(&databricksv1alpha1.Run{}).
+		Complete(r)
+}
+
+// sync observes the Run of every step, in dependency order, and creates the
+// Runs of steps whose dependencies have finished and whose condition is met
+func (r *RunWorkflowReconciler) sync(instance *databricksv1alpha1.RunWorkflow, steps []databricksv1alpha1.RunWorkflowStep) error {
+	statuses := map[string]databricksv1alpha1.RunWorkflowStepStatus{}
+	status := &databricksv1alpha1.RunWorkflowStatus{}
+	if instance.Status != nil {
+		status.StartTime = instance.Status.StartTime
+	}
+	if status.StartTime == nil {
+		status.StartTime = &metav1.Time{Time: metav1.Now().Time}
+	}
+
+	for _, step := range steps {
+		stepStatus, err := r.syncStep(instance, step, statuses)
+		if err != nil {
+			return err
+		}
+		statuses[step.Name] = stepStatus
+	}
+
+	// report steps in the order they are declared
+	finished, failed := true, false
+	for _, step := range instance.Spec.Steps {
+		stepStatus := statuses[step.Name]
+		status.Steps = append(status.Steps, stepStatus)
+		finished = finished && stepStatus.IsFinished()
+		failed = failed || stepStatus.Phase == databricksv1alpha1.RunWorkflowPhaseFailed
+	}
+	switch {
+	case !finished:
+		status.Phase = databricksv1alpha1.RunWorkflowPhaseRunning
+	case failed:
+		status.Phase = databricksv1alpha1.RunWorkflowPhaseFailed
+	default:
+		status.Phase = databricksv1alpha1.RunWorkflowPhaseSucceeded
+	}
+	if finished {
+		status.CompletionTime = &metav1.Time{Time: metav1.Now().Time}
+	}
+
+	if instance.Status != nil && reflect.DeepEqual(instance.Status.Steps, status.Steps) && instance.Status.Phase == status.Phase {
+		return nil
+	}
+	return r.updateStatus(instance, status)
+}
+
+func (r *RunWorkflowReconciler) syncStep(instance *databricksv1alpha1.RunWorkflow, step databricksv1alpha1.RunWorkflowStep, statuses map[string]databricksv1alpha1.RunWorkflowStepStatus) (databricksv1alpha1.RunWorkflowStepStatus, error) {
+	if previous := instance.GetStepStatus(step.Name); previous != nil && previous.IsFinished() {
+		return *previous, nil
+	}
+
+	stepStatus := databricksv1alpha1.RunWorkflowStepStatus{Name: step.Name}
+	runName := instance.GetStepRunName(step.Name)
+	run := &databricksv1alpha1.Run{}
+	err := r.Get(context.Background(), types.NamespacedName{Name: runName, Namespace: instance.GetNamespace()}, run)
+	if err == nil {
+		if !metav1.IsControlledBy(run, instance) {
+			return stepStatus, fmt.Errorf("run %s already exists and is not owned by the workflow", runName)
+		}
+		stepStatus.RunName = runName
+		if !run.IsFinal() {
+			stepStatus.Phase = databricksv1alpha1.RunWorkflowPhaseRunning
+			return stepStatus, nil
+		}
+		output, err := r.getStepOutput(run)
+		if err != nil {
+			return stepStatus, err
+		}
+		stepStatus.Output = output
+		if run.IsSucceeded() {
+			stepStatus.Phase = databricksv1alpha1.RunWorkflowPhaseSucceeded
+			return stepStatus, nil
+		}
+		stepStatus.Phase = databricksv1alpha1.RunWorkflowPhaseFailed
+		stepStatus.Message = run.Status.Error
+		if stepStatus.Message == "" {
+			stepStatus.Message = run.Status.Metadata.State.StateMessage
+		}
+		return stepStatus, nil
+	}
+	if !errors.IsNotFound(err) {
+		return stepStatus, err
+	}
+	if previous := instance.GetStepStatus(step.Name); previous != nil && previous.RunName != "" {
+		// the run was deleted before its result was observed, for example by its TTL
+		stepStatus.Phase = databricksv1alpha1.RunWorkflowPhaseFailed
+		stepStatus.Message = fmt.Sprintf("run %s was deleted", previous.RunName)
+		return stepStatus, nil
+	}
+
+	ready, message := evaluateStep(step, statuses)
+	if !ready {
+		stepStatus.Phase = databricksv1alpha1.RunWorkflowPhasePending
+		if message != "" {
+			stepStatus.Phase = databricksv1alpha1.RunWorkflowPhaseSkipped
+			stepStatus.Message = message
+		}
+		return stepStatus, nil
+	}
+
+	run, err = newStepRun(instance, step, statuses)
+	if err != nil {
+		// a parameter that cannot be resolved will not resolve later either
+		stepStatus.Phase = databricksv1alpha1.RunWorkflowPhaseFailed
+		stepStatus.Message = err.Error()
+		return stepStatus, nil
+	}
+	if err := controllerutil.SetControllerReference(instance, run, r.Scheme); err != nil {
+		return stepStatus, err
+	}
+	if err := r.Create(context.Background(), run); err != nil {
+		return stepStatus, err
+	}
+	r.Recorder.Event(instance, corev1.EventTypeNormal, "Started", fmt.Sprintf("Created run %s for step %s", runName, step.Name))
+
+	stepStatus.RunName = runName
+	stepStatus.Phase = databricksv1alpha1.RunWorkflowPhaseRunning
+	return stepStatus, nil
+}
+
+// evaluateStep returns true if all dependencies of the step have finished and its
+// condition is met. If the condition is not met the message says why the step is skipped.
+func evaluateStep(step databricksv1alpha1.RunWorkflowStep, statuses map[string]databricksv1alpha1.RunWorkflowStepStatus) (bool, string) {
+	anyFailed, allSucceeded := false, true
+	for _, dependency := range step.DependsOn {
+		dependencyStatus := statuses[dependency]
+		if !dependencyStatus.IsFinished() {
+			return false, ""
+		}
+		anyFailed = anyFailed || dependencyStatus.Phase == databricksv1alpha1.RunWorkflowPhaseFailed
+		allSucceeded = allSucceeded && dependencyStatus.Phase == databricksv1alpha1.RunWorkflowPhaseSucceeded
+	}
+
+	switch step.When {
+	case databricksv1alpha1.RunWorkflowStepAlways:
+		return true, ""
+	case databricksv1alpha1.RunWorkflowStepOnFailure:
+		if anyFailed {
+			return true, ""
+		}
+		return false, "no dependency failed"
+	default:
+		if allSucceeded {
+			return true, ""
+		}
+		return false, "not all dependencies succeeded"
+	}
+}
+
+// newStepRun creates the Run for a step from its template, with the parameters
+// taken from the output of its dependencies
+func newStepRun(instance *databricksv1alpha1.RunWorkflow, step databricksv1alpha1.RunWorkflowStep, statuses map[string]databricksv1alpha1.RunWorkflowStepStatus) (*databricksv1alpha1.Run, error) {
+	spec := step.Template.DeepCopy()
+	for _, parameter := range step.ParametersFrom {
+		value, err := notebookOutputValue(statuses[parameter.Step].Output, parameter.Key)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading parameter %s from step %s: %v", parameter.Name, parameter.Step, err)
+		}
+		if err := spec.SetNotebookParameter(parameter.Name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return &databricksv1alpha1.Run{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.GetStepRunName(step.Name),
+			Namespace: instance.GetNamespace(),
+			Labels: map[string]string{
+				databricksv1alpha1.RunWorkflowLabel:     instance.GetName(),
+				databricksv1alpha1.RunWorkflowStepLabel: step.Name,
+			},
+		},
+		Spec: spec,
+	}, nil
+}
+
+// notebookOutputValue returns the notebook output, or a field of it when the notebook exited with a JSON object
+func notebookOutputValue(output, key string) (string, error) {
+	if key == "" {
+		return output, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &fields); err != nil {
+		return "", fmt.Errorf("output is not a JSON object: %v", err)
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("output has no field %s", key)
+	}
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
+
+func (r *RunWorkflowReconciler) updateStatus(instance *databricksv1alpha1.RunWorkflow, status *databricksv1alpha1.RunWorkflowStatus) error {
+	err := r.Get(context.Background(), types.NamespacedName{
+		Name:      instance.GetName(),
+		Namespace: instance.GetNamespace(),
+	}, instance)
+	if err != nil {
+		return err
+	}
+	instance.Status = status
+	return r.Update(context.Background(), instance)
+}
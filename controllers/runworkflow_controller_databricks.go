@@ -0,0 +1,48 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+)
+
+// getStepOutput returns the notebook result of the run of a step. The result in the status of the
+// run may be capped by the operator, the whole result is then read through Jobs API 2.1.
+func (r *RunWorkflowReconciler) getStepOutput(run *databricksv1alpha1.Run) (string, error) {
+	if !run.Status.ResultTruncated {
+		return run.Status.NotebookOutput.Result, nil
+	}
+	r.Log.Info(fmt.Sprintf("Reading the whole output of run %s", run.GetName()))
+
+	execution := NewExecution("runworkflows", "run_get_output")
+	output, err := newJobsAPI(r.APIClient).RunsGetOutput(run.Status.Metadata.RunID)
+	execution.Finish(err)
+	if err != nil {
+		return "", fmt.Errorf("error when reading the output of run %s: %v", run.GetName(), err)
+	}
+	return output.NotebookOutput.Result, nil
+}
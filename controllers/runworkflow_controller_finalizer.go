@@ -0,0 +1,75 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (r *RunWorkflowReconciler) addFinalizer(instance *databricksv1alpha1.RunWorkflow) error {
+	instance.AddFinalizer(databricksv1alpha1.RunWorkflowFinalizerName)
+	return r.Update(context.Background(), instance)
+}
+
+func (r *RunWorkflowReconciler) handleFinalizer(instance *databricksv1alpha1.RunWorkflow) error {
+	if !instance.HasFinalizer(databricksv1alpha1.RunWorkflowFinalizerName) {
+		return nil
+	}
+
+	if err := r.delete(instance); err != nil {
+		return err
+	}
+	instance.RemoveFinalizer(databricksv1alpha1.RunWorkflowFinalizerName)
+	return r.Update(context.Background(), instance)
+}
+
+// delete removes the Runs of the workflow, which cancels those still running in DataBricks
+func (r *RunWorkflowReconciler) delete(instance *databricksv1alpha1.RunWorkflow) error {
+	if instance.Spec == nil {
+		return nil
+	}
+	for _, step := range instance.Spec.Steps {
+		run := &databricksv1alpha1.Run{}
+		err := r.Get(context.Background(), types.NamespacedName{Name: instance.GetStepRunName(step.Name), Namespace: instance.GetNamespace()}, run)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(run, instance) {
+			continue
+		}
+		if err := r.Delete(context.Background(), run); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("RunWorkflow Controller", func() {
+
+	var (
+		reconciler *RunWorkflowReconciler
+		key        types.NamespacedName
+		databricks *fakeDatabricks
+	)
+
+	// finishRun terminates the Run of a step with the specified result and notebook output
+	finishRun := func(step string, result dbmodels.RunResultState, output string) {
+		run := &databricksv1alpha1.Run{}
+		Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: key.Name + "-" + step, Namespace: key.Namespace}, run)).To(Succeed())
+		lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+		run.Status = &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+			NotebookOutput: dbmodels.NotebookOutput{Result: output},
+			Metadata: dbmodels.Run{
+				JobID: 1,
+				State: &dbmodels.RunState{LifeCycleState: &lifeCycleState, ResultState: &result},
+			},
+		}}
+		Expect(reconciler.Update(context.Background(), run)).To(Succeed())
+	}
+
+	reconcile := func() *databricksv1alpha1.RunWorkflow {
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		fetched := &databricksv1alpha1.RunWorkflow{}
+		Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+		return fetched
+	}
+
+	stepPhases := func(rw *databricksv1alpha1.RunWorkflow) map[string]databricksv1alpha1.RunWorkflowPhase {
+		phases := map[string]databricksv1alpha1.RunWorkflowPhase{}
+		for _, step := range rw.Status.Steps {
+			phases[step.Name] = step.Phase
+		}
+		return phases
+	}
+
+	BeforeEach(func() {
+		key = types.NamespacedName{Name: "t-workflow", Namespace: "default"}
+		notebook := func(path string) databricksv1alpha1.RunSpec {
+			return databricksv1alpha1.RunSpec{
+				ClusterSpec: databricksv1alpha1.ClusterSpec{ExistingClusterID: "0101-abc"},
+				JobTask:     &dbmodels.JobTask{NotebookTask: &dbmodels.NotebookTask{NotebookPath: path}},
+			}
+		}
+		instance := &databricksv1alpha1.RunWorkflow{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: &databricksv1alpha1.RunWorkflowSpec{
+				Steps: []databricksv1alpha1.RunWorkflowStep{
+					{Name: "prepare", Template: notebook("/prepare")},
+					{
+						Name:      "train",
+						DependsOn: []string{"prepare"},
+						ParametersFrom: []databricksv1alpha1.RunWorkflowParameter{
+							{Name: "table", Step: "prepare", Key: "table"},
+							{Name: "rows", Step: "prepare", Key: "rows"},
+						},
+						Template: notebook("/train"),
+					},
+					{Name: "cleanup", DependsOn: []string{"train"}, When: databricksv1alpha1.RunWorkflowStepOnFailure, Template: notebook("/cleanup")},
+					{Name: "notify", DependsOn: []string{"train"}, When: databricksv1alpha1.RunWorkflowStepAlways, Template: notebook("/notify")},
+				},
+			},
+		}
+
+		databricks = newFakeDatabricks()
+		reconciler = &RunWorkflowReconciler{
+			Client:    newFakeClient(instance),
+			Log:       ctrl.Log.WithName("controllers").WithName("RunWorkflow"),
+			Scheme:    fakeScheme,
+			Recorder:  record.NewFakeRecorder(100),
+			APIClient: databricks.client(),
+		}
+		reconcile() // finalizer
+	})
+
+	AfterEach(func() {
+		databricks.Close()
+	})
+
+	Context("Workflow with dependencies", func() {
+		It("Should run steps in order and pass notebook outputs", func() {
+			rw := reconcile()
+			Expect(rw.Status.Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseRunning))
+			Expect(stepPhases(rw)).To(Equal(map[string]databricksv1alpha1.RunWorkflowPhase{
+				"prepare": databricksv1alpha1.RunWorkflowPhaseRunning,
+				"train":   databricksv1alpha1.RunWorkflowPhasePending,
+				"cleanup": databricksv1alpha1.RunWorkflowPhasePending,
+				"notify":  databricksv1alpha1.RunWorkflowPhasePending,
+			}))
+
+			run := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "t-workflow-prepare", Namespace: key.Namespace}, run)).To(Succeed())
+			Expect(metav1.IsControlledBy(run, rw)).To(BeTrue())
+			Expect(run.Labels[databricksv1alpha1.RunWorkflowStepLabel]).To(Equal("prepare"))
+
+			finishRun("prepare", dbmodels.RunResultStateSuccess, `{"table": "sales", "rows": 42}`)
+			rw = reconcile()
+			Expect(rw.GetStepStatus("prepare").Output).To(Equal(`{"table": "sales", "rows": 42}`))
+			Expect(rw.GetStepStatus("train").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseRunning))
+
+			Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "t-workflow-train", Namespace: key.Namespace}, run)).To(Succeed())
+			Expect(run.Spec.NotebookTask.BaseParameters).To(Equal(map[string]string{"table": "sales", "rows": "42"}))
+
+			finishRun("train", dbmodels.RunResultStateSuccess, "")
+			rw = reconcile()
+			Expect(rw.GetStepStatus("cleanup").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseSkipped))
+			Expect(rw.GetStepStatus("notify").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseRunning))
+
+			finishRun("notify", dbmodels.RunResultStateSuccess, "")
+			rw = reconcile()
+			Expect(rw.Status.Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseSucceeded))
+			Expect(rw.Status.CompletionTime).ToNot(BeNil())
+
+			By("Deleting the runs of the workflow")
+			Expect(reconciler.delete(rw)).To(Succeed())
+			Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "t-workflow-train", Namespace: key.Namespace}, run)).ToNot(Succeed())
+		})
+
+		It("Should run failure handlers and report failure", func() {
+			reconcile()
+			finishRun("prepare", dbmodels.RunResultStateSuccess, `{"table": "sales", "rows": 42}`)
+			reconcile()
+			finishRun("train", dbmodels.RunResultStateFailed, "")
+			rw := reconcile()
+			Expect(rw.GetStepStatus("train").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseFailed))
+			Expect(rw.GetStepStatus("cleanup").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseRunning))
+			Expect(rw.GetStepStatus("notify").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseRunning))
+
+			finishRun("cleanup", dbmodels.RunResultStateSuccess, "")
+			finishRun("notify", dbmodels.RunResultStateSuccess, "")
+			rw = reconcile()
+			Expect(rw.Status.Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseFailed))
+		})
+
+		It("Should wait for a failed step that is retried", func() {
+			reconcile()
+			finishRun("prepare", dbmodels.RunResultStateFailed, "")
+			run := &databricksv1alpha1.Run{}
+			runKey := types.NamespacedName{Name: "t-workflow-prepare", Namespace: key.Namespace}
+			Expect(reconciler.Get(context.Background(), runKey, run)).To(Succeed())
+			run.Status.NextRetryTime = &metav1.Time{Time: time.Now()}
+			Expect(reconciler.Update(context.Background(), run)).To(Succeed())
+			rw := reconcile()
+			Expect(rw.GetStepStatus("prepare").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseRunning))
+			Expect(rw.GetStepStatus("cleanup").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhasePending))
+
+			By("Following the retry to its result")
+			finishRun("prepare", dbmodels.RunResultStateSuccess, `{"table": "sales", "rows": 42}`)
+			rw = reconcile()
+			Expect(rw.GetStepStatus("prepare").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseSucceeded))
+			Expect(rw.GetStepStatus("train").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseRunning))
+		})
+
+		It("Should read the whole output of a step whose result was capped", func() {
+			reconcile()
+			finishRun("prepare", dbmodels.RunResultStateSuccess, `{"table": "sa`)
+			run := &databricksv1alpha1.Run{}
+			Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "t-workflow-prepare", Namespace: key.Namespace}, run)).To(Succeed())
+			run.Status.ResultTruncated = true
+			Expect(reconciler.Update(context.Background(), run)).To(Succeed())
+			databricks.result = `{"table": "sales", "rows": 42}`
+
+			rw := reconcile()
+			Expect(databricks.calls["GET /api/2.1/jobs/runs/get-output"]).To(Equal(1))
+			Expect(rw.GetStepStatus("prepare").Output).To(Equal(`{"table": "sales", "rows": 42}`))
+			Expect(reconciler.Get(context.Background(), types.NamespacedName{Name: "t-workflow-train", Namespace: key.Namespace}, run)).To(Succeed())
+			Expect(run.Spec.NotebookTask.BaseParameters).To(Equal(map[string]string{"table": "sales", "rows": "42"}))
+		})
+
+		It("Should fail a step whose parameters cannot be resolved", func() {
+			reconcile()
+			finishRun("prepare", dbmodels.RunResultStateSuccess, "sales")
+			rw := reconcile()
+			Expect(rw.GetStepStatus("train").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseFailed))
+			Expect(rw.GetStepStatus("train").Message).To(ContainSubstring("not a JSON object"))
+			Expect(rw.GetStepStatus("cleanup").Phase).To(Equal(databricksv1alpha1.RunWorkflowPhaseRunning))
+		})
+	})
+})
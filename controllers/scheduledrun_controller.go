@@ -0,0 +1,316 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+)
+
+// ScheduledRunReconciler reconciles a ScheduledRun object
+type ScheduledRunReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=scheduledruns,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=databricks.microsoft.com,resources=scheduledruns/status,verbs=get;update;patch
+
+// Reconcile implements the reconciliation loop for the operator
+func (r *ScheduledRunReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	_ = context.Background()
+	_ = r.Log.WithValues("scheduledrun", req.NamespacedName)
+
+	instance := &databricksv1alpha1.ScheduledRun{}
+
+	r.Log.Info(fmt.Sprintf("Starting reconcile loop for %v", req.NamespacedName))
+	defer r.Log.Info(fmt.Sprintf("Finish reconcile loop for %v", req.NamespacedName))
+
+	if err := r.Get(context.Background(), req.NamespacedName, instance); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// the Runs are owned by the ScheduledRun and garbage collected with it
+	if !instance.ObjectMeta.DeletionTimestamp.IsZero() || instance.Spec == nil {
+		return ctrl.Result{}, nil
+	}
+
+	status := &databricksv1alpha1.ScheduledRunStatus{}
+	if instance.Status != nil {
+		status = instance.Status.DeepCopy()
+	}
+
+	active, err := r.syncHistory(instance, status)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Syncing history", fmt.Sprintf("Failed to sync runs: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when syncing scheduled runs: %v", err)
+	}
+
+	if instance.Spec.Suspend {
+		return ctrl.Result{}, r.updateStatus(instance, status)
+	}
+
+	now := time.Now()
+	scheduledTime, next, tooManyMissed, err := getScheduleTimes(instance, now)
+	if err != nil {
+		// the spec has to change before anything can be scheduled, so do not requeue
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "InvalidSchedule", fmt.Sprintf("Failed to parse schedule: %s", err))
+		return ctrl.Result{}, nil
+	}
+	if tooManyMissed {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "TooManyMissedTimes", fmt.Sprintf("More than %d start times were missed, skipping to the latest one. Set or decrease starting_deadline_seconds or check clock skew.", maxMissedScheduleTimes))
+	}
+	result := ctrl.Result{RequeueAfter: next.Sub(now)}
+
+	if scheduledTime == nil {
+		return result, r.updateStatus(instance, status)
+	}
+
+	switch instance.GetConcurrencyPolicy() {
+	case databricksv1alpha1.ForbidConcurrent:
+		if len(active) > 0 {
+			// retried when an active run finishes, as long as the starting deadline allows
+			r.Log.Info(fmt.Sprintf("Run of %v for %v is forbidden while %d runs are active", req.NamespacedName, scheduledTime, len(active)))
+			return result, r.updateStatus(instance, status)
+		}
+	case databricksv1alpha1.ReplaceConcurrent:
+		for i := range active {
+			if err := r.Delete(context.Background(), &active[i]); err != nil && !errors.IsNotFound(err) {
+				return ctrl.Result{}, fmt.Errorf("error when replacing run %s: %v", active[i].GetName(), err)
+			}
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Replaced", fmt.Sprintf("Deleted active run %s", active[i].GetName()))
+		}
+		status.Active = nil
+	}
+
+	run, err := r.createRun(instance, *scheduledTime)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Creating run", fmt.Sprintf("Failed to create run: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when creating scheduled run: %v", err)
+	}
+	r.Recorder.Event(instance, corev1.EventTypeNormal, "Created", fmt.Sprintf("Created run %s", run.GetName()))
+
+	// a run that already existed is listed as active by syncHistory
+	alreadyActive := false
+	for _, name := range status.Active {
+		alreadyActive = alreadyActive || name == run.GetName()
+	}
+	if !alreadyActive {
+		status.Active = append(status.Active, run.GetName())
+	}
+	status.LastScheduleTime = &metav1.Time{Time: *scheduledTime}
+	return result, r.updateStatus(instance, status)
+}
+
+// SetupWithManager adds the controller manager
+func (r *ScheduledRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&databricksv1alpha1.ScheduledRun{}).
+		Owns(&databricksv1alpha1.Run{}).
+		Complete(r)
+}
+
+// maxMissedScheduleTimes is how many missed start times are walked through, as for a CronJob,
+// before skipping straight to the latest one
+const maxMissedScheduleTimes = 100
+
+// getScheduleTimes returns the latest schedule time that has not been run yet and
+// is within the starting deadline, or nil if there is none, and the next schedule time.
+// It also returns true if more than maxMissedScheduleTimes start times were missed.
+func getScheduleTimes(instance *databricksv1alpha1.ScheduledRun, now time.Time) (*time.Time, time.Time, bool, error) {
+	schedule, err := instance.GetSchedule()
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+	location, err := instance.GetLocation()
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+
+	earliest := instance.GetCreationTimestamp().Time
+	if instance.Status != nil && instance.Status.LastScheduleTime != nil {
+		earliest = instance.Status.LastScheduleTime.Time
+	}
+	if instance.Spec.StartingDeadlineSeconds != nil {
+		deadline := now.Add(-time.Duration(*instance.Spec.StartingDeadlineSeconds) * time.Second)
+		if deadline.After(earliest) {
+			earliest = deadline
+		}
+	}
+
+	var scheduledTime *time.Time
+	t := schedule.Next(earliest.In(location))
+	for missed := 1; !t.After(now); missed++ {
+		if missed > maxMissedScheduleTimes {
+			latest := latestScheduleTime(schedule, t, now)
+			return &latest, schedule.Next(latest), true, nil
+		}
+		scheduled := t
+		scheduledTime = &scheduled
+		t = schedule.Next(t)
+	}
+	return scheduledTime, t, false, nil
+}
+
+// latestScheduleTime returns the last schedule time up to now, given one at or after which to look.
+// It looks back from now over growing windows so that the missed times are not walked through.
+func latestScheduleTime(schedule cron.Schedule, after time.Time, now time.Time) time.Time {
+	for window := time.Minute; ; window *= 2 {
+		from := now.Add(-window).In(after.Location())
+		if !from.After(after) {
+			from = after
+		}
+		latest := after
+		for t := schedule.Next(from); !t.After(now); t = schedule.Next(t) {
+			latest = t
+		}
+		if latest.After(after) || from.Equal(after) {
+			return latest
+		}
+	}
+}
+
+// syncHistory records the active and last successful runs and deletes finished
+// runs beyond the history limits. It returns the runs that are still active.
+func (r *ScheduledRunReconciler) syncHistory(instance *databricksv1alpha1.ScheduledRun, status *databricksv1alpha1.ScheduledRunStatus) ([]databricksv1alpha1.Run, error) {
+	var runs databricksv1alpha1.RunList
+	err := r.List(context.Background(), &runs, client.InNamespace(instance.GetNamespace()), client.MatchingLabels{
+		databricksv1alpha1.ScheduledRunLabel: instance.GetName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var active, successful, failed []databricksv1alpha1.Run
+	for _, run := range runs.Items {
+		if !metav1.IsControlledBy(&run, instance) {
+			continue
+		}
+		switch {
+		case !run.IsFinal():
+			active = append(active, run)
+		case run.IsSucceeded():
+			successful = append(successful, run)
+		default:
+			failed = append(failed, run)
+		}
+	}
+
+	status.Active = nil
+	for _, run := range active {
+		status.Active = append(status.Active, run.GetName())
+	}
+	sort.Strings(status.Active)
+
+	sortRunsBySchedule(successful)
+	if len(successful) > 0 {
+		lastSuccessful := metav1.NewTime(scheduledTimeOf(successful[len(successful)-1]))
+		status.LastSuccessfulTime = &lastSuccessful
+	}
+
+	sortRunsBySchedule(failed)
+	for _, history := range []struct {
+		runs  []databricksv1alpha1.Run
+		limit int
+	}{
+		{successful, instance.GetSuccessfulRunsHistoryLimit()},
+		{failed, instance.GetFailedRunsHistoryLimit()},
+	} {
+		for i := 0; i < len(history.runs)-history.limit; i++ {
+			if err := r.Delete(context.Background(), &history.runs[i]); err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Deleted", fmt.Sprintf("Deleted finished run %s", history.runs[i].GetName()))
+		}
+	}
+	return active, nil
+}
+
+func (r *ScheduledRunReconciler) createRun(instance *databricksv1alpha1.ScheduledRun, scheduledTime time.Time) (*databricksv1alpha1.Run, error) {
+	run := &databricksv1alpha1.Run{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.GetRunName(scheduledTime),
+			Namespace: instance.GetNamespace(),
+			Labels: map[string]string{
+				databricksv1alpha1.ScheduledRunLabel: instance.GetName(),
+			},
+			Annotations: map[string]string{
+				databricksv1alpha1.ScheduledRunTimeAnnotation: scheduledTime.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: instance.Spec.RunTemplate.DeepCopy(),
+	}
+	if err := controllerutil.SetControllerReference(instance, run, r.Scheme); err != nil {
+		return nil, err
+	}
+	// the run name is derived from the schedule time, so a run that already exists was created by an earlier reconcile
+	if err := r.Create(context.Background(), run); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (r *ScheduledRunReconciler) updateStatus(instance *databricksv1alpha1.ScheduledRun, status *databricksv1alpha1.ScheduledRunStatus) error {
+	if reflect.DeepEqual(instance.Status, status) {
+		return nil
+	}
+	instance.Status = status
+	return r.Update(context.Background(), instance)
+}
+
+// scheduledTimeOf returns the time the run was scheduled for, falling back to its creation time
+func scheduledTimeOf(run databricksv1alpha1.Run) time.Time {
+	if scheduled, err := time.Parse(time.RFC3339, run.GetAnnotations()[databricksv1alpha1.ScheduledRunTimeAnnotation]); err == nil {
+		return scheduled
+	}
+	return run.GetCreationTimestamp().Time
+}
+
+func sortRunsBySchedule(runs []databricksv1alpha1.Run) {
+	sort.Slice(runs, func(i, j int) bool {
+		return scheduledTimeOf(runs[i]).Before(scheduledTimeOf(runs[j]))
+	})
+}
@@ -0,0 +1,258 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ScheduledRun Controller", func() {
+
+	var (
+		reconciler *ScheduledRunReconciler
+		instance   *databricksv1alpha1.ScheduledRun
+		key        types.NamespacedName
+	)
+
+	// existingRun returns a Run that was created for the ScheduledRun some minutes ago
+	existingRun := func(minutesAgo int, result dbmodels.RunResultState) *databricksv1alpha1.Run {
+		scheduledTime := time.Now().Add(-time.Duration(minutesAgo) * time.Minute).Truncate(time.Minute)
+		isController := true
+		run := &databricksv1alpha1.Run{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      instance.GetRunName(scheduledTime),
+				Namespace: key.Namespace,
+				Labels:    map[string]string{databricksv1alpha1.ScheduledRunLabel: key.Name},
+				Annotations: map[string]string{
+					databricksv1alpha1.ScheduledRunTimeAnnotation: scheduledTime.UTC().Format(time.RFC3339),
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "databricks.microsoft.com/v1alpha1", Kind: "ScheduledRun", Name: key.Name, UID: instance.GetUID(), Controller: &isController},
+				},
+			},
+			Spec: instance.Spec.RunTemplate.DeepCopy(),
+		}
+		if result != "" {
+			lifeCycleState := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+			run.Status = &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				Metadata: dbmodels.Run{
+					JobID: 1,
+					State: &dbmodels.RunState{LifeCycleState: &lifeCycleState, ResultState: &result},
+				},
+			}}
+		}
+		return run
+	}
+
+	setup := func(objects ...runtime.Object) {
+		reconciler = &ScheduledRunReconciler{
+			Client:   newFakeClient(append(objects, instance)...),
+			Log:      ctrl.Log.WithName("controllers").WithName("ScheduledRun"),
+			Scheme:   fakeScheme,
+			Recorder: record.NewFakeRecorder(100),
+		}
+	}
+
+	reconcile := func() (ctrl.Result, *databricksv1alpha1.ScheduledRun) {
+		result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		fetched := &databricksv1alpha1.ScheduledRun{}
+		Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+		return result, fetched
+	}
+
+	runNames := func() []string {
+		var runs databricksv1alpha1.RunList
+		Expect(reconciler.List(context.Background(), &runs, client.InNamespace(key.Namespace))).To(Succeed())
+		var names []string
+		for _, run := range runs.Items {
+			names = append(names, run.GetName())
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		key = types.NamespacedName{Name: "t-scheduled", Namespace: "default"}
+		instance = &databricksv1alpha1.ScheduledRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              key.Name,
+				Namespace:         key.Namespace,
+				UID:               "t-scheduled-uid",
+				CreationTimestamp: metav1.Time{Time: time.Now().Add(-5 * time.Minute)},
+			},
+			Spec: &databricksv1alpha1.ScheduledRunSpec{
+				Schedule: "* * * * *",
+				RunTemplate: databricksv1alpha1.RunSpec{
+					JobName: "nightly",
+				},
+			},
+		}
+	})
+
+	Context("Schedule times", func() {
+		It("Should return the latest missed schedule time within the deadline", func() {
+			now := time.Date(2020, 1, 1, 12, 30, 30, 0, time.UTC)
+			instance.CreationTimestamp = metav1.Time{Time: now.Add(-time.Hour)}
+			instance.Spec.Schedule = "*/10 * * * *"
+
+			scheduled, next, tooManyMissed, err := getScheduleTimes(instance, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*scheduled).To(Equal(time.Date(2020, 1, 1, 12, 30, 0, 0, time.UTC)))
+			Expect(next).To(Equal(time.Date(2020, 1, 1, 12, 40, 0, 0, time.UTC)))
+			Expect(tooManyMissed).To(BeFalse())
+
+			instance.Status = &databricksv1alpha1.ScheduledRunStatus{LastScheduleTime: &metav1.Time{Time: *scheduled}}
+			scheduled, _, _, err = getScheduleTimes(instance, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scheduled).To(BeNil())
+
+			instance.Status = nil
+			deadline := int64(10)
+			instance.Spec.StartingDeadlineSeconds = &deadline
+			scheduled, _, _, err = getScheduleTimes(instance, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scheduled).To(BeNil())
+
+			instance.Spec.TimeZone = "Nowhere/Special"
+			_, _, _, err = getScheduleTimes(instance, now)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should skip to the latest schedule time when too many were missed", func() {
+			now := time.Date(2020, 1, 1, 12, 30, 30, 0, time.UTC)
+			instance.CreationTimestamp = metav1.Time{Time: now.Add(-365 * 24 * time.Hour)}
+			instance.Spec.Schedule = "*/5 * * * *"
+
+			scheduled, next, tooManyMissed, err := getScheduleTimes(instance, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tooManyMissed).To(BeTrue())
+			Expect(*scheduled).To(Equal(time.Date(2020, 1, 1, 12, 30, 0, 0, time.UTC)))
+			Expect(next).To(Equal(time.Date(2020, 1, 1, 12, 35, 0, 0, time.UTC)))
+
+			instance.CreationTimestamp = metav1.Time{Time: now.Add(-time.Hour)}
+			_, _, tooManyMissed, err = getScheduleTimes(instance, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tooManyMissed).To(BeFalse())
+		})
+	})
+
+	Context("Scheduled run", func() {
+		It("Should create a run for the latest missed schedule", func() {
+			setup()
+			result, fetched := reconcile()
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+
+			var runs databricksv1alpha1.RunList
+			Expect(reconciler.List(context.Background(), &runs, client.InNamespace(key.Namespace))).To(Succeed())
+			Expect(runs.Items).To(HaveLen(1))
+			run := runs.Items[0]
+			Expect(metav1.IsControlledBy(&run, fetched)).To(BeTrue())
+			Expect(run.Spec.JobName).To(Equal("nightly"))
+			Expect(fetched.Status.Active).To(Equal([]string{run.GetName()}))
+			Expect(run.GetName()).To(Equal(fetched.GetRunName(fetched.Status.LastScheduleTime.Time)))
+
+			By("Not creating another run before the next schedule")
+			reconcile()
+			Expect(runNames()).To(HaveLen(1))
+
+			By("Recording the successful run")
+			finished := existingRun(0, dbmodels.RunResultStateSuccess)
+			run.Status = finished.Status
+			Expect(reconciler.Update(context.Background(), &run)).To(Succeed())
+			_, fetched = reconcile()
+			Expect(fetched.Status.Active).To(BeEmpty())
+			Expect(fetched.Status.LastSuccessfulTime).ToNot(BeNil())
+		})
+
+		It("Should not create runs while suspended", func() {
+			instance.Spec.Suspend = true
+			setup()
+			result, _ := reconcile()
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(runNames()).To(BeEmpty())
+		})
+
+		It("Should skip the run while another is active when concurrency is forbidden", func() {
+			instance.Spec.ConcurrencyPolicy = databricksv1alpha1.ForbidConcurrent
+			active := existingRun(3, "")
+			setup(active)
+			_, fetched := reconcile()
+			Expect(runNames()).To(Equal([]string{active.GetName()}))
+			Expect(fetched.Status.Active).To(Equal([]string{active.GetName()}))
+			Expect(fetched.Status.LastScheduleTime).To(BeNil())
+		})
+
+		It("Should treat a failed run that is retried as active when concurrency is forbidden", func() {
+			instance.Spec.ConcurrencyPolicy = databricksv1alpha1.ForbidConcurrent
+			retrying := existingRun(3, dbmodels.RunResultStateFailed)
+			retrying.Status.NextRetryTime = &metav1.Time{Time: time.Now()}
+			setup(retrying)
+			_, fetched := reconcile()
+			Expect(runNames()).To(Equal([]string{retrying.GetName()}))
+			Expect(fetched.Status.Active).To(Equal([]string{retrying.GetName()}))
+		})
+
+		It("Should replace the active run when concurrency is replace", func() {
+			instance.Spec.ConcurrencyPolicy = databricksv1alpha1.ReplaceConcurrent
+			active := existingRun(3, "")
+			setup(active)
+			_, fetched := reconcile()
+			names := runNames()
+			Expect(names).To(HaveLen(1))
+			Expect(names[0]).ToNot(Equal(active.GetName()))
+			Expect(fetched.Status.Active).To(Equal(names))
+		})
+
+		It("Should delete finished runs beyond the history limits", func() {
+			var one int32 = 1
+			instance.Spec.SuccessfulRunsHistoryLimit = &one
+			instance.Spec.FailedRunsHistoryLimit = &one
+			instance.Spec.Suspend = true
+			oldFailure := existingRun(4, dbmodels.RunResultStateFailed)
+			failure := existingRun(3, dbmodels.RunResultStateFailed)
+			oldSuccess := existingRun(2, dbmodels.RunResultStateSuccess)
+			success := existingRun(1, dbmodels.RunResultStateSuccess)
+			setup(oldFailure, failure, oldSuccess, success)
+
+			_, fetched := reconcile()
+			Expect(runNames()).To(ConsistOf(failure.GetName(), success.GetName()))
+			Expect(fetched.Status.LastSuccessfulTime.Time).To(BeTemporally("==", scheduledTimeOf(*success)))
+		})
+	})
+})
@@ -27,16 +27,20 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
@@ -115,6 +119,16 @@ func (r *SecretScopeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, nil
 	}
 
+	synced, err := r.syncSecrets(instance)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Failed", fmt.Sprintf("Failed to sync secrets: %s", err))
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, fmt.Errorf("error when syncing secrets to the API: %v", err)
+	}
+	if len(synced) > 0 {
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Synced", fmt.Sprintf("Secrets are synced for keys: %s", strings.Join(synced, ", ")))
+		return ctrl.Result{}, nil
+	}
+
 	r.Recorder.Event(instance, corev1.EventTypeNormal, "Completed", "Object has completed")
 	return ctrl.Result{}, nil
 }
@@ -123,5 +137,48 @@ func (r *SecretScopeReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 func (r *SecretScopeReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&databricksv1alpha1.SecretScope{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapValueFrom),
+		}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapValueFrom),
+		}).
 		Complete(r)
 }
+
+// mapValueFrom returns a request for each secret scope in the namespace of the object that reads
+// the value of a key from it
+func (r *SecretScopeReconciler) mapValueFrom(object handler.MapObject) []reconcile.Request {
+	var scopes databricksv1alpha1.SecretScopeList
+	if err := r.List(context.Background(), &scopes, client.InNamespace(object.Meta.GetNamespace())); err != nil {
+		r.Log.Info(fmt.Sprintf("Failed to list SecretScopes for %s: %v", object.Meta.GetName(), err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, scope := range scopes.Items {
+		for _, secret := range scope.Spec.SecretScopeSecrets {
+			if referencesValueFrom(secret.ValueFrom, object) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: scope.GetName(), Namespace: scope.GetNamespace()},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// referencesValueFrom returns true if the value of a secret scope key is read from the object
+func referencesValueFrom(from *databricksv1alpha1.SecretScopeValueFrom, object handler.MapObject) bool {
+	if from == nil {
+		return false
+	}
+	switch object.Object.(type) {
+	case *corev1.ConfigMap:
+		return from.ConfigMapKeyRef != nil && from.ConfigMapKeyRef.Name == object.Meta.GetName()
+	case *corev1.Secret:
+		return from.ConfigMapKeyRef == nil && from.SecretKeyRef.Name == object.Meta.GetName()
+	}
+	return false
+}
@@ -33,6 +33,7 @@ import (
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -58,6 +59,12 @@ func (r *SecretScopeReconciler) get(scope string) (*dbmodels.SecretScope, error)
 	return &matchingScope, nil
 }
 
+// exists returns true if the scope can be found in Databricks
+func (r *SecretScopeReconciler) exists(scope string) bool {
+	remoteScope, err := r.get(scope)
+	return err == nil && remoteScope != nil
+}
+
 func (r *SecretScopeReconciler) submitSecrets(instance *databricksv1alpha1.SecretScope) error {
 	scope := instance.ObjectMeta.Name
 	namespace := instance.Namespace
@@ -81,57 +88,184 @@ func (r *SecretScopeReconciler) submitSecrets(instance *databricksv1alpha1.Secre
 		}
 	}
 
+	keys := make([]databricksv1alpha1.SecretScopeKeyStatus, 0, len(instance.Spec.SecretScopeSecrets))
+	var failedKeys []string
 	for _, secret := range instance.Spec.SecretScopeSecrets {
-		if secret.StringValue != "" {
-			execution := NewExecution("secretscopes", "put_secret_string")
-			err = r.APIClient.Secrets().PutSecretString(secret.StringValue, scope, secret.Key)
-			execution.Finish(err)
-			if err != nil {
-				return err
-			}
-		} else if secret.ByteValue != "" {
-			v, err := base64.StdEncoding.DecodeString(secret.ByteValue)
-			if err != nil {
-				return err
-			}
-			execution := NewExecution("secretscopes", "put_secret")
-			err = r.APIClient.Secrets().PutSecret(v, scope, secret.Key)
-			execution.Finish(err)
-			if err != nil {
-				return err
+		source := secret.Source()
+		if source == "" {
+			continue
+		}
+
+		keyStatus := databricksv1alpha1.SecretScopeKeyStatus{
+			Key:    secret.Key,
+			Source: source,
+		}
+		// keep the last successful sync time if this attempt fails
+		if previous := instance.GetKeyStatus(secret.Key); previous != nil && previous.Source == source {
+			keyStatus.LastSyncedTime = previous.LastSyncedTime
+			keyStatus.ResourceVersion = previous.ResourceVersion
+		}
+
+		resourceVersion, err := r.putSecret(scope, namespace, secret)
+		if err != nil {
+			keyStatus.Error = err.Error()
+			failedKeys = append(failedKeys, secret.Key)
+		} else {
+			now := metav1.Now()
+			keyStatus.LastSyncedTime = &now
+			keyStatus.ResourceVersion = resourceVersion
+		}
+		keys = append(keys, keyStatus)
+	}
+
+	instance.Status.Keys = keys
+	if len(failedKeys) > 0 {
+		return fmt.Errorf("failed to put secrets for keys: %s", strings.Join(failedKeys, ", "))
+	}
+	return nil
+}
+
+// syncSecrets puts the secrets of a submitted scope again when their value may have changed: keys
+// that are missing from the inventory or failed before, and keys read from a k8s secret or config
+// map whose resource version moved since the last sync. Keys removed from the spec are deleted
+// from the scope. It returns the keys that were put or deleted.
+func (r *SecretScopeReconciler) syncSecrets(instance *databricksv1alpha1.SecretScope) ([]string, error) {
+	scope := instance.ObjectMeta.Name
+	namespace := instance.Namespace
+
+	var synced []string
+	var failedKeys []string
+	keys := make([]databricksv1alpha1.SecretScopeKeyStatus, 0, len(instance.Spec.SecretScopeSecrets))
+	inSpec := map[string]bool{}
+	for _, secret := range instance.Spec.SecretScopeSecrets {
+		source := secret.Source()
+		if source == "" {
+			continue
+		}
+		inSpec[secret.Key] = true
+
+		keyStatus := databricksv1alpha1.SecretScopeKeyStatus{Key: secret.Key, Source: source}
+		previous := instance.GetKeyStatus(secret.Key)
+		if previous != nil && previous.Source == source {
+			keyStatus = *previous
+		}
+		if previous != nil && previous.Source == source && previous.Error == "" {
+			if secret.ValueFrom == nil {
+				keys = append(keys, keyStatus)
+				continue
 			}
-		} else if secret.ValueFrom != nil {
-			value, err := r.getSecretValueFrom(namespace, secret)
-			if err != nil {
-				return err
+			_, resourceVersion, err := r.getSecretValueFrom(namespace, secret)
+			if err == nil && resourceVersion == previous.ResourceVersion {
+				keys = append(keys, keyStatus)
+				continue
 			}
+		}
 
-			execution := NewExecution("secretscopes", "put_secret_string")
-			err = r.APIClient.Secrets().PutSecretString(value, scope, secret.Key)
-			execution.Finish(err)
-			if err != nil {
-				return err
-			}
+		synced = append(synced, secret.Key)
+		resourceVersion, err := r.putSecret(scope, namespace, secret)
+		if err != nil {
+			keyStatus.Error = err.Error()
+			failedKeys = append(failedKeys, secret.Key)
+		} else {
+			now := metav1.Now()
+			keyStatus.LastSyncedTime = &now
+			keyStatus.ResourceVersion = resourceVersion
+			keyStatus.Error = ""
 		}
+		keys = append(keys, keyStatus)
 	}
 
-	return nil
+	for _, previous := range instance.Status.Keys {
+		if inSpec[previous.Key] {
+			continue
+		}
+		execution := NewExecution("secretscopes", "delete_secret")
+		err := r.APIClient.Secrets().DeleteSecret(scope, previous.Key)
+		execution.Finish(err)
+		if err != nil && !strings.Contains(err.Error(), "does not exist") {
+			keys = append(keys, previous)
+			failedKeys = append(failedKeys, previous.Key)
+			continue
+		}
+		synced = append(synced, previous.Key)
+	}
+
+	if len(synced) == 0 {
+		return nil, nil
+	}
+	instance.Status.Keys = keys
+	if err := r.Update(context.Background(), instance); err != nil {
+		return synced, err
+	}
+	if len(failedKeys) > 0 {
+		return synced, fmt.Errorf("failed to sync secrets for keys: %s", strings.Join(failedKeys, ", "))
+	}
+	return synced, nil
 }
 
-func (r *SecretScopeReconciler) getSecretValueFrom(namespace string, scopeSecret databricksv1alpha1.SecretScopeSecret) (string, error) {
-	if &scopeSecret.ValueFrom != nil {
-		namespacedName := types.NamespacedName{Namespace: namespace, Name: scopeSecret.ValueFrom.SecretKeyRef.Name}
-		secret := &v1.Secret{}
-		err := r.Get(context.Background(), namespacedName, secret)
+// putSecret writes a single secret into the scope. It returns the resource version of the
+// k8s object the value was read from, if any.
+func (r *SecretScopeReconciler) putSecret(scope string, namespace string, secret databricksv1alpha1.SecretScopeSecret) (string, error) {
+	switch secret.Source() {
+	case databricksv1alpha1.SecretScopeKeySourceInline:
+		execution := NewExecution("secretscopes", "put_secret_string")
+		err := r.APIClient.Secrets().PutSecretString(secret.StringValue, scope, secret.Key)
+		execution.Finish(err)
+		return "", err
+	case databricksv1alpha1.SecretScopeKeySourceByte:
+		v, err := base64.StdEncoding.DecodeString(secret.ByteValue)
+		if err != nil {
+			return "", err
+		}
+		execution := NewExecution("secretscopes", "put_secret")
+		err = r.APIClient.Secrets().PutSecret(v, scope, secret.Key)
+		execution.Finish(err)
+		return "", err
+	default:
+		value, resourceVersion, err := r.getSecretValueFrom(namespace, secret)
 		if err != nil {
 			return "", err
 		}
+		execution := NewExecution("secretscopes", "put_secret_string")
+		err = r.APIClient.Secrets().PutSecretString(value, scope, secret.Key)
+		execution.Finish(err)
+		return resourceVersion, err
+	}
+}
+
+// getSecretValueFrom reads the referenced value from a k8s secret or config map.
+// It returns the value and the resource version of the object it was read from.
+func (r *SecretScopeReconciler) getSecretValueFrom(namespace string, scopeSecret databricksv1alpha1.SecretScopeSecret) (string, string, error) {
+	if scopeSecret.ValueFrom == nil {
+		return "", "", fmt.Errorf("No ValueFrom present to extract secret")
+	}
+
+	if ref := scopeSecret.ValueFrom.ConfigMapKeyRef; ref != nil {
+		namespacedName := types.NamespacedName{Namespace: namespace, Name: ref.Name}
+		configMap := &v1.ConfigMap{}
+		err := r.Get(context.Background(), namespacedName, configMap)
+		if err != nil {
+			return "", "", err
+		}
 
-		value := string(secret.Data[scopeSecret.ValueFrom.SecretKeyRef.Key])
-		return value, nil
+		if value, ok := configMap.Data[ref.Key]; ok {
+			return value, configMap.ResourceVersion, nil
+		}
+		if value, ok := configMap.BinaryData[ref.Key]; ok {
+			return string(value), configMap.ResourceVersion, nil
+		}
+		return "", "", fmt.Errorf("key %s not found in config map %s", ref.Key, ref.Name)
 	}
 
-	return "", fmt.Errorf("No ValueFrom present to extract secret")
+	namespacedName := types.NamespacedName{Namespace: namespace, Name: scopeSecret.ValueFrom.SecretKeyRef.Name}
+	secret := &v1.Secret{}
+	err := r.Get(context.Background(), namespacedName, secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	value := string(secret.Data[scopeSecret.ValueFrom.SecretKeyRef.Key])
+	return value, secret.ResourceVersion, nil
 }
 
 func (r *SecretScopeReconciler) submitACLs(instance *databricksv1alpha1.SecretScope) error {
@@ -185,7 +319,7 @@ func (r *SecretScopeReconciler) checkSecrets(instance *databricksv1alpha1.Secret
 	// if secret in cluster is reference, see if secret exists.
 	for _, secret := range instance.Spec.SecretScopeSecrets {
 		if secret.ValueFrom != nil {
-			if _, err := r.getSecretValueFrom(namespace, secret); err != nil {
+			if _, _, err := r.getSecretValueFrom(namespace, secret); err != nil {
 				return err
 			}
 		}
@@ -199,16 +333,24 @@ func (r *SecretScopeReconciler) submit(instance *databricksv1alpha1.SecretScope)
 	scope := instance.ObjectMeta.Name
 	initialManagePrincipal := instance.Spec.InitialManagePrincipal
 
-	execution := NewExecution("secretscopes", "create_secret_scope")
-	err = r.APIClient.Secrets().CreateSecretScope(scope, initialManagePrincipal)
-	execution.Finish(err)
-	if err != nil {
-		return
+	// a pass that failed on some of the keys has recorded them after creating the scope,
+	// the scope is kept and only the secrets are put again
+	if len(instance.Status.Keys) == 0 || !r.exists(scope) {
+		execution := NewExecution("secretscopes", "create_secret_scope")
+		err = r.APIClient.Secrets().CreateSecretScope(scope, initialManagePrincipal)
+		execution.Finish(err)
+		if err != nil {
+			return
+		}
 	}
 
 	err = r.submitSecrets(instance)
 	if err != nil {
 		requeue = true
+		// record the per-key errors so they are visible on the object
+		if updateErr := r.Update(context.Background(), instance); updateErr != nil {
+			r.Log.Info(fmt.Sprintf("Failed to update key status of %s: %v", instance.GetName(), updateErr))
+		}
 		return
 	}
 
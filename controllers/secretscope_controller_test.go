@@ -39,6 +39,9 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 )
 
 var _ = Describe("SecretScope Controller", func() {
@@ -237,6 +240,15 @@ var _ = Describe("SecretScope Controller", func() {
 				return fetched.IsSubmitted()
 			}, timeout, interval).Should(BeTrue())
 
+			By("Reporting the synced keys in status")
+			Expect(fetched.Status.Keys).To(HaveLen(3))
+			secretFromSecretStatus := fetched.GetKeyStatus("secretFromSecret")
+			Expect(secretFromSecretStatus).ToNot(BeNil())
+			Expect(secretFromSecretStatus.Source).To(Equal(databricksv1alpha1.SecretScopeKeySourceSecretRef))
+			Expect(secretFromSecretStatus.ResourceVersion).ToNot(BeEmpty())
+			Expect(secretFromSecretStatus.LastSyncedTime).ToNot(BeNil())
+			Expect(secretFromSecretStatus.Error).To(BeEmpty())
+
 			By("Updating secrets successfully")
 			newSecretValue := "newSecretValue"
 			updatedSecrets := []databricksv1alpha1.SecretScopeSecret{
@@ -450,4 +462,123 @@ var _ = Describe("SecretScope Controller", func() {
 			}, timeout, interval).ShouldNot(Succeed())
 		})
 	})
+
+	Context("Secret Scope with a key that failed", func() {
+		It("Should put the secrets again into the scope it created", func() {
+			databricks := newFakeDatabricks()
+			defer databricks.Close()
+			databricks.failPutSecret = 1
+
+			key := types.NamespacedName{Name: "t-secretscope-retry", Namespace: "default"}
+			instance := &databricksv1alpha1.SecretScope{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Finalizers: []string{databricksv1alpha1.SecretScopeFinalizerName},
+				},
+				Spec: databricksv1alpha1.SecretScopeSpec{
+					InitialManagePrincipal: "users",
+					SecretScopeSecrets: []databricksv1alpha1.SecretScopeSecret{
+						{Key: "username", StringValue: "admin"},
+						{Key: "password", StringValue: "secret"},
+					},
+				},
+				Status: databricksv1alpha1.SecretScopeStatus{SecretInClusterAvailable: true},
+			}
+			reconciler := &SecretScopeReconciler{
+				Client:    newFakeClient(instance),
+				Log:       ctrl.Log.WithName("controllers").WithName("SecretScope"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+			fetched := &databricksv1alpha1.SecretScope{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.IsSubmitted()).To(BeFalse())
+			Expect(fetched.GetKeyStatus("username").Error).ToNot(BeEmpty())
+
+			By("Retrying the key without creating the scope again")
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/secrets/scopes/create"]).To(Equal(1))
+			Expect(databricks.secretScopes[key.Name]).To(Equal(map[string]string{"username": "admin", "password": "secret"}))
+			fetched = &databricksv1alpha1.SecretScope{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.IsSubmitted()).To(BeTrue())
+			Expect(fetched.GetKeyStatus("username").Error).To(BeEmpty())
+		})
+	})
+
+	Context("Secret Scope with a key from a k8s secret", func() {
+		It("Should put the key again when the secret changes", func() {
+			databricks := newFakeDatabricks()
+			defer databricks.Close()
+
+			key := types.NamespacedName{Name: "t-secretscope-resync", Namespace: "default"}
+			credentials := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "t-credentials", Namespace: key.Namespace},
+				Data:       map[string][]byte{"password": []byte("first")},
+			}
+			instance := &databricksv1alpha1.SecretScope{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       key.Name,
+					Namespace:  key.Namespace,
+					Finalizers: []string{databricksv1alpha1.SecretScopeFinalizerName},
+				},
+				Spec: databricksv1alpha1.SecretScopeSpec{
+					SecretScopeSecrets: []databricksv1alpha1.SecretScopeSecret{
+						{Key: "username", StringValue: "admin"},
+						{Key: "password", ValueFrom: &databricksv1alpha1.SecretScopeValueFrom{
+							SecretKeyRef: databricksv1alpha1.SecretScopeKeyRef{Name: credentials.Name, Key: "password"},
+						}},
+					},
+				},
+				Status: databricksv1alpha1.SecretScopeStatus{SecretInClusterAvailable: true},
+			}
+			reconciler := &SecretScopeReconciler{
+				Client:    newFakeClient(instance, credentials),
+				Log:       ctrl.Log.WithName("controllers").WithName("SecretScope"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+			Expect(reconciler.mapValueFrom(handler.MapObject{Meta: credentials, Object: credentials})).To(HaveLen(1))
+
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.secretScopes[key.Name]).To(Equal(map[string]string{"username": "admin", "password": "first"}))
+			puts := databricks.calls["POST /api/2.0/secrets/put"]
+
+			By("Not putting unchanged keys again")
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/secrets/put"]).To(Equal(puts))
+
+			By("Putting the key whose secret changed")
+			fetched := &databricksv1alpha1.SecretScope{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			synced := fetched.GetKeyStatus("password").ResourceVersion
+			credentials.Data["password"] = []byte("second")
+			Expect(reconciler.Update(context.Background(), credentials)).To(Succeed())
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.calls["POST /api/2.0/secrets/put"]).To(Equal(puts + 1))
+			Expect(databricks.secretScopes[key.Name]["password"]).To(Equal("second"))
+			fetched = &databricksv1alpha1.SecretScope{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.GetKeyStatus("password").ResourceVersion).ToNot(Equal(synced))
+
+			By("Deleting a key removed from the spec")
+			fetched.Spec.SecretScopeSecrets = fetched.Spec.SecretScopeSecrets[1:]
+			Expect(reconciler.Update(context.Background(), fetched)).To(Succeed())
+			_, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(databricks.secretScopes[key.Name]).To(Equal(map[string]string{"password": "second"}))
+			fetched = &databricksv1alpha1.SecretScope{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			Expect(fetched.Status.Keys).To(HaveLen(1))
+		})
+	})
 })
@@ -0,0 +1,316 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// notebookFile is a source file read from a config map or git snapshot
+type notebookFile struct {
+	// sourcePath is relative to the workspace directory and keeps its extension
+	sourcePath string
+	data       []byte
+}
+
+// notebookFormat infers the language and import format of a notebook from its file extension.
+// It returns false for files that are not notebooks.
+func notebookFormat(fileName string) (dbmodels.Language, dbmodels.ExportFormat, bool) {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".py":
+		return dbmodels.LanguagePython, dbmodels.ExportFormatSource, true
+	case ".scala":
+		return dbmodels.LanguageScala, dbmodels.ExportFormatSource, true
+	case ".sql":
+		return dbmodels.LanguageSQL, dbmodels.ExportFormatSource, true
+	case ".r":
+		return dbmodels.LanguageR, dbmodels.ExportFormatSource, true
+	case ".ipynb":
+		return "", dbmodels.ExportFormatJupyter, true
+	case ".dbc":
+		return "", dbmodels.ExportFormatDbc, true
+	}
+	return "", "", false
+}
+
+// getSourceVersion returns a string identifying the current versions of all sources and where they are
+// imported to, without reading them
+func (r *WorkspaceDirectoryReconciler) getSourceVersion(instance *databricksv1alpha1.WorkspaceDirectory) (string, error) {
+	versions := []string{fmt.Sprintf("path=%s", instance.Spec.Path)}
+	for _, source := range instance.Spec.ConfigMaps {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(context.Background(), types.NamespacedName{Namespace: instance.Namespace, Name: source.Name}, configMap); err != nil {
+			return "", fmt.Errorf("error when reading config map %s: %v", source.Name, err)
+		}
+		versions = append(versions, fmt.Sprintf("configmap/%s@%s&prefix=%s", source.Name, configMap.ResourceVersion, source.Prefix))
+	}
+	if snapshot := instance.Spec.GitSnapshot; snapshot != nil {
+		versions = append(versions, fmt.Sprintf("%s&strip=%d&path=%s", httpContentVersion(&snapshot.Archive), snapshot.StripComponents, snapshot.Path))
+	}
+	return strings.Join(versions, ","), nil
+}
+
+// sync imports the notebooks whose content has changed and deletes the notebooks no longer in the sources
+func (r *WorkspaceDirectoryReconciler) sync(instance *databricksv1alpha1.WorkspaceDirectory, sourceVersion string) error {
+	files, err := r.readSources(instance)
+	if err != nil {
+		// without the full list of notebooks nothing can be pruned safely, so give up on this pass
+		return err
+	}
+
+	items := make([]databricksv1alpha1.WorkspaceDirectoryItemStatus, 0, len(files))
+	desiredPaths := map[string]string{}
+	createdDirectories := map[string]bool{}
+
+	for _, file := range files {
+		language, format, ok := notebookFormat(file.sourcePath)
+		if !ok {
+			r.Log.Info(fmt.Sprintf("Skipping %s, which is not a notebook", file.sourcePath))
+			continue
+		}
+		itemPath, err := joinDirectoryPath(instance.Spec.Path, strings.TrimSuffix(file.sourcePath, path.Ext(file.sourcePath)))
+		if err != nil {
+			return err
+		}
+		if other, ok := desiredPaths[itemPath]; ok {
+			return fmt.Errorf("both %s and %s would be imported as %s", other, file.sourcePath, itemPath)
+		}
+		desiredPaths[itemPath] = file.sourcePath
+
+		item := databricksv1alpha1.WorkspaceDirectoryItemStatus{
+			Path:       itemPath,
+			SourcePath: file.sourcePath,
+			Language:   language,
+			Format:     format,
+			ObjectHash: databricksv1alpha1.HashContent(file.data),
+		}
+		previous := instance.GetItemStatus(itemPath)
+		if previous != nil && previous.Error == "" && previous.ObjectHash == item.ObjectHash &&
+			previous.Format == item.Format && previous.Language == item.Language {
+			items = append(items, *previous)
+			continue
+		}
+
+		if err := r.importItem(item, file.data, createdDirectories); err != nil {
+			item.Error = err.Error()
+			if previous != nil {
+				item.LastSyncedTime = previous.LastSyncedTime
+			}
+		} else {
+			now := metav1.Now()
+			item.LastSyncedTime = &now
+		}
+		items = append(items, item)
+	}
+
+	if instance.Status != nil {
+		for _, previous := range instance.Status.Items {
+			if _, ok := desiredPaths[previous.Path]; ok {
+				continue
+			}
+			r.Log.Info(fmt.Sprintf("Deleting item %s", previous.Path))
+			if err := r.deleteItem(previous); err != nil {
+				// keep the item in the status so that the delete is retried
+				previous.Error = fmt.Sprintf("failed to delete item: %v", err)
+				items = append(items, previous)
+			}
+		}
+	}
+
+	var synced, failed int32
+	for _, item := range items {
+		if item.Error != "" {
+			failed++
+		} else {
+			synced++
+		}
+	}
+	now := metav1.Now()
+	instance.Status = &databricksv1alpha1.WorkspaceDirectoryStatus{
+		Items:          items,
+		SourceVersion:  sourceVersion,
+		SyncedItems:    synced,
+		FailedItems:    failed,
+		LastSyncedTime: &now,
+	}
+	if err := r.Update(context.Background(), instance); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d items failed to sync", failed, len(items))
+	}
+	return nil
+}
+
+// importItem creates the parent folders of the notebook and imports it
+func (r *WorkspaceDirectoryReconciler) importItem(item databricksv1alpha1.WorkspaceDirectoryItemStatus, data []byte, createdDirectories map[string]bool) error {
+	if parent := path.Dir(item.Path); !createdDirectories[parent] {
+		execution := NewExecution("workspacedirectories", "mkdirs")
+		err := r.APIClient.Workspace().Mkdirs(parent)
+		execution.Finish(err)
+		if err != nil {
+			return err
+		}
+		createdDirectories[parent] = true
+	}
+
+	overwrite := true
+	if item.Format == dbmodels.ExportFormatDbc {
+		// DBC archives can hold a whole folder, so they cannot be overwritten and are replaced instead
+		if err := r.deleteItem(item); err != nil {
+			return err
+		}
+		overwrite = false
+	}
+
+	r.Log.Info(fmt.Sprintf("Importing item %s", item.Path))
+	execution := NewExecution("workspacedirectories", "import")
+	err := r.APIClient.Workspace().Import(item.Path, item.Format, item.Language, data, overwrite)
+	execution.Finish(err)
+	return err
+}
+
+func (r *WorkspaceDirectoryReconciler) deleteItem(item databricksv1alpha1.WorkspaceDirectoryItemStatus) error {
+	execution := NewExecution("workspacedirectories", "delete")
+	err := r.APIClient.Workspace().Delete(item.Path, item.Format == dbmodels.ExportFormatDbc)
+	execution.Finish(err)
+	if err != nil && isResourceNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// readSources reads the files of all config maps and the git snapshot, sorted by path
+func (r *WorkspaceDirectoryReconciler) readSources(instance *databricksv1alpha1.WorkspaceDirectory) ([]notebookFile, error) {
+	var files []notebookFile
+	for _, source := range instance.Spec.ConfigMaps {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(context.Background(), types.NamespacedName{Namespace: instance.Namespace, Name: source.Name}, configMap); err != nil {
+			return nil, fmt.Errorf("error when reading config map %s: %v", source.Name, err)
+		}
+		for key, value := range configMap.Data {
+			files = append(files, notebookFile{sourcePath: source.Prefix + key, data: []byte(value)})
+		}
+		for key, value := range configMap.BinaryData {
+			files = append(files, notebookFile{sourcePath: source.Prefix + key, data: value})
+		}
+	}
+
+	if snapshot := instance.Spec.GitSnapshot; snapshot != nil {
+		archive, err := downloadContent(&snapshot.Archive)
+		if err != nil {
+			return nil, fmt.Errorf("error when downloading git snapshot: %v", err)
+		}
+		snapshotFiles, err := extractNotebooks(archive, int(snapshot.StripComponents), snapshot.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error when extracting git snapshot: %v", err)
+		}
+		files = append(files, snapshotFiles...)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].sourcePath < files[j].sourcePath })
+	return files, nil
+}
+
+// extractNotebooks returns the notebooks in a gzipped tar archive. Leading directories are
+// stripped from the paths first, then only files under directory are returned, relative to it.
+func extractNotebooks(archive []byte, stripComponents int, directory string) ([]notebookFile, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	prefix := ""
+	if directory = strings.Trim(path.Clean("/"+directory), "/"); directory != "" {
+		prefix = directory + "/"
+	}
+
+	var files []notebookFile
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimPrefix(path.Clean("/"+header.Name), "/"), "/")
+		if len(parts) <= stripComponents {
+			continue
+		}
+		name := strings.Join(parts[stripComponents:], "/")
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		name = strings.TrimPrefix(name, prefix)
+		if _, _, ok := notebookFormat(name); !ok {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, notebookFile{sourcePath: name, data: data})
+	}
+	return files, nil
+}
+
+func (r *WorkspaceDirectoryReconciler) delete(instance *databricksv1alpha1.WorkspaceDirectory) error {
+	r.Log.Info(fmt.Sprintf("Deleting directory %s", instance.GetName()))
+
+	if instance.Status == nil {
+		return nil
+	}
+
+	for _, item := range instance.Status.Items {
+		if err := r.deleteItem(item); err != nil {
+			return fmt.Errorf("error when deleting item %s: %v", item.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE..
+*/
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func gzipTar(files map[string]string) []byte {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		_ = tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		_, _ = tarWriter.Write([]byte(content))
+	}
+	_ = tarWriter.Close()
+	_ = gzipWriter.Close()
+	return buffer.Bytes()
+}
+
+var _ = Describe("WorkspaceDirectory Controller", func() {
+
+	const timeout = time.Second * 30
+	const interval = time.Second * 1
+
+	BeforeEach(func() {
+		// Add any setup steps that needs to be executed before each test
+	})
+
+	AfterEach(func() {
+		// Add any teardown steps that needs to be executed after each test
+	})
+
+	Context("Notebook formats", func() {
+		It("Should infer the language and format from the extension", func() {
+			language, format, ok := notebookFormat("etl/load.py")
+			Expect(ok).To(BeTrue())
+			Expect(language).To(Equal(dbmodels.Language(dbmodels.LanguagePython)))
+			Expect(format).To(Equal(dbmodels.ExportFormat(dbmodels.ExportFormatSource)))
+
+			language, _, _ = notebookFormat("report.R")
+			Expect(language).To(Equal(dbmodels.Language(dbmodels.LanguageR)))
+
+			language, _, _ = notebookFormat("query.sql")
+			Expect(language).To(Equal(dbmodels.Language(dbmodels.LanguageSQL)))
+
+			language, _, _ = notebookFormat("job.scala")
+			Expect(language).To(Equal(dbmodels.Language(dbmodels.LanguageScala)))
+
+			_, format, _ = notebookFormat("analysis.ipynb")
+			Expect(format).To(Equal(dbmodels.ExportFormat(dbmodels.ExportFormatJupyter)))
+
+			_, format, _ = notebookFormat("archive.dbc")
+			Expect(format).To(Equal(dbmodels.ExportFormat(dbmodels.ExportFormatDbc)))
+
+			_, _, ok = notebookFormat("README.md")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("Git snapshots", func() {
+		It("Should extract notebooks under the path", func() {
+			archive := gzipTar(map[string]string{
+				"repo-abc123/README.md":              "# repo",
+				"repo-abc123/notebooks/main.py":      "print('main')",
+				"repo-abc123/notebooks/lib/util.sql": "select 1",
+				"repo-abc123/tests/test_main.py":     "print('test')",
+			})
+
+			files, err := extractNotebooks(archive, 1, "notebooks")
+			Expect(err).ToNot(HaveOccurred())
+
+			sourcePaths := map[string]string{}
+			for _, file := range files {
+				sourcePaths[file.sourcePath] = string(file.data)
+			}
+			Expect(sourcePaths).To(Equal(map[string]string{
+				"main.py":      "print('main')",
+				"lib/util.sql": "select 1",
+			}))
+
+			files, err = extractNotebooks(archive, 1, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(HaveLen(3))
+
+			_, err = extractNotebooks([]byte("not an archive"), 0, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Source version", func() {
+		It("Should change with the path and the prefixes", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "t-notebooks", Namespace: "default", ResourceVersion: "7"},
+				Data:       map[string]string{"main.py": "print('main')"},
+			}
+			instance := &databricksv1alpha1.WorkspaceDirectory{
+				ObjectMeta: metav1.ObjectMeta{Name: "t-workspace-directory", Namespace: "default"},
+				Spec: &databricksv1alpha1.WorkspaceDirectorySpec{
+					Path:       "/Shared/etl",
+					ConfigMaps: []databricksv1alpha1.WorkspaceDirectoryConfigMapSource{{Name: configMap.GetName(), Prefix: "jobs/"}},
+				},
+			}
+			reconciler := &WorkspaceDirectoryReconciler{
+				Client: newFakeClient(configMap),
+				Log:    ctrl.Log.WithName("controllers").WithName("WorkspaceDirectory"),
+			}
+
+			version, err := reconciler.getSourceVersion(instance)
+			Expect(err).ToNot(HaveOccurred())
+
+			instance.Spec.ConfigMaps[0].Prefix = "batch/"
+			prefixVersion, err := reconciler.getSourceVersion(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prefixVersion).ToNot(Equal(version))
+
+			instance.Spec.Path = "/Shared/reports"
+			pathVersion, err := reconciler.getSourceVersion(instance)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pathVersion).ToNot(Equal(prefixVersion))
+
+			instance.Status = &databricksv1alpha1.WorkspaceDirectoryStatus{LastSyncedTime: &metav1.Time{}, SourceVersion: prefixVersion}
+			Expect(instance.IsUpToDate(pathVersion)).To(BeFalse())
+		})
+	})
+
+	Context("Directory from a config map", func() {
+		It("Should import and prune notebooks", func() {
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "t-notebooks" + randomStringWithCharset(10, charset),
+					Namespace: "default",
+				},
+				Data: map[string]string{
+					"main.py":   "print('main')",
+					"query.sql": "select 1",
+					"notes.txt": "not a notebook",
+				},
+			}
+			Expect(k8sClient.Create(context.Background(), configMap)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), configMap)).Should(Succeed())
+			}()
+
+			key := types.NamespacedName{
+				Name:      "t-workspace-directory" + randomStringWithCharset(10, charset),
+				Namespace: "default",
+			}
+
+			created := &databricksv1alpha1.WorkspaceDirectory{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: &databricksv1alpha1.WorkspaceDirectorySpec{
+					Path: "/test-workspace-directory-" + key.Name,
+					ConfigMaps: []databricksv1alpha1.WorkspaceDirectoryConfigMapSource{
+						{Name: configMap.GetName(), Prefix: "jobs/"},
+					},
+				},
+			}
+
+			// Create
+			Expect(k8sClient.Create(context.Background(), created)).Should(Succeed())
+
+			By("Expecting two notebooks to be synced")
+			Eventually(func() int32 {
+				f := &databricksv1alpha1.WorkspaceDirectory{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				if !f.IsSubmitted() {
+					return 0
+				}
+				return f.Status.SyncedItems
+			}, timeout, interval).Should(Equal(int32(2)))
+
+			fetched := &databricksv1alpha1.WorkspaceDirectory{}
+			Expect(k8sClient.Get(context.Background(), key, fetched)).Should(Succeed())
+			Expect(fetched.GetItemStatus(created.Spec.Path + "/jobs/query").Language).To(Equal(dbmodels.Language(dbmodels.LanguageSQL)))
+
+			// Remove a notebook from the source
+			updated := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: configMap.GetName(), Namespace: "default"}, updated)).Should(Succeed())
+			delete(updated.Data, "query.sql")
+			Expect(k8sClient.Update(context.Background(), updated)).Should(Succeed())
+
+			By("Expecting the removed notebook to be pruned")
+			Eventually(func() bool {
+				f := &databricksv1alpha1.WorkspaceDirectory{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				return f.GetItemStatus(created.Spec.Path+"/jobs/query") == nil
+			}, timeout, interval).Should(BeTrue())
+
+			// Delete
+			By("Expecting to delete successfully")
+			Eventually(func() error {
+				f := &databricksv1alpha1.WorkspaceDirectory{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				return k8sClient.Delete(context.Background(), f)
+			}, timeout, interval).Should(Succeed())
+
+			By("Expecting to delete finish")
+			Eventually(func() error {
+				f := &databricksv1alpha1.WorkspaceDirectory{}
+				return k8sClient.Get(context.Background(), key, f)
+			}, timeout, interval).ShouldNot(Succeed())
+		})
+	})
+})
@@ -27,6 +27,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -34,9 +35,14 @@ import (
 	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 )
@@ -90,9 +96,23 @@ func (r *WorkspaceItemReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		return ctrl.Result{}, nil
 	}
 
-	if !instance.IsSubmitted() || !instance.IsUpToDate() {
+	if instance.IsExport() {
+		return r.reconcileExport(instance)
+	}
+
+	upToDate := instance.IsUpToDate()
+	if instance.HasContentFrom() {
+		sourceVersion, err := getContentVersion(r.Client, instance.Namespace, instance.Spec.ContentFrom)
+		if err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Resolving content", fmt.Sprintf("Failed to resolve content source: %s", err))
+			return ctrl.Result{}, fmt.Errorf("error when resolving workspace item content source: %v", err)
+		}
+		upToDate = instance.IsSourceUpToDate(sourceVersion)
+	}
+
+	if !instance.IsSubmitted() || !upToDate {
 		r.Log.Info(fmt.Sprintf("Submit for %v", req.NamespacedName))
-		if err := r.submit(instance); err != nil {
+		if err := r.submit(instance, nil); err != nil {
 			r.Recorder.Event(instance, corev1.EventTypeWarning, "Submitting object", fmt.Sprintf("Failed to submit object: %s", err))
 			return ctrl.Result{}, fmt.Errorf("error when submitting workspace item: %v", err)
 		}
@@ -100,12 +120,98 @@ func (r *WorkspaceItemReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 		return ctrl.Result{}, nil
 	}
 
+	if instance.GetDriftDetection().IsEnabled() {
+		return r.detectDrift(instance)
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// detectDrift verifies the item in the workspace when it is due and handles any drift according to the policy
+func (r *WorkspaceItemReconciler) detectDrift(instance *databricksv1alpha1.WorkspaceItem) (ctrl.Result, error) {
+	detection := instance.GetDriftDetection()
+	if wait := detection.NextVerification(instance.Status.LastVerifiedTime, time.Now()); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	r.Log.Info(fmt.Sprintf("Verify for %s", instance.GetName()))
+	drift, err := r.verify(instance)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Verifying object", fmt.Sprintf("Failed to verify object: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when verifying workspace item: %v", err)
+	}
+
+	if drift != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Drifted", drift.message)
+		if detection.IsEnforced() {
+			if err := r.submit(instance, drift); err != nil {
+				r.Recorder.Event(instance, corev1.EventTypeWarning, "Submitting object", fmt.Sprintf("Failed to submit object: %s", err))
+				return ctrl.Result{}, fmt.Errorf("error when submitting workspace item: %v", err)
+			}
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "Enforced", "Object is submitted again")
+			return ctrl.Result{RequeueAfter: detection.Interval()}, nil
+		}
+	}
+
+	now := metav1.Now()
+	instance.Status.LastVerifiedTime = &now
+	if drift != nil {
+		instance.Status.LastDriftTime = &now
+	}
+	instance.Status.Conditions = databricksv1alpha1.SetCondition(instance.Status.Conditions, inSyncCondition(drift))
+	if err := r.Update(context.Background(), instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: detection.Interval()}, nil
+}
+
+// reconcileExport mirrors the item in the workspace into its config map when an export is due
+func (r *WorkspaceItemReconciler) reconcileExport(instance *databricksv1alpha1.WorkspaceItem) (ctrl.Result, error) {
+	if wait := instance.NextExport(time.Now()); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	r.Log.Info(fmt.Sprintf("Export for %s", instance.GetName()))
+	modified, err := r.exportToConfigMap(instance)
+	if err != nil {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, "Exporting object", fmt.Sprintf("Failed to export object: %s", err))
+		return ctrl.Result{}, fmt.Errorf("error when exporting workspace item: %v", err)
+	}
+	if modified {
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Exported", fmt.Sprintf("Changed content is written to config map %s", instance.Spec.ExportTo.ConfigMapName))
+	}
+
+	return ctrl.Result{RequeueAfter: instance.GetExportInterval()}, nil
+}
+
 // SetupWithManager adds the controller manager
 func (r *WorkspaceItemReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&databricksv1alpha1.WorkspaceItem{}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapContentFrom),
+		}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(r.mapContentFrom),
+		}).
 		Complete(r)
 }
+
+// mapContentFrom returns the WorkspaceItems that read their content from the config map or secret in the event
+func (r *WorkspaceItemReconciler) mapContentFrom(object handler.MapObject) []reconcile.Request {
+	var items databricksv1alpha1.WorkspaceItemList
+	if err := r.List(context.Background(), &items, client.InNamespace(object.Meta.GetNamespace())); err != nil {
+		r.Log.Info(fmt.Sprintf("Failed to list WorkspaceItems for %s: %v", object.Meta.GetName(), err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, item := range items.Items {
+		if item.Spec != nil && referencesContentObject(item.Spec.ContentFrom, object) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: item.GetName(), Namespace: item.GetNamespace()},
+			})
+		}
+	}
+	return requests
+}
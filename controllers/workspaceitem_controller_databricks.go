@@ -29,20 +29,31 @@ import (
 	"encoding/base64"
 	"fmt"
 	"time"
+	"unicode/utf8"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
-func (r *WorkspaceItemReconciler) submit(instance *databricksv1alpha1.WorkspaceItem) error {
+// submit imports the item. Unless it is correcting a drift, the import is skipped when the content is unchanged.
+func (r *WorkspaceItemReconciler) submit(instance *databricksv1alpha1.WorkspaceItem, drift *remoteDrift) error {
 	r.Log.Info(fmt.Sprintf("Create item %s", instance.GetName()))
 
-	if instance.Spec == nil || len(instance.Spec.Content) <= 0 {
-		return fmt.Errorf("Workspace Content is empty")
-	}
-	data, err := base64.StdEncoding.DecodeString(instance.Spec.Content)
+	data, sourceVersion, err := r.getContent(instance)
 	if err != nil {
 		return err
 	}
+	hash := databricksv1alpha1.HashContent(data)
+
+	if drift == nil && instance.IsSubmitted() && instance.Status.ObjectHash == hash {
+		// the source has changed but its content has not, so there is nothing to import
+		instance.Status.SourceVersion = sourceVersion
+		return r.Update(context.Background(), instance)
+	}
 
 	execution := NewExecution("workspaceitems", "import")
 	err = r.APIClient.Workspace().Import(instance.Spec.Path, instance.Spec.Format, instance.Spec.Language, data, true)
@@ -61,17 +72,191 @@ func (r *WorkspaceItemReconciler) submit(instance *databricksv1alpha1.WorkspaceI
 		return err
 	}
 
-	instance.Status = &databricksv1alpha1.WorkspaceItemStatus{
-		ObjectInfo: &objectInfo,
-		ObjectHash: instance.GetHash(),
+	if instance.Status == nil {
+		instance.Status = &databricksv1alpha1.WorkspaceItemStatus{}
+	}
+	instance.Status.ObjectInfo = &objectInfo
+	instance.Status.ObjectHash = hash
+	instance.Status.SourceVersion = sourceVersion
+
+	if detection := instance.GetDriftDetection(); detection.IsEnabled() {
+		if detection.VerifyContent {
+			// notebooks are not exported byte for byte as imported, so keep the hash of the export to compare against
+			remote, err := r.export(instance.Spec.Path, dbmodels.ExportFormatSource)
+			if err != nil {
+				return err
+			}
+			instance.Status.RemoteHash = databricksv1alpha1.HashContent(remote)
+		}
+		now := metav1.Now()
+		instance.Status.LastVerifiedTime = &now
+		if drift != nil {
+			instance.Status.LastDriftTime = &now
+			instance.Status.Conditions = databricksv1alpha1.SetCondition(instance.Status.Conditions, driftCorrectedCondition(drift))
+		} else {
+			instance.Status.Conditions = databricksv1alpha1.SetCondition(instance.Status.Conditions, inSyncCondition(nil))
+		}
 	}
 
 	return r.Update(context.Background(), instance)
 }
 
+// getContent returns the content of the item and, for items using ContentFrom, the version of the source
+func (r *WorkspaceItemReconciler) getContent(instance *databricksv1alpha1.WorkspaceItem) ([]byte, string, error) {
+	if instance.HasContentFrom() {
+		return getContent(r.Client, instance.Namespace, instance.Spec.ContentFrom)
+	}
+	if instance.Spec == nil || len(instance.Spec.Content) <= 0 {
+		return nil, "", fmt.Errorf("Workspace Content is empty")
+	}
+	data, err := base64.StdEncoding.DecodeString(instance.Spec.Content)
+	return data, "", err
+}
+
+// verify checks that the item still exists in the workspace and optionally that its content is unchanged
+func (r *WorkspaceItemReconciler) verify(instance *databricksv1alpha1.WorkspaceItem) (*remoteDrift, error) {
+	path := instance.Status.ObjectInfo.Path
+
+	execution := NewExecution("workspaceitems", "get_status")
+	_, err := r.APIClient.Workspace().GetStatus(path)
+	execution.Finish(err)
+	if err != nil {
+		if isResourceNotFound(err) {
+			return &remoteDrift{
+				reason:  databricksv1alpha1.DriftReasonMissing,
+				message: fmt.Sprintf("Item %s no longer exists", path),
+			}, nil
+		}
+		return nil, err
+	}
+
+	if instance.GetDriftDetection().VerifyContent && instance.Status.RemoteHash != "" {
+		remote, err := r.export(path, dbmodels.ExportFormatSource)
+		if err != nil {
+			return nil, err
+		}
+		if hash := databricksv1alpha1.HashContent(remote); hash != instance.Status.RemoteHash {
+			return &remoteDrift{
+				reason:  databricksv1alpha1.DriftReasonContentMismatch,
+				message: fmt.Sprintf("Item %s has hash %s, expected %s", path, hash, instance.Status.RemoteHash),
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *WorkspaceItemReconciler) export(path string, format dbmodels.ExportFormat) ([]byte, error) {
+	execution := NewExecution("workspaceitems", "export")
+	data, err := r.APIClient.Workspace().Export(path, format, false)
+	execution.Finish(err)
+	return data, err
+}
+
+// exportToConfigMap exports the item and writes it to the config map if its content changed.
+// It returns true if the content differs from the previous export.
+func (r *WorkspaceItemReconciler) exportToConfigMap(instance *databricksv1alpha1.WorkspaceItem) (bool, error) {
+	if instance.Spec.ExportTo == nil || instance.Spec.ExportTo.ConfigMapName == "" {
+		return false, fmt.Errorf("export_to.config_map_name must be set to export an item")
+	}
+	if err := instance.ValidateExportKey(); err != nil {
+		return false, err
+	}
+
+	execution := NewExecution("workspaceitems", "get_status")
+	objectInfo, err := r.APIClient.Workspace().GetStatus(instance.Spec.Path)
+	execution.Finish(err)
+	if err != nil {
+		return false, err
+	}
+
+	format := instance.Spec.Format
+	if format == "" {
+		format = dbmodels.ExportFormatSource
+	}
+	data, err := r.export(instance.Spec.Path, format)
+	if err != nil {
+		return false, err
+	}
+	hash := databricksv1alpha1.HashContent(data)
+
+	if instance.Status == nil {
+		instance.Status = &databricksv1alpha1.WorkspaceItemStatus{}
+	}
+	now := metav1.Now()
+	modified := hash != instance.Status.ObjectHash
+	if modified || instance.Status.LastModifiedTime == nil {
+		instance.Status.LastModifiedTime = &now
+	}
+
+	if err := r.writeConfigMap(instance, data, hash); err != nil {
+		return false, err
+	}
+
+	instance.Status.ObjectInfo = &objectInfo
+	instance.Status.ObjectHash = hash
+	instance.Status.LastExportedTime = &now
+	return modified, r.Update(context.Background(), instance)
+}
+
+// writeConfigMap writes the exported content with its hash and modification time, creating the
+// config map if it does not exist. The config map is left untouched if it already has the content.
+// The annotations are qualified with the key so that items exported to the same config map do not
+// overwrite each other's.
+func (r *WorkspaceItemReconciler) writeConfigMap(instance *databricksv1alpha1.WorkspaceItem, data []byte, hash string) error {
+	key := instance.GetExportKey()
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(context.Background(), types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.ExportTo.ConfigMapName}, configMap)
+	exists := err == nil
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	hashAnnotation := instance.GetExportAnnotation(databricksv1alpha1.WorkspaceItemContentHashAnnotation)
+	if exists && configMap.GetAnnotations()[hashAnnotation] == hash {
+		return nil
+	}
+
+	configMap.Name = instance.Spec.ExportTo.ConfigMapName
+	configMap.Namespace = instance.Namespace
+	annotations := configMap.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[hashAnnotation] = hash
+	annotations[instance.GetExportAnnotation(databricksv1alpha1.WorkspaceItemLastModifiedAnnotation)] = instance.Status.LastModifiedTime.UTC().Format(time.RFC3339)
+	annotations[instance.GetExportAnnotation(databricksv1alpha1.WorkspaceItemSourcePathAnnotation)] = instance.Spec.Path
+	configMap.SetAnnotations(annotations)
+
+	// text goes in Data so that it can be diffed, anything else such as DBC archives in BinaryData
+	if utf8.Valid(data) {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[key] = string(data)
+		delete(configMap.BinaryData, key)
+	} else {
+		if configMap.BinaryData == nil {
+			configMap.BinaryData = map[string][]byte{}
+		}
+		configMap.BinaryData[key] = data
+		delete(configMap.Data, key)
+	}
+
+	if exists {
+		return r.Update(context.Background(), configMap)
+	}
+	return r.Create(context.Background(), configMap)
+}
+
 func (r *WorkspaceItemReconciler) delete(instance *databricksv1alpha1.WorkspaceItem) error {
 	r.Log.Info(fmt.Sprintf("Deleting item %s", instance.GetName()))
 
+	if instance.IsExport() {
+		// exported items are owned by whoever edits them in the workspace
+		return nil
+	}
+
 	if instance.Status == nil || instance.Status.ObjectInfo == nil {
 		return nil
 	}
@@ -26,13 +26,17 @@ package controllers
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 var _ = Describe("WorkspaceItem Controller", func() {
@@ -105,4 +109,197 @@ var _ = Describe("WorkspaceItem Controller", func() {
 			}, timeout, interval).ShouldNot(Succeed())
 		})
 	})
+
+	Context("Workspace Item in export mode", func() {
+		It("Should write changed content to the config map", func() {
+			databricks := newFakeDatabricks()
+			defer databricks.Close()
+			databricks.notebookPath = "/Users/someone/analysis"
+			databricks.notebookContent = []byte("print(1)")
+
+			key := types.NamespacedName{Name: "t-export", Namespace: "default"}
+			instance := &databricksv1alpha1.WorkspaceItem{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: &databricksv1alpha1.WorkspaceItemSpec{
+					Path:      databricks.notebookPath,
+					Direction: databricksv1alpha1.WorkspaceItemDirectionExport,
+					ExportTo:  &databricksv1alpha1.WorkspaceItemExportTarget{ConfigMapName: "t-export-notebooks"},
+				},
+			}
+
+			reconciler := &WorkspaceItemReconciler{
+				Client:    newFakeClient(instance),
+				Log:       ctrl.Log.WithName("controllers").WithName("WorkspaceItem"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+
+			_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key}) // finalizer
+			Expect(err).ToNot(HaveOccurred())
+			result, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+
+			configMap := &corev1.ConfigMap{}
+			configMapKey := types.NamespacedName{Name: "t-export-notebooks", Namespace: "default"}
+			Expect(reconciler.Get(context.Background(), configMapKey, configMap)).To(Succeed())
+			Expect(configMap.Data["analysis"]).To(Equal("print(1)"))
+			Expect(configMap.Annotations["databricks.microsoft.com/analysis.content-hash"]).To(Equal(databricksv1alpha1.HashContent([]byte("print(1)"))))
+			Expect(configMap.Annotations["databricks.microsoft.com/analysis.source-path"]).To(Equal(databricks.notebookPath))
+
+			By("Not exporting again before the interval")
+			result, err = reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			By("Writing edits made in the workspace")
+			databricks.notebookContent = []byte("print(2)")
+			fetched := &databricksv1alpha1.WorkspaceItem{}
+			Expect(reconciler.Get(context.Background(), key, fetched)).To(Succeed())
+			modified, err := reconciler.exportToConfigMap(fetched)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(modified).To(BeTrue())
+
+			Expect(reconciler.Get(context.Background(), configMapKey, configMap)).To(Succeed())
+			Expect(configMap.Data["analysis"]).To(Equal("print(2)"))
+
+			modified, err = reconciler.exportToConfigMap(fetched)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(modified).To(BeFalse())
+
+			By("Leaving the notebook in the workspace on delete")
+			Expect(reconciler.delete(fetched)).To(Succeed())
+		})
+
+		It("Should keep the annotations of items sharing a config map apart", func() {
+			databricks := newFakeDatabricks()
+			defer databricks.Close()
+			databricks.notebookPath = "/Users/someone/analysis"
+			databricks.notebookContent = []byte("print(1)")
+
+			newItem := func(name, key string) *databricksv1alpha1.WorkspaceItem {
+				return &databricksv1alpha1.WorkspaceItem{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: &databricksv1alpha1.WorkspaceItemSpec{
+						Path:      databricks.notebookPath,
+						Direction: databricksv1alpha1.WorkspaceItemDirectionExport,
+						ExportTo:  &databricksv1alpha1.WorkspaceItemExportTarget{ConfigMapName: "t-shared-notebooks", Key: key},
+					},
+				}
+			}
+			first := newItem("t-first", "first.py")
+			second := newItem("t-second", "second.py")
+
+			reconciler := &WorkspaceItemReconciler{
+				Client:    newFakeClient(first, second),
+				Log:       ctrl.Log.WithName("controllers").WithName("WorkspaceItem"),
+				Recorder:  record.NewFakeRecorder(100),
+				APIClient: databricks.client(),
+			}
+
+			_, err := reconciler.exportToConfigMap(first)
+			Expect(err).ToNot(HaveOccurred())
+			databricks.notebookContent = []byte("print(2)")
+			_, err = reconciler.exportToConfigMap(second)
+			Expect(err).ToNot(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			configMapKey := types.NamespacedName{Name: "t-shared-notebooks", Namespace: "default"}
+			Expect(reconciler.Get(context.Background(), configMapKey, configMap)).To(Succeed())
+			Expect(configMap.Data).To(Equal(map[string]string{"first.py": "print(1)", "second.py": "print(2)"}))
+			Expect(configMap.Annotations["databricks.microsoft.com/first.py.content-hash"]).To(Equal(databricksv1alpha1.HashContent([]byte("print(1)"))))
+			Expect(configMap.Annotations["databricks.microsoft.com/second.py.content-hash"]).To(Equal(databricksv1alpha1.HashContent([]byte("print(2)"))))
+
+			By("Writing a change to the first item even though the second has the same content")
+			_, err = reconciler.exportToConfigMap(first)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reconciler.Get(context.Background(), configMapKey, configMap)).To(Succeed())
+			Expect(configMap.Data["first.py"]).To(Equal("print(2)"))
+
+			By("Rejecting a key too long to qualify the annotations with")
+			first.Spec.ExportTo.Key = strings.Repeat("k", 64)
+			_, err = reconciler.exportToConfigMap(first)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Workspace Item from a config map", func() {
+		It("Should import again when the config map changes", func() {
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "t-notebook" + randomStringWithCharset(10, charset),
+					Namespace: "default",
+				},
+				Data: map[string]string{"notebook.scala": "1+1"},
+			}
+			Expect(k8sClient.Create(context.Background(), configMap)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(context.Background(), configMap)).Should(Succeed())
+			}()
+
+			key := types.NamespacedName{
+				Name:      "t-workspace-item-from-config-map" + randomStringWithCharset(10, charset),
+				Namespace: "default",
+			}
+
+			created := &databricksv1alpha1.WorkspaceItem{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+				},
+				Spec: &databricksv1alpha1.WorkspaceItemSpec{
+					ContentFrom: &databricksv1alpha1.ContentFrom{
+						ConfigMapKeyRef: &databricksv1alpha1.ContentKeyRef{
+							Name: configMap.GetName(),
+							Key:  "notebook.scala",
+						},
+					},
+					Path:     "/test-notebook-from-config-map",
+					Language: "SCALA",
+					Format:   "SOURCE",
+				},
+			}
+
+			// Create
+			Expect(k8sClient.Create(context.Background(), created)).Should(Succeed())
+
+			By("Expecting the hash to be computed over the config map content")
+			Eventually(func() string {
+				f := &databricksv1alpha1.WorkspaceItem{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				if !f.IsSubmitted() {
+					return ""
+				}
+				return f.Status.ObjectHash
+			}, timeout, interval).Should(Equal(databricksv1alpha1.HashContent([]byte("1+1"))))
+
+			// Update the source
+			updated := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(context.Background(), types.NamespacedName{Name: configMap.GetName(), Namespace: "default"}, updated)).Should(Succeed())
+			updated.Data["notebook.scala"] = "1+2"
+			Expect(k8sClient.Update(context.Background(), updated)).Should(Succeed())
+
+			By("Expecting the item to be imported again")
+			Eventually(func() string {
+				f := &databricksv1alpha1.WorkspaceItem{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				return f.Status.ObjectHash
+			}, timeout, interval).Should(Equal(databricksv1alpha1.HashContent([]byte("1+2"))))
+
+			// Delete
+			By("Expecting to delete successfully")
+			Eventually(func() error {
+				f := &databricksv1alpha1.WorkspaceItem{}
+				_ = k8sClient.Get(context.Background(), key, f)
+				return k8sClient.Delete(context.Background(), f)
+			}, timeout, interval).Should(Succeed())
+
+			By("Expecting to delete finish")
+			Eventually(func() error {
+				f := &databricksv1alpha1.WorkspaceItem{}
+				return k8sClient.Get(context.Background(), key, f)
+			}, timeout, interval).ShouldNot(Succeed())
+		})
+	})
 })
@@ -0,0 +1,177 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const usage = `kubectl databricks works with the Runs and Djobs of the Azure Databricks operator
+
+Usage:
+  kubectl databricks logs <run> [-f]                  print the state, error and error trace of a run, -f follows it until it terminates
+  kubectl databricks output <run>                     print the whole notebook result of a run
+  kubectl databricks cancel <run>                     cancel a run
+  kubectl databricks rerun <run>                      submit a terminated run again
+  kubectl databricks run-now <djob> [-p name=value]   trigger a run of a Djob, -p sets notebook parameters
+  kubectl databricks open <run> [--print]             open the page of a run in the DataBricks workspace
+
+Flags:
+  -n, --namespace string        namespace of the objects, it defaults to the namespace of the current context
+      --kubeconfig string       path to the kubeconfig file
+      --operator-namespace      namespace of the dbrickssettings secret the DataBricks host and token are read from,
+                                unless DATABRICKS_HOST and DATABRICKS_TOKEN are set
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(out, usage)
+		return nil
+	}
+	command := args[0]
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(out, usage) }
+	var namespace, kubeconfig, operatorNamespace string
+	var follow, printOnly bool
+	parameters := parameterFlag{}
+	flags.StringVar(&namespace, "namespace", "", "")
+	flags.StringVar(&namespace, "n", "", "")
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "")
+	flags.StringVar(&operatorNamespace, "operator-namespace", defaultOperatorNamespace, "")
+	flags.BoolVar(&follow, "follow", false, "")
+	flags.BoolVar(&follow, "f", false, "")
+	flags.BoolVar(&printOnly, "print", false, "")
+	flags.Var(parameters, "param", "")
+	flags.Var(parameters, "p", "")
+	names, err := parseArgs(flags, args[1:])
+	if err != nil {
+		return err
+	}
+	if len(names) != 1 {
+		return fmt.Errorf("%s expects exactly one name, see kubectl databricks --help", command)
+	}
+
+	p, err := newPlugin(kubeconfig, namespace, operatorNamespace, out)
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "logs":
+		return p.logs(names[0], follow)
+	case "output":
+		return p.output(names[0])
+	case "cancel":
+		return p.cancel(names[0])
+	case "rerun":
+		return p.rerun(names[0])
+	case "run-now":
+		return p.runNow(names[0], parameters)
+	case "open":
+		return p.open(names[0], printOnly)
+	}
+	return fmt.Errorf("unknown command %s, see kubectl databricks --help", command)
+}
+
+// parseArgs parses the flags wherever they appear and returns the positional arguments
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// parameterFlag collects name=value flags
+type parameterFlag map[string]string
+
+func (p parameterFlag) String() string {
+	return fmt.Sprint(map[string]string(p))
+}
+
+func (p parameterFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("parameter %s should be name=value", value)
+	}
+	p[parts[0]] = parts[1]
+	return nil
+}
+
+func newPlugin(kubeconfig, namespace, operatorNamespace string, out io.Writer) (*plugin, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.Context.Namespace = namespace
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = databricksv1alpha1.AddToScheme(scheme)
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	return &plugin{
+		client:            c,
+		namespace:         namespace,
+		operatorNamespace: operatorNamespace,
+		out:               out,
+		pollInterval:      5 * time.Second,
+	}, nil
+}
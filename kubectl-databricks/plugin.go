@@ -0,0 +1,256 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	"github.com/microsoft/azure-databricks-operator/controllers/jobsapi"
+	db "github.com/xinsnake/databricks-sdk-golang"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultOperatorNamespace is where the operator is deployed by make deploy
+const defaultOperatorNamespace = "azure-databricks-operator-system"
+
+// plugin resolves the names of Runs and Djobs through their status and calls DataBricks for what the status does not hold
+type plugin struct {
+	client            client.Client
+	namespace         string
+	operatorNamespace string
+	out               io.Writer
+	pollInterval      time.Duration
+	// jobs is set on the first call to DataBricks
+	jobs *jobsapi.Client
+}
+
+// logs prints the state of the run and, once it terminated, its error and error trace
+func (p *plugin) logs(name string, follow bool) error {
+	run, err := p.getRun(name)
+	if err != nil {
+		return err
+	}
+
+	lastState := ""
+	for {
+		output, err := p.getRunOutput(run.Status.Metadata.RunID)
+		if err != nil {
+			return err
+		}
+		current := &databricksv1alpha1.Run{Status: &databricksv1alpha1.RunStatus{JobsRunsGetOutputResponse: output.JobsRunsGetOutputResponse}}
+		if state := formatState(output.Metadata.State); state != lastState {
+			fmt.Fprintf(p.out, "%s %s\n", time.Now().Format(time.RFC3339), state)
+			lastState = state
+		}
+		if current.IsTerminated() || !follow {
+			if output.Error != "" {
+				fmt.Fprintf(p.out, "Error: %s\n", output.Error)
+			}
+			if output.ErrorTrace != "" {
+				fmt.Fprintln(p.out, output.ErrorTrace)
+			}
+			return nil
+		}
+		time.Sleep(p.pollInterval)
+	}
+}
+
+// output prints the notebook result of the run as returned by DataBricks, status only holds a capped copy
+func (p *plugin) output(name string) error {
+	run, err := p.getRun(name)
+	if err != nil {
+		return err
+	}
+	output, err := p.getRunOutput(run.Status.Metadata.RunID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(p.out, output.NotebookOutput.Result)
+	return nil
+}
+
+// cancel sets spec.cancel, the operator cancels the run in DataBricks
+func (p *plugin) cancel(name string) error {
+	run := &databricksv1alpha1.Run{}
+	if err := p.client.Get(context.Background(), p.key(name), run); err != nil {
+		return err
+	}
+	if run.Spec == nil {
+		run.Spec = &databricksv1alpha1.RunSpec{}
+	}
+	run.Spec.Cancel = true
+	if err := p.client.Update(context.Background(), run); err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "run/%s cancel requested\n", name)
+	return nil
+}
+
+// rerun bumps spec.attempt of a terminated run, the operator submits it again.
+// Mirrored runs follow a run started outside the operator, so they cannot be rerun.
+func (p *plugin) rerun(name string) error {
+	run, err := p.getRun(name)
+	if err != nil {
+		return err
+	}
+	if run.Spec == nil {
+		return fmt.Errorf("run %s has no spec", name)
+	}
+	if run.IsMirrored() {
+		return fmt.Errorf("run %s mirrors DataBricks run %d and cannot be rerun", name, run.Spec.MirrorRunID)
+	}
+	if !run.IsTerminated() {
+		return fmt.Errorf("run %s has not terminated", name)
+	}
+	run.Spec.Attempt++
+	if err := p.client.Update(context.Background(), run); err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "run/%s attempt %d requested\n", name, run.Spec.Attempt)
+	return nil
+}
+
+// runNow bumps spec.run_now.trigger of the Djob with the specified notebook parameters,
+// the operator creates a Run of the job for the trigger
+func (p *plugin) runNow(name string, parameters map[string]string) error {
+	job := &databricksv1alpha1.Djob{}
+	if err := p.client.Get(context.Background(), p.key(name), job); err != nil {
+		return err
+	}
+	if job.Spec == nil {
+		return fmt.Errorf("djob %s has no spec", name)
+	}
+
+	if job.Spec.RunNow == nil {
+		job.Spec.RunNow = &databricksv1alpha1.JobRunNow{}
+	}
+	job.Spec.RunNow.Trigger++
+	job.Spec.RunNow.Parameters = nil
+	if len(parameters) > 0 {
+		job.Spec.RunNow.Parameters = &dbmodels.RunParameters{NotebookParams: parameters}
+	}
+	if err := p.client.Update(context.Background(), job); err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "djob/%s triggered run/%s\n", name, job.GetTriggeredRunName(job.Spec.RunNow.Trigger))
+	return nil
+}
+
+// open opens the page of the run in the DataBricks workspace, or prints its URL
+func (p *plugin) open(name string, printOnly bool) error {
+	run, err := p.getRun(name)
+	if err != nil {
+		return err
+	}
+	runPageURL := run.Status.RunPageURL
+	if runPageURL == "" {
+		runPageURL = run.Status.Metadata.RunPageURL
+	}
+	if runPageURL == "" {
+		return fmt.Errorf("run %s has no run page yet", name)
+	}
+	if printOnly {
+		fmt.Fprintln(p.out, runPageURL)
+		return nil
+	}
+
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		command = exec.Command("open", runPageURL)
+	case "windows":
+		command = exec.Command("rundll32", "url.dll,FileProtocolHandler", runPageURL)
+	default:
+		command = exec.Command("xdg-open", runPageURL)
+	}
+	return command.Start()
+}
+
+func (p *plugin) key(name string) types.NamespacedName {
+	return types.NamespacedName{Namespace: p.namespace, Name: name}
+}
+
+// getRun returns the Run, which should have been submitted to DataBricks
+func (p *plugin) getRun(name string) (*databricksv1alpha1.Run, error) {
+	run := &databricksv1alpha1.Run{}
+	if err := p.client.Get(context.Background(), p.key(name), run); err != nil {
+		return nil, err
+	}
+	if !run.IsSubmitted() {
+		return nil, fmt.Errorf("run %s is not submitted yet", name)
+	}
+	return run, nil
+}
+
+func (p *plugin) getRunOutput(runID int64) (jobsapi.RunOutput, error) {
+	if err := p.initDatabricks(); err != nil {
+		return jobsapi.RunOutput{}, err
+	}
+	return p.jobs.RunsGetOutput(runID)
+}
+
+// initDatabricks reads the DataBricks host and token from the environment,
+// or from the dbrickssettings secret the operator reads them from
+func (p *plugin) initDatabricks() error {
+	if p.jobs != nil {
+		return nil
+	}
+	host, token := os.Getenv("DATABRICKS_HOST"), os.Getenv("DATABRICKS_TOKEN")
+	if host == "" || token == "" {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: p.operatorNamespace, Name: "dbrickssettings"}
+		if err := p.client.Get(context.Background(), key, secret); err != nil {
+			return fmt.Errorf("error when reading DataBricks settings, set DATABRICKS_HOST and DATABRICKS_TOKEN: %v", err)
+		}
+		host, token = string(secret.Data["DatabricksHost"]), string(secret.Data["DatabricksToken"])
+	}
+	p.jobs = jobsapi.NewClient(db.DBClientOption{Host: host, Token: token})
+	return nil
+}
+
+// formatState returns the life cycle state of a run followed by its result state and message
+func formatState(state *dbmodels.RunState) string {
+	if state == nil || state.LifeCycleState == nil {
+		return "UNKNOWN"
+	}
+	formatted := string(*state.LifeCycleState)
+	if state.ResultState != nil {
+		formatted += " " + string(*state.ResultState)
+	}
+	if state.StateMessage != "" {
+		formatted += ": " + state.StateMessage
+	}
+	return formatted
+}
@@ -0,0 +1,234 @@
+/*
+The MIT License (MIT)
+
+Copyright (c) 2019  Microsoft
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	databricksv1alpha1 "github.com/microsoft/azure-databricks-operator/api/v1alpha1"
+	"github.com/microsoft/azure-databricks-operator/controllers/jobsapi"
+	"github.com/stretchr/testify/assert"
+	dbazure "github.com/xinsnake/databricks-sdk-golang/azure"
+	dbmodels "github.com/xinsnake/databricks-sdk-golang/azure/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestPlugin(t *testing.T, lifeCycleState dbmodels.RunLifeCycleState, objects ...runtime.Object) (*plugin, *bytes.Buffer, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/2.1/jobs/runs/get-output", r.URL.Path)
+		assert.Equal(t, "7", r.URL.Query().Get("run_id"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		failed := dbmodels.RunResultState(dbmodels.RunResultStateFailed)
+		_ = json.NewEncoder(w).Encode(jobsapi.RunOutput{
+			JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				NotebookOutput: dbmodels.NotebookOutput{Result: "whole result"},
+				Error:          "notebook failed",
+				Metadata: dbmodels.Run{RunID: 7, State: &dbmodels.RunState{
+					LifeCycleState: &lifeCycleState, ResultState: &failed,
+				}},
+			},
+			ErrorTrace: "Traceback",
+		})
+	}))
+
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+	_ = databricksv1alpha1.AddToScheme(s)
+	settings := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dbrickssettings", Namespace: defaultOperatorNamespace},
+		Data:       map[string][]byte{"DatabricksHost": []byte(server.URL), "DatabricksToken": []byte("token")},
+	}
+	out := &bytes.Buffer{}
+	p := &plugin{
+		client:            fake.NewFakeClientWithScheme(s, append(objects, settings)...),
+		namespace:         "default",
+		operatorNamespace: defaultOperatorNamespace,
+		out:               out,
+	}
+	return p, out, server.Close
+}
+
+func newTestRun(lifeCycleState dbmodels.RunLifeCycleState) *databricksv1alpha1.Run {
+	return &databricksv1alpha1.Run{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default"},
+		Spec:       &databricksv1alpha1.RunSpec{},
+		Status: &databricksv1alpha1.RunStatus{
+			JobsRunsGetOutputResponse: dbazure.JobsRunsGetOutputResponse{
+				Metadata: dbmodels.Run{JobID: 1, RunID: 7, State: &dbmodels.RunState{LifeCycleState: &lifeCycleState}},
+			},
+			RunPageURL: "https://workspace/#job/1/run/7",
+		},
+	}
+}
+
+func TestPlugin_Logs(t *testing.T) {
+	// Arrange
+	terminated := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+	p, out, closeServer := newTestPlugin(t, terminated, newTestRun(terminated))
+	defer closeServer()
+
+	// Act
+	err := p.logs("report", true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "TERMINATED FAILED\n")
+	assert.Contains(t, out.String(), "Error: notebook failed\nTraceback\n")
+}
+
+func TestPlugin_Output(t *testing.T) {
+	// Arrange
+	terminated := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+	p, out, closeServer := newTestPlugin(t, terminated, newTestRun(terminated))
+	defer closeServer()
+
+	// Act
+	err := p.output("report")
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "whole result\n", out.String())
+}
+
+func TestPlugin_CancelAndRerun(t *testing.T) {
+	// Arrange
+	running := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateRunning)
+	p, _, closeServer := newTestPlugin(t, running, newTestRun(running))
+	defer closeServer()
+
+	// Act
+	cancelErr := p.cancel("report")
+	rerunErr := p.rerun("report")
+
+	// Assert
+	assert.Nil(t, cancelErr)
+	assert.EqualError(t, rerunErr, "run report has not terminated")
+	run := &databricksv1alpha1.Run{}
+	assert.Nil(t, p.client.Get(context.Background(), p.key("report"), run))
+	assert.True(t, run.Spec.Cancel)
+	assert.Equal(t, int32(0), run.Spec.Attempt)
+}
+
+func TestPlugin_Rerun(t *testing.T) {
+	// Arrange
+	terminated := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+	p, _, closeServer := newTestPlugin(t, terminated, newTestRun(terminated))
+	defer closeServer()
+
+	// Act
+	err := p.rerun("report")
+
+	// Assert
+	assert.Nil(t, err)
+	run := &databricksv1alpha1.Run{}
+	assert.Nil(t, p.client.Get(context.Background(), p.key("report"), run))
+	assert.Equal(t, int32(1), run.Spec.Attempt)
+}
+
+func TestPlugin_RerunInvalid(t *testing.T) {
+	// Arrange
+	terminated := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateTerminated)
+	noSpec := newTestRun(terminated)
+	noSpec.Name = "no-spec"
+	noSpec.Spec = nil
+	mirrored := newTestRun(terminated)
+	mirrored.Name = "mirrored"
+	mirrored.Spec.MirrorRunID = 7
+	p, _, closeServer := newTestPlugin(t, terminated, noSpec, mirrored)
+	defer closeServer()
+
+	// Act
+	noSpecErr := p.rerun("no-spec")
+	mirroredErr := p.rerun("mirrored")
+
+	// Assert
+	assert.EqualError(t, noSpecErr, "run no-spec has no spec")
+	assert.EqualError(t, mirroredErr, "run mirrored mirrors DataBricks run 7 and cannot be rerun")
+	run := &databricksv1alpha1.Run{}
+	assert.Nil(t, p.client.Get(context.Background(), p.key("mirrored"), run))
+	assert.Equal(t, int32(0), run.Spec.Attempt)
+}
+
+func TestPlugin_RunNow(t *testing.T) {
+	// Arrange
+	job := &databricksv1alpha1.Djob{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"},
+		Spec:       &databricksv1alpha1.JobSettings{RunNow: &databricksv1alpha1.JobRunNow{Trigger: 3}},
+	}
+	p, out, closeServer := newTestPlugin(t, dbmodels.RunLifeCycleStatePending, job)
+	defer closeServer()
+
+	// Act
+	err := p.runNow("nightly", parameterFlag{"date": "2020-01-01"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "djob/nightly triggered run/nightly-run-4\n", out.String())
+	fetched := &databricksv1alpha1.Djob{}
+	assert.Nil(t, p.client.Get(context.Background(), p.key("nightly"), fetched))
+	assert.Equal(t, int32(4), fetched.Spec.RunNow.Trigger)
+	assert.Equal(t, map[string]string{"date": "2020-01-01"}, fetched.Spec.RunNow.Parameters.NotebookParams)
+}
+
+func TestPlugin_Open(t *testing.T) {
+	// Arrange
+	running := dbmodels.RunLifeCycleState(dbmodels.RunLifeCycleStateRunning)
+	p, out, closeServer := newTestPlugin(t, running, newTestRun(running))
+	defer closeServer()
+
+	// Act
+	err := p.open("report", true)
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, "https://workspace/#job/1/run/7\n", out.String())
+}
+
+func TestParseArgs(t *testing.T) {
+	// Arrange
+	var follow bool
+	parameters := parameterFlag{}
+	flags := flag.NewFlagSet("logs", flag.ContinueOnError)
+	flags.BoolVar(&follow, "f", false, "")
+	flags.Var(parameters, "p", "")
+
+	// Act
+	names, err := parseArgs(flags, []string{"report", "-f", "-p", "a=b=c"})
+
+	// Assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"report"}, names)
+	assert.True(t, follow)
+	assert.Equal(t, parameterFlag{"a": "b=c"}, parameters)
+}